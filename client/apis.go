@@ -26,6 +26,7 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/cluster"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/version"
 )
@@ -35,6 +36,7 @@ const (
 	apiDumpURL     = "/v4/default/admin/dump"
 	apiClustersURL = "/v4/default/admin/clusters"
 	apiHealthURL   = "/v4/default/registry/health"
+	apiMigrateURL  = "/v4/default/admin/migrate"
 
 	QueryGlobal util.CtxKey = "global"
 )
@@ -159,3 +161,34 @@ func (c *Client) HealthCheck(ctx context.Context) *errsvc.Error {
 	}
 	return nil
 }
+
+func (c *Client) Migrate(ctx context.Context, request *migrate.Request) (*migrate.Report, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost, apiMigrateURL, headers, reqBody)
+	if err != nil {
+		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	migrateResp := &migrate.Response{}
+	err = json.Unmarshal(body, migrateResp)
+	if err != nil {
+		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	return migrateResp.Report, nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+)
+
+const apiBrokerMatrixURL = "/matrix"
+
+// GetBrokerMatrix returns whether the consumer version can be deployed, with
+// the verification results of its pacts
+func (c *Client) GetBrokerMatrix(ctx context.Context, request *brokerpb.MatrixRequest) (*brokerpb.MatrixResponse, *errsvc.Error) {
+	query := url.Values{}
+	query.Set("appId", request.AppId)
+	query.Set("serviceName", request.ServiceName)
+	query.Set("version", request.Version)
+	query.Set("environment", request.Environment)
+	query.Set("tag", request.Tag)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiBrokerMatrixURL+"?"+query.Encode(),
+		c.CommonHeaders(ctx), nil)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	matrix := &brokerpb.MatrixResponse{}
+	err = json.Unmarshal(body, matrix)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return matrix, nil
+}
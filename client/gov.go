@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+const (
+	apiGovExportURL = "/v1/%s/gov/export"
+	apiGovApplyURL  = "/v1/%s/gov/apply"
+)
+
+// ExportGov returns the governance policies of the project as a YAML bundle
+func (c *Client) ExportGov(ctx context.Context, project, app, env string) ([]byte, *errsvc.Error) {
+	query := url.Values{}
+	query.Set("app", app)
+	query.Set("environment", env)
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiGovExportURL, project)+"?"+query.Encode(),
+		c.CommonHeaders(ctx), nil)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+	return body, nil
+}
+
+// ApplyGov reconciles the governance policies of the project with the YAML
+// bundle, only the plan is returned in a dry run
+func (c *Client) ApplyGov(ctx context.Context, project, app, env string, bundle []byte, dryRun bool) (*gov.Plan, *errsvc.Error) {
+	query := url.Values{}
+	query.Set("app", app)
+	query.Set("environment", env)
+	query.Set("dryRun", strconv.FormatBool(dryRun))
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiGovApplyURL, project)+"?"+query.Encode(),
+		c.CommonHeaders(ctx), bundle)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	plan := &gov.Plan{}
+	err = json.Unmarshal(body, plan)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return plan, nil
+}
@@ -46,7 +46,8 @@ type AccountManager interface {
 	CreateAccount(ctx context.Context, a *rbac.Account) error
 	AccountExist(ctx context.Context, name string) (bool, error)
 	GetAccount(ctx context.Context, name string) (*rbac.Account, error)
-	ListAccount(ctx context.Context) ([]*rbac.Account, int64, error)
+	// ListAccount returns the page of accounts and the total count
+	ListAccount(ctx context.Context, opts ...ListOption) ([]*rbac.Account, int64, error)
 	DeleteAccount(ctx context.Context, names []string) (bool, error)
 	UpdateAccount(ctx context.Context, name string, account *rbac.Account) error
 }
@@ -54,6 +55,7 @@ type AccountManager interface {
 // AccountLockManager saves login failure status
 type AccountLockManager interface {
 	GetLock(ctx context.Context, key string) (*AccountLock, error)
+	ListLock(ctx context.Context) ([]*AccountLock, int64, error)
 	DeleteLock(ctx context.Context, key string) error
 	Ban(ctx context.Context, key string) error
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/go-chassis/cari/rbac"
 
 	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,6 +72,16 @@ func TestAccount(t *testing.T) {
 		_, err = datasource.GetAccountManager().DeleteAccount(context.Background(), []string{a1.Name})
 		assert.NoError(t, err)
 	})
+	t.Run("add account with hashed password, should keep the password", func(t *testing.T) {
+		a := rbac.Account{Name: "test-account-hashed", Password: "$s0$hashed"}
+		err := datasource.GetAccountManager().CreateAccount(util.WithHashedPassword(context.Background()), &a)
+		assert.NoError(t, err)
+		r, err := datasource.GetAccountManager().GetAccount(context.Background(), a.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, "$s0$hashed", r.Password)
+		_, err = datasource.GetAccountManager().DeleteAccount(context.Background(), []string{a.Name})
+		assert.NoError(t, err)
+	})
 	t.Run("delete account", func(t *testing.T) {
 		err := datasource.GetAccountManager().CreateAccount(context.Background(), &a2)
 		assert.NoError(t, err)
@@ -145,6 +156,19 @@ func TestAccountLock(t *testing.T) {
 		assert.Less(t, lock1.ReleaseAt, lock2.ReleaseAt)
 	})
 
+	t.Run("list account locks, should contain TestAccountLock", func(t *testing.T) {
+		locks, n, err := datasource.GetAccountLockManager().ListLock(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(locks)), n)
+		found := false
+		for _, lock := range locks {
+			if lock.Key == "TestAccountLock" {
+				found = true
+			}
+		}
+		assert.True(t, found)
+	})
+
 	t.Run("delete account lock, should return no error", func(t *testing.T) {
 		err := datasource.GetAccountLockManager().DeleteLock(context.Background(), "TestAccountLock")
 		assert.NoError(t, err)
@@ -0,0 +1,33 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+)
+
+// AuditLogManager saves the audit records, they are shared by the replicas
+type AuditLogManager interface {
+	AddAuditLog(ctx context.Context, r *audit.Record) error
+	// ListAuditLog returns the latest records matching the request, and
+	// the number of them. It stops once the limit is reached, so the rest
+	// of the matched ones are not counted
+	ListAuditLog(ctx context.Context, req *audit.Request) ([]*audit.Record, int64, error)
+}
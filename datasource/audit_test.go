@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	base := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	expireAt := time.Now().Add(time.Hour).Unix()
+	for i := 0; i < 5; i++ {
+		actor := "datasource_audit"
+		if i%2 == 1 {
+			actor = "datasource_audit_other"
+		}
+		err := datasource.GetAuditLogManager().AddAuditLog(getContext(), &audit.Record{
+			ID:        "datasource_audit" + string(rune('0'+i)),
+			Timestamp: base + int64(i),
+			Actor:     actor,
+			Resource:  "service",
+			ExpireAt:  expireAt,
+		})
+		assert.NoError(t, err)
+	}
+
+	t.Run("add an expired record, should be dropped", func(t *testing.T) {
+		err := datasource.GetAuditLogManager().AddAuditLog(getContext(), &audit.Record{
+			ID: "datasource_audit_expired", Timestamp: base + 5, Actor: "datasource_audit",
+			ExpireAt: time.Now().Add(-time.Second).Unix(),
+		})
+		assert.NoError(t, err)
+		records, _, err := datasource.GetAuditLogManager().ListAuditLog(getContext(), &audit.Request{
+			Start: base + 5, End: base + 6})
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+	t.Run("list with limit, should stop at the latest matched ones", func(t *testing.T) {
+		records, total, err := datasource.GetAuditLogManager().ListAuditLog(getContext(), &audit.Request{
+			Start: base, End: base + 5, Actor: "datasource_audit", Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		if assert.Equal(t, 2, len(records)) {
+			assert.Equal(t, "datasource_audit4", records[0].ID)
+			assert.Equal(t, "datasource_audit2", records[1].ID)
+		}
+
+		records, total, err = datasource.GetAuditLogManager().ListAuditLog(getContext(), &audit.Request{
+			Start: base, End: base + 5, Actor: "datasource_audit", Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, "datasource_audit0", records[2].ID)
+	})
+}
@@ -22,7 +22,14 @@ type DataSource interface {
 	SystemManager() SystemManager
 	AccountManager() AccountManager
 	AccountLockManager() AccountLockManager
+	APIKeyManager() APIKeyManager
+	TokenManager() TokenManager
+	PasswordHistoryManager() PasswordHistoryManager
 	RoleManager() RoleManager
+	QuotaManager() QuotaManager
+	AuditLogManager() AuditLogManager
+	GovPolicyManager() GovPolicyManager
+	GovRevisionManager() GovRevisionManager
 	DependencyManager() DependencyManager
 	MetadataManager() MetadataManager
 	SCManager() SCManager
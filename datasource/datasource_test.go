@@ -22,3 +22,4 @@ import _ "github.com/apache/servicecomb-service-center/test"
 // Run The UT-TEST By Set The TEST_MODE ENV
 // Mongo: TEST_MODE=mongo.
 // ETCD: TEST_MODE=etcd.
+// Local: TEST_MODE=local.
@@ -28,6 +28,9 @@ type DependencyManager interface {
 	SearchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetProDependenciesResponse, error)
 	SearchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error)
 	AddOrUpdateDependencies(ctx context.Context, dependencyInfos []*pb.ConsumerDependency, override bool) (*pb.Response, error)
+	// GetConsumerDependencyRules returns the provider rules of the consumer
+	// as they are declared, the version rules are not resolved
+	GetConsumerDependencyRules(ctx context.Context, consumer *pb.MicroServiceKey) ([]*pb.MicroServiceKey, error)
 	DeleteDependency()
 	DependencyHandle(ctx context.Context) error
 }
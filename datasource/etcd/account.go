@@ -56,10 +56,12 @@ func (ds *AccountManager) CreateAccount(ctx context.Context, a *rbac.Account) er
 	if exist {
 		return datasource.ErrAccountDuplicated
 	}
-	a.Password, err = privacy.ScryptPassword(a.Password)
-	if err != nil {
-		log.Error("pwd hash failed", err)
-		return err
+	if !util.HashedPassword(ctx) {
+		a.Password, err = privacy.ScryptPassword(a.Password)
+		if err != nil {
+			log.Error("pwd hash failed", err)
+			return err
+		}
 	}
 	a.Role = ""
 	a.CurrentPassword = ""
@@ -144,7 +146,7 @@ func (ds *AccountManager) compatibleOldVersionAccount(a *rbac.Account) {
 	a.Role = ""
 }
 
-func (ds *AccountManager) ListAccount(ctx context.Context) ([]*rbac.Account, int64, error) {
+func (ds *AccountManager) ListAccount(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Account, int64, error) {
 	resp, err := client.Instance().Do(ctx, client.GET,
 		client.WithStrKey(path.GenerateRBACAccountKey("")), client.WithPrefix())
 	if err != nil {
@@ -162,6 +164,10 @@ func (ds *AccountManager) ListAccount(ctx context.Context) ([]*rbac.Account, int
 		ds.compatibleOldVersionAccount(a)
 		accounts = append(accounts, a)
 	}
+	accounts, err = datasource.PageAccounts(accounts, datasource.ToListOptions(opts...))
+	if err != nil {
+		return nil, 0, err
+	}
 	return accounts, resp.Count, nil
 }
 func (ds *AccountManager) DeleteAccount(ctx context.Context, names []string) (bool, error) {
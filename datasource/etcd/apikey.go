@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type APIKeyManager struct {
+}
+
+// CreateKey saves the key under its account and the account under the id
+func (km *APIKeyManager) CreateKey(ctx context.Context, key *apikey.APIKey) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		log.Error("api key is invalid", err)
+		return err
+	}
+	idKey := path.GenerateAPIKeyIDKey(key.ID)
+	resp, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(idKey), client.WithStrValue(key.Account)),
+		client.OpPut(client.WithStrKey(path.GenerateAPIKeyKey(key.Account, key.ID)), client.WithValue(value)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrCreateRev(idKey), client.CmpEqual, 0),
+	}, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save api key %s of account %s", key.ID, key.Account), err)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrAPIKeyDuplicated
+	}
+	return nil
+}
+
+func (km *APIKeyManager) GetKey(ctx context.Context, id string) (*apikey.APIKey, error) {
+	account, err := km.getAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	key := path.GenerateAPIKeyKey(account, id)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrAPIKeyNotExist
+	}
+	k := &apikey.APIKey{}
+	err = json.Unmarshal(resp.Kvs[0].Value, k)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return nil, err
+	}
+	return k, nil
+}
+
+// getAccount returns the account of the key id
+func (km *APIKeyManager) getAccount(ctx context.Context, id string) (string, error) {
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(path.GenerateAPIKeyIDKey(id)))
+	if err != nil {
+		return "", err
+	}
+	if resp.Count == 0 {
+		return "", datasource.ErrAPIKeyNotExist
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+func (km *APIKeyManager) ListKey(ctx context.Context, account string) ([]*apikey.APIKey, error) {
+	kvs, _, err := client.List(ctx, path.GenerateAPIKeyKey(account, ""))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*apikey.APIKey, 0, len(kvs))
+	for _, v := range kvs {
+		k := &apikey.APIKey{}
+		err = json.Unmarshal(v.Value, k)
+		if err != nil {
+			log.Error(fmt.Sprintf("key %s format invalid", v.Key), err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// UpdateKey replaces the key if it is not deleted
+func (km *APIKeyManager) UpdateKey(ctx context.Context, key *apikey.APIKey) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		log.Error("api key is invalid", err)
+		return err
+	}
+	resp, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(path.GenerateAPIKeyKey(key.Account, key.ID)), client.WithValue(value)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrVal(path.GenerateAPIKeyIDKey(key.ID)), client.CmpEqual, key.Account),
+	}, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not update api key %s of account %s", key.ID, key.Account), err)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrAPIKeyNotExist
+	}
+	return nil
+}
+
+func (km *APIKeyManager) DeleteKey(ctx context.Context, id string) error {
+	account, err := km.getAccount(ctx, id)
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			return nil
+		}
+		return err
+	}
+	_, err = client.Instance().Txn(ctx, []client.PluginOp{
+		client.OpDel(client.WithStrKey(path.GenerateAPIKeyKey(account, id))),
+		client.OpDel(client.WithStrKey(path.GenerateAPIKeyIDKey(id))),
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove api key %s", id), err)
+		return err
+	}
+	return nil
+}
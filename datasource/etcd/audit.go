@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const (
+	// auditLeaseBucket is the period of the records sharing one lease, so a
+	// record may be kept at most one bucket longer than the retention
+	auditLeaseBucket int64 = 60
+	// auditPageSize is the number of the records read at a time when listing
+	auditPageSize = 500
+)
+
+type AuditLogManager struct {
+	lock sync.Mutex
+	// leases maps the end of the bucket to the lease expiring at that time
+	leases map[int64]int64
+}
+
+// AddAuditLog saves the record with the lease of its expiring bucket, it
+// is removed by etcd after the retention
+func (am *AuditLogManager) AddAuditLog(ctx context.Context, r *audit.Record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		log.Error("audit record is invalid", err)
+		return err
+	}
+	opts := []client.PluginOpOption{client.PUT,
+		client.WithStrKey(path.GenerateAuditLogKey(r.Timestamp, r.ID)), client.WithValue(value)}
+	var bucket int64
+	if r.ExpireAt > 0 {
+		now := time.Now().Unix()
+		if r.ExpireAt <= now {
+			return nil
+		}
+		bucket = (r.ExpireAt/auditLeaseBucket + 1) * auditLeaseBucket
+		leaseID, err := am.lease(ctx, bucket, now)
+		if err != nil {
+			log.Error(fmt.Sprintf("can not grant lease for the audit record %s", r.ID), err)
+			return err
+		}
+		opts = append(opts, client.WithLease(leaseID))
+	}
+	_, err = client.Instance().Do(ctx, opts...)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save the audit record %s", r.ID), err)
+		if bucket > 0 {
+			// the lease may be revoked, grant a new one next time
+			am.forget(bucket)
+		}
+		return err
+	}
+	return nil
+}
+
+// lease returns the lease expiring at the end of the bucket, it is granted
+// once and shared by all the records in the bucket
+func (am *AuditLogManager) lease(ctx context.Context, bucket, now int64) (int64, error) {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+	if am.leases == nil {
+		am.leases = make(map[int64]int64)
+	}
+	if leaseID, ok := am.leases[bucket]; ok {
+		return leaseID, nil
+	}
+	for end := range am.leases {
+		if end <= now {
+			delete(am.leases, end)
+		}
+	}
+	leaseID, err := client.Instance().LeaseGrant(ctx, bucket-now)
+	if err != nil {
+		return 0, err
+	}
+	am.leases[bucket] = leaseID
+	return leaseID, nil
+}
+
+func (am *AuditLogManager) forget(bucket int64) {
+	am.lock.Lock()
+	delete(am.leases, bucket)
+	am.lock.Unlock()
+}
+
+// ListAuditLog lists the keys in time descending order first, then reads
+// the values by pages and stops once the limit of the matched records is
+// reached. The actor is not a part of the key, so it is filtered here
+func (am *AuditLogManager) ListAuditLog(ctx context.Context, req *audit.Request) ([]*audit.Record, int64, error) {
+	end := req.End
+	if end <= 0 {
+		end = math.MaxInt64
+	}
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateAuditLogKey(req.Start, "")),
+		client.WithStrEndKey(path.GenerateAuditLogKey(end, "")),
+		client.WithDescendOrder(),
+		client.WithKeyOnly())
+	if err != nil {
+		return nil, 0, err
+	}
+	var records []*audit.Record
+	for i := 0; i < len(resp.Kvs); i += auditPageSize {
+		last := i + auditPageSize
+		if last > len(resp.Kvs) {
+			last = len(resp.Kvs)
+		}
+		page, err := client.Instance().Do(ctx, client.GET,
+			client.WithKey(resp.Kvs[last-1].Key),
+			client.WithStrEndKey(string(resp.Kvs[i].Key)+"\x00"),
+			client.WithDescendOrder())
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, kv := range page.Kvs {
+			r := &audit.Record{}
+			err = json.Unmarshal(kv.Value, r)
+			if err != nil {
+				log.Error(fmt.Sprintf("key %s format invalid", kv.Key), err)
+				continue
+			}
+			if !req.Match(r) {
+				continue
+			}
+			records = append(records, r)
+			if req.Limit > 0 && int64(len(records)) >= req.Limit {
+				return records, int64(len(records)), nil
+			}
+		}
+	}
+	return records, int64(len(records)), nil
+}
@@ -29,6 +29,9 @@ import (
 	//registry etcd
 	_ "github.com/apache/servicecomb-service-center/datasource/etcd/client/embedded"
 
+	//registry bbolt file
+	_ "github.com/apache/servicecomb-service-center/datasource/etcd/client/local"
+
 	//discovery
 	_ "github.com/apache/servicecomb-service-center/datasource/etcd/sd/aggregate"
 
@@ -171,6 +171,9 @@ func (s *EtcdEmbed) toCompares(cmps []client.CompareOp) []*etcdserverpb.Compare
 		compare := &etcdserverpb.Compare{
 			Key: cmp.Key,
 		}
+		if cmp.Prefix {
+			compare.RangeEnd = s.getPrefixEndKey(cmp.Key)
+		}
 		switch cmp.Type {
 		case client.CmpVersion:
 			var version int64
@@ -0,0 +1,649 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package local is a standalone registry client plugin, it stores the
+// kvs and leases in a single bbolt file and does not depend on any
+// external etcd or mongo server
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	DefaultPath        = "data/registry.db"
+	DefaultHistorySize = 10000
+	// the interval of checking the expired leases
+	leaseCheckInterval = 500 * time.Millisecond
+	openTimeout        = 10 * time.Second
+	watchChanSize      = 1024
+)
+
+var (
+	bucketKv    = []byte("kvs")
+	bucketLease = []byte("leases")
+	bucketMeta  = []byte("meta")
+	keyRevision = []byte("revision")
+
+	ErrCompacted     = errors.New("required revision has been compacted")
+	ErrWatcherClosed = errors.New("watcher is closed by the slow consumer")
+	ErrClosed        = errors.New("local registry is closed")
+
+	instances     = make(map[string]*Registry)
+	instancesLock sync.Mutex
+)
+
+func init() {
+	client.Install("local", NewRegistry)
+}
+
+type leaseRecord struct {
+	ID  int64 `json:"id"`
+	TTL int64 `json:"ttl"`
+}
+
+type lease struct {
+	leaseRecord
+	deadline time.Time
+	keys     map[string]struct{}
+}
+
+// Registry implements the client.Registry on a bbolt file
+type Registry struct {
+	DB *bolt.DB
+
+	// openErr is the error of opening the file, every call fails with it
+	openErr error
+
+	path      string
+	err       chan error
+	ready     chan struct{}
+	goroutine *gopool.Pool
+
+	// lock guards the following fields, and serializes all the writes
+	// to make sure the watchers receive the events in revision order
+	lock        sync.Mutex
+	rev         int64
+	leases      map[int64]*lease
+	history     []mvccpb.Event
+	historySize int
+	watchers    map[*watcher]struct{}
+}
+
+func (s *Registry) Err() <-chan error {
+	return s.err
+}
+
+func (s *Registry) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *Registry) Close() {
+	instancesLock.Lock()
+	if instances[s.path] == s {
+		delete(instances, s.path)
+	}
+	instancesLock.Unlock()
+
+	s.goroutine.Close(true)
+
+	s.lock.Lock()
+	for w := range s.watchers {
+		w.close(ErrClosed)
+	}
+	s.lock.Unlock()
+
+	if s.DB != nil {
+		if err := s.DB.Close(); err != nil {
+			log.Error("close local registry failed", err)
+		}
+	}
+	log.Debugf("local registry client stopped")
+}
+
+func (s *Registry) PutNoOverride(ctx context.Context, opts ...client.PluginOpOption) (bool, error) {
+	op := client.OpPut(opts...)
+	resp, err := s.TxnWithCmp(ctx, []client.PluginOp{op}, []client.CompareOp{
+		client.OpCmp(client.CmpCreateRev(op.Key), client.CmpEqual, 0),
+	}, nil)
+	if err != nil {
+		log.Errorf(err, "PutNoOverride %s failed", op.Key)
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (s *Registry) Do(ctx context.Context, opts ...client.PluginOpOption) (*client.PluginResponse, error) {
+	if s.openErr != nil {
+		return nil, s.openErr
+	}
+	op := client.OptionsToOp(opts...)
+	if op.Action == client.ActionGet {
+		var resp *client.PluginResponse
+		err := s.DB.View(func(tx *bolt.Tx) error {
+			resp = s.rangeKvs(tx, op)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.Succeeded = true
+		return resp, nil
+	}
+
+	resp, err := s.TxnWithCmp(ctx, []client.PluginOp{op}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &client.PluginResponse{
+		Revision:  resp.Revision,
+		Succeeded: true,
+	}, nil
+}
+
+func (s *Registry) Txn(ctx context.Context, opts []client.PluginOp) (*client.PluginResponse, error) {
+	resp, err := s.TxnWithCmp(ctx, opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &client.PluginResponse{
+		Succeeded: resp.Succeeded,
+		Revision:  resp.Revision,
+	}, nil
+}
+
+func (s *Registry) TxnWithCmp(ctx context.Context, success []client.PluginOp, cmps []client.CompareOp,
+	fail []client.PluginOp) (*client.PluginResponse, error) {
+	if s.openErr != nil {
+		return nil, s.openErr
+	}
+	resp := &client.PluginResponse{}
+	err := s.update(func(t *txn) error {
+		resp.Succeeded = t.compare(cmps)
+		ops := success
+		if !resp.Succeeded {
+			ops = fail
+		}
+		for _, op := range ops {
+			switch op.Action {
+			case client.ActionGet:
+				rangeResp := s.rangeKvs(t.tx, op)
+				resp.Kvs = append(resp.Kvs, rangeResp.Kvs...)
+				resp.Count += rangeResp.Count
+			case client.ActionPut:
+				if err := t.put(op); err != nil {
+					return err
+				}
+			case client.ActionDelete:
+				if err := t.delete(op); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err == rpctypes.ErrKeyNotFound {
+			// the same as etcd, return ErrKeyNotFound if key does not exist and
+			// the PUT options contain WithIgnoreLease
+			return &client.PluginResponse{Succeeded: false}, nil
+		}
+		return nil, err
+	}
+	resp.Revision = s.Revision()
+	return resp, nil
+}
+
+func (s *Registry) LeaseGrant(ctx context.Context, TTL int64) (int64, error) {
+	if s.openErr != nil {
+		return 0, s.openErr
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var leaseID int64
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketLease)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		leaseID = int64(seq)
+		data, err := json.Marshal(&leaseRecord{ID: leaseID, TTL: TTL})
+		if err != nil {
+			return err
+		}
+		return b.Put(leaseIDToBytes(leaseID), data)
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.leases[leaseID] = &lease{
+		leaseRecord: leaseRecord{ID: leaseID, TTL: TTL},
+		deadline:    time.Now().Add(time.Duration(TTL) * time.Second),
+		keys:        make(map[string]struct{}),
+	}
+	return leaseID, nil
+}
+
+func (s *Registry) LeaseRenew(ctx context.Context, leaseID int64) (int64, error) {
+	if s.openErr != nil {
+		return 0, s.openErr
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	l, ok := s.leases[leaseID]
+	if !ok {
+		return 0, rpctypes.ErrLeaseNotFound
+	}
+	l.deadline = time.Now().Add(time.Duration(l.TTL) * time.Second)
+	return l.TTL, nil
+}
+
+func (s *Registry) LeaseRevoke(ctx context.Context, leaseID int64) error {
+	if s.openErr != nil {
+		return s.openErr
+	}
+	s.lock.Lock()
+	_, ok := s.leases[leaseID]
+	s.lock.Unlock()
+	if !ok {
+		return rpctypes.ErrLeaseNotFound
+	}
+	return s.update(func(t *txn) error {
+		return t.revoke(leaseID)
+	})
+}
+
+// Watch only supports to watch the changes from the revisions still in the
+// history, otherwise it returns ErrCompacted and the caller should list again
+func (s *Registry) Watch(ctx context.Context, opts ...client.PluginOpOption) error {
+	if s.openErr != nil {
+		return s.openErr
+	}
+	op := client.OpGet(opts...)
+	if len(op.Key) == 0 {
+		return fmt.Errorf("no key has been watched")
+	}
+
+	w := &watcher{
+		key:    op.Key,
+		end:    op.EndKey,
+		prefix: op.Prefix,
+		ch:     make(chan []mvccpb.Event, watchChanSize),
+	}
+
+	s.lock.Lock()
+	var replay []mvccpb.Event
+	if op.Revision > 0 && op.Revision <= s.rev {
+		if len(s.history) == 0 || s.history[0].Kv.ModRevision > op.Revision {
+			s.lock.Unlock()
+			return ErrCompacted
+		}
+		for _, evt := range s.history {
+			if evt.Kv.ModRevision >= op.Revision && w.match(evt.Kv.Key) {
+				replay = append(replay, evt)
+			}
+		}
+	}
+	s.watchers[w] = struct{}{}
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.watchers, w)
+		s.lock.Unlock()
+	}()
+
+	if len(replay) > 0 {
+		if err := dispatch(replay, op.WatchCallback); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evts, ok := <-w.ch:
+			if !ok {
+				return w.err
+			}
+			if err := dispatch(evts, op.WatchCallback); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Compact removes the watch history and reserves the latest 'reserve' revisions
+func (s *Registry) Compact(ctx context.Context, reserve int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	revToCompact := s.rev - reserve
+	if revToCompact <= 0 {
+		log.Infof("revision is %d, <=%d, no nead to compact", s.rev, reserve)
+		return nil
+	}
+	i := sort.Search(len(s.history), func(i int) bool {
+		return s.history[i].Kv.ModRevision > revToCompact
+	})
+	s.history = append([]mvccpb.Event(nil), s.history[i:]...)
+	log.Infof("compacted locally, revision is %d(current: %d, reserve %d)", revToCompact, s.rev, reserve)
+	return nil
+}
+
+func (s *Registry) Revision() int64 {
+	return atomic.LoadInt64(&s.rev)
+}
+
+func (s *Registry) rangeKvs(tx *bolt.Tx, op client.PluginOp) *client.PluginResponse {
+	var kvs []*mvccpb.KeyValue
+	forEachInRange(tx, op.Key, op.EndKey, op.Prefix, func(kv *mvccpb.KeyValue) {
+		kvs = append(kvs, kv)
+	})
+
+	resp := &client.PluginResponse{
+		Count:    int64(len(kvs)),
+		Revision: s.Revision(),
+	}
+	if op.CountOnly {
+		return resp
+	}
+	if op.OrderBy == client.OrderByCreate {
+		sort.SliceStable(kvs, func(i, j int) bool {
+			return kvs[i].CreateRevision < kvs[j].CreateRevision
+		})
+	}
+	if op.SortOrder == client.SortDescend {
+		for i, j := 0, len(kvs)-1; i < j; i, j = i+1, j-1 {
+			kvs[i], kvs[j] = kvs[j], kvs[i]
+		}
+	}
+	if op.Offset >= 0 && op.Limit > 0 {
+		start, end := op.Offset, op.Offset+op.Limit
+		if start > int64(len(kvs)) {
+			start = int64(len(kvs))
+		}
+		if end > int64(len(kvs)) {
+			end = int64(len(kvs))
+		}
+		kvs = kvs[start:end]
+	}
+	if op.KeyOnly {
+		for _, kv := range kvs {
+			kv.Value = nil
+		}
+	}
+	resp.Kvs = kvs
+	return resp
+}
+
+// update executes f in a write transaction, then publishes the changes to
+// the watchers with a new revision if f changed something
+func (s *Registry) update(f func(t *txn) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var t *txn
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		t = newTxn(s, tx)
+		if err := f(t); err != nil {
+			return err
+		}
+		if len(t.events) == 0 {
+			return nil
+		}
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, uint64(t.rev))
+		return tx.Bucket(bucketMeta).Put(keyRevision, buf)
+	})
+	if err != nil {
+		return err
+	}
+	t.commitLeases()
+	if len(t.events) == 0 {
+		return nil
+	}
+	atomic.StoreInt64(&s.rev, t.rev)
+	s.notify(t.events)
+	return nil
+}
+
+func (s *Registry) notify(evts []mvccpb.Event) {
+	s.history = append(s.history, evts...)
+	if over := len(s.history) - s.historySize; over > 0 {
+		s.history = append([]mvccpb.Event(nil), s.history[over:]...)
+	}
+	for w := range s.watchers {
+		w.send(evts)
+	}
+}
+
+func (s *Registry) expireLeases(ctx context.Context) {
+	ticker := time.NewTicker(leaseCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			var expired []int64
+			s.lock.Lock()
+			for id, l := range s.leases {
+				if now.After(l.deadline) {
+					expired = append(expired, id)
+				}
+			}
+			s.lock.Unlock()
+
+			for _, id := range expired {
+				err := s.update(func(t *txn) error {
+					return t.revoke(id)
+				})
+				if err != nil {
+					log.Errorf(err, "revoke expired lease[%d] failed", id)
+				}
+			}
+		}
+	}
+}
+
+func (s *Registry) open(path string) error {
+	if dir := filepath.Dir(path); len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return err
+	}
+	s.DB = db
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketKv, bucketLease, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if v := tx.Bucket(bucketMeta).Get(keyRevision); len(v) == 8 {
+			s.rev = int64(binary.BigEndian.Uint64(v))
+		}
+		// the same as etcd, all the leases are refreshed after restart
+		now := time.Now()
+		err := tx.Bucket(bucketLease).ForEach(func(k, v []byte) error {
+			var r leaseRecord
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			s.leases[r.ID] = &lease{
+				leaseRecord: r,
+				deadline:    now.Add(time.Duration(r.TTL) * time.Second),
+				keys:        make(map[string]struct{}),
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		forEachInRange(tx, nil, nil, true, func(kv *mvccpb.KeyValue) {
+			if l, ok := s.leases[kv.Lease]; ok {
+				l.keys[string(kv.Key)] = struct{}{}
+			}
+		})
+		return nil
+	})
+}
+
+func forEachInRange(tx *bolt.Tx, key, end []byte, prefix bool, f func(kv *mvccpb.KeyValue)) {
+	c := tx.Bucket(bucketKv).Cursor()
+	if !prefix && len(end) == 0 {
+		if v := tx.Bucket(bucketKv).Get(key); v != nil {
+			if kv := unmarshalKv(key, v); kv != nil {
+				f(kv)
+			}
+		}
+		return
+	}
+	for k, v := c.Seek(key); k != nil; k, v = c.Next() {
+		if prefix && !bytes.HasPrefix(k, key) {
+			break
+		}
+		if !prefix && bytes.Compare(k, end) >= 0 {
+			break
+		}
+		if kv := unmarshalKv(k, v); kv != nil {
+			f(kv)
+		}
+	}
+}
+
+func unmarshalKv(k, v []byte) *mvccpb.KeyValue {
+	kv := &mvccpb.KeyValue{}
+	if err := kv.Unmarshal(v); err != nil {
+		log.Errorf(err, "unmarshal key %s failed", k)
+		return nil
+	}
+	return kv
+}
+
+func leaseIDToBytes(id int64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(id))
+	return buf
+}
+
+func dispatch(evts []mvccpb.Event, cb client.WatchCallback) error {
+	sIdx := 0
+	for i := 1; i <= len(evts); i++ {
+		if i < len(evts) && evts[i].Type == evts[sIdx].Type &&
+			evts[i].Kv.ModRevision == evts[sIdx].Kv.ModRevision {
+			continue
+		}
+		if err := callback(evts[sIdx:i], cb); err != nil {
+			return err
+		}
+		sIdx = i
+	}
+	return nil
+}
+
+func callback(evts []mvccpb.Event, cb client.WatchCallback) error {
+	action := client.ActionPut
+	if evts[0].Type == mvccpb.DELETE {
+		action = client.ActionDelete
+	}
+	kvs := make([]*mvccpb.KeyValue, 0, len(evts))
+	for _, evt := range evts {
+		kv := evt.Kv
+		if evt.Type == mvccpb.DELETE && evt.PrevKv != nil {
+			kv = evt.PrevKv
+		}
+		kvs = append(kvs, kv)
+	}
+	return cb("key information changed", &client.PluginResponse{
+		Action:    action,
+		Kvs:       kvs,
+		Count:     int64(len(kvs)),
+		Revision:  evts[0].Kv.ModRevision,
+		Succeeded: true,
+	})
+}
+
+// NewRegistry returns the opened instance if the file is already opened in
+// this process, because bbolt does not allow to open a file twice
+func NewRegistry(opts datasource.Options) client.Registry {
+	log.Warnf("enable local registry mode")
+
+	path := config.GetString("registry.local.path", DefaultPath)
+	instancesLock.Lock()
+	defer instancesLock.Unlock()
+	if inst, ok := instances[path]; ok {
+		return inst
+	}
+
+	inst := &Registry{
+		path:        path,
+		err:         make(chan error, 1),
+		ready:       make(chan struct{}),
+		goroutine:   gopool.New(context.Background()),
+		leases:      make(map[int64]*lease),
+		watchers:    make(map[*watcher]struct{}),
+		historySize: config.GetInt("registry.local.historySize", DefaultHistorySize),
+	}
+	if inst.historySize <= 0 {
+		inst.historySize = DefaultHistorySize
+	}
+
+	if err := inst.open(path); err != nil {
+		log.Errorf(err, "open local registry file %s failed", path)
+		if inst.DB != nil {
+			inst.DB.Close()
+			inst.DB = nil
+		}
+		inst.openErr = err
+		inst.err <- err
+		return inst
+	}
+	log.Infof("local registry file %s opened, current revision is %d", path, inst.rev)
+
+	inst.goroutine.Do(inst.expireLeases)
+	instances[path] = inst
+	close(inst.ready)
+	return inst
+}
@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client/local"
+)
+
+func init() {
+	err := archaius.Init(archaius.WithMemorySource())
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newRegistry(t *testing.T, path string) client.Registry {
+	archaius.Set("registry.local.path", path)
+	inst, err := client.New(datasource.Options{Kind: "local"})
+	assert.NoError(t, err)
+	return inst
+}
+
+func TestRegistry_Do(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.db")
+	r := newRegistry(t, path)
+	ctx := context.Background()
+
+	_, err := r.Do(ctx, client.PUT, client.WithStrKey("/a/1"), client.WithStrValue("1"))
+	assert.NoError(t, err)
+	_, err = r.Do(ctx, client.PUT, client.WithStrKey("/a/2"), client.WithStrValue("2"))
+	assert.NoError(t, err)
+	_, err = r.Do(ctx, client.PUT, client.WithStrKey("/b/1"), client.WithStrValue("3"))
+	assert.NoError(t, err)
+
+	t.Run("get prefix", func(t *testing.T) {
+		resp, err := r.Do(ctx, client.GET, client.WithStrKey("/a/"), client.WithPrefix())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Count)
+		assert.Equal(t, "1", string(resp.Kvs[0].Value))
+		assert.Equal(t, int64(3), resp.Revision)
+
+		resp, err = r.Do(ctx, client.GET, client.WithStrKey("/a/"), client.WithPrefix(), client.WithDescendOrder())
+		assert.NoError(t, err)
+		assert.Equal(t, "/a/2", string(resp.Kvs[0].Key))
+
+		resp, err = r.Do(ctx, client.GET, client.WithStrKey("/a/"), client.WithPrefix(), client.WithCountOnly())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Count)
+		assert.Empty(t, resp.Kvs)
+	})
+
+	t.Run("put existing key should increase version", func(t *testing.T) {
+		_, err := r.Do(ctx, client.PUT, client.WithStrKey("/a/1"), client.WithStrValue("11"))
+		assert.NoError(t, err)
+		kv, err := getOne(r, "/a/1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), kv.Version)
+		assert.Equal(t, int64(1), kv.CreateRevision)
+		assert.Equal(t, int64(4), kv.ModRevision)
+	})
+
+	t.Run("txn with compare", func(t *testing.T) {
+		ok, err := r.PutNoOverride(ctx, client.WithStrKey("/a/1"), client.WithStrValue("x"))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = r.PutNoOverride(ctx, client.WithStrKey("/a/3"), client.WithStrValue("x"))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		resp, err := r.TxnWithCmp(ctx, []client.PluginOp{client.OpDel(client.WithStrKey("/a/3"))},
+			[]client.CompareOp{client.OpCmp(client.CmpStrVal("/a/3"), client.CmpEqual, "y")},
+			[]client.PluginOp{client.OpGet(client.WithStrKey("/a/3"))})
+		assert.NoError(t, err)
+		assert.False(t, resp.Succeeded)
+		assert.Equal(t, int64(1), resp.Count)
+
+		resp, err = r.TxnWithCmp(ctx, []client.PluginOp{client.OpPut(client.WithStrKey("/c/1"))},
+			[]client.CompareOp{client.OpCmp(client.CmpPrefix(client.CmpStrCreateRev("/a/")), client.CmpEqual, 0)}, nil)
+		assert.NoError(t, err)
+		assert.False(t, resp.Succeeded)
+
+		resp, err = r.TxnWithCmp(ctx, []client.PluginOp{client.OpDel(client.WithStrKey("/c/1"))},
+			[]client.CompareOp{client.OpCmp(client.CmpPrefix(client.CmpStrCreateRev("/c/")), client.CmpEqual, 0)}, nil)
+		assert.NoError(t, err)
+		assert.True(t, resp.Succeeded)
+	})
+
+	t.Run("delete prefix", func(t *testing.T) {
+		_, err := r.Do(ctx, client.DEL, client.WithStrKey("/a/"), client.WithPrefix())
+		assert.NoError(t, err)
+		resp, err := r.Do(ctx, client.GET, client.WithStrKey("/"), client.WithPrefix())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), resp.Count)
+	})
+
+	t.Run("reopen should keep the data", func(t *testing.T) {
+		rev := r.(*local.Registry).Revision()
+		r.Close()
+		r = newRegistry(t, path)
+		assert.Equal(t, rev, r.(*local.Registry).Revision())
+		kv, err := getOne(r, "/b/1")
+		assert.NoError(t, err)
+		assert.Equal(t, "3", string(kv.Value))
+	})
+	r.Close()
+}
+
+func TestRegistry_OpenFailed(t *testing.T) {
+	dir := t.TempDir()
+	// the parent of the file is a file, the registry can not be opened
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), nil, 0600))
+	archaius.Set("registry.local.path", filepath.Join(dir, "file", "registry.db"))
+	_, err := client.New(datasource.Options{Kind: "local"})
+	assert.Error(t, err)
+
+	r := local.NewRegistry(datasource.Options{Kind: "local"})
+	defer r.Close()
+	ctx := context.Background()
+	_, err = r.Do(ctx, client.GET, client.WithStrKey("/a"))
+	assert.Error(t, err)
+	_, err = r.Do(ctx, client.PUT, client.WithStrKey("/a"), client.WithStrValue("1"))
+	assert.Error(t, err)
+	_, err = r.LeaseGrant(ctx, 1)
+	assert.Error(t, err)
+	assert.Error(t, r.Watch(ctx, client.WithStrKey("/a")))
+}
+
+func TestRegistry_Lease(t *testing.T) {
+	r := newRegistry(t, filepath.Join(t.TempDir(), "registry.db"))
+	defer r.Close()
+	ctx := context.Background()
+
+	leaseID, err := r.LeaseGrant(ctx, 1)
+	assert.NoError(t, err)
+	_, err = r.Do(ctx, client.PUT, client.WithStrKey("/lease/1"), client.WithLease(leaseID))
+	assert.NoError(t, err)
+
+	ttl, err := r.LeaseRenew(ctx, leaseID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), ttl)
+
+	_, err = r.Do(ctx, client.PUT, client.WithStrKey("/lease/2"), client.WithLease(leaseID+1))
+	assert.Error(t, err)
+
+	time.Sleep(2 * time.Second)
+	_, err = getOne(r, "/lease/1")
+	assert.Equal(t, client.ErrNotUnique, err)
+	_, err = r.LeaseRenew(ctx, leaseID)
+	assert.Error(t, err)
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	r := newRegistry(t, filepath.Join(t.TempDir(), "registry.db"))
+	defer r.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := r.Do(ctx, client.PUT, client.WithStrKey("/w/1"), client.WithStrValue("1"))
+	assert.NoError(t, err)
+
+	events := make(chan *client.PluginResponse, 10)
+	go func() {
+		_ = r.Watch(ctx, client.WithStrKey("/w/"), client.WithPrefix(), client.WithRev(1),
+			client.WithWatchCallback(func(message string, evt *client.PluginResponse) error {
+				events <- evt
+				return nil
+			}))
+	}()
+
+	evt := <-events
+	assert.Equal(t, client.ActionPut, evt.Action)
+	assert.Equal(t, int64(1), evt.Revision)
+
+	_, err = r.Do(ctx, client.DEL, client.WithStrKey("/w/1"))
+	assert.NoError(t, err)
+	evt = <-events
+	assert.Equal(t, client.ActionDelete, evt.Action)
+	assert.Equal(t, "1", string(evt.Kvs[0].Value))
+
+	assert.NoError(t, r.Compact(ctx, 0))
+	err = r.Watch(ctx, client.WithStrKey("/w/"), client.WithPrefix(), client.WithRev(1))
+	assert.Equal(t, local.ErrCompacted, err)
+}
+
+func getOne(r client.Registry, key string) (*mvccpb.KeyValue, error) {
+	resp, err := r.Do(context.Background(), client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, client.ErrNotUnique
+	}
+	return resp.Kvs[0], nil
+}
@@ -0,0 +1,276 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"bytes"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+)
+
+type leaseChange struct {
+	key      string
+	from, to int64
+}
+
+// txn is a write transaction, all the changes in a txn share one revision
+type txn struct {
+	*Registry
+	tx  *bolt.Tx
+	rev int64
+
+	events       []mvccpb.Event
+	leaseChanges []leaseChange
+	revoked      map[int64]struct{}
+}
+
+func newTxn(s *Registry, tx *bolt.Tx) *txn {
+	return &txn{
+		Registry: s,
+		tx:       tx,
+		rev:      s.rev + 1,
+		revoked:  make(map[int64]struct{}),
+	}
+}
+
+func (t *txn) get(key []byte) *mvccpb.KeyValue {
+	v := t.tx.Bucket(bucketKv).Get(key)
+	if v == nil {
+		return nil
+	}
+	return unmarshalKv(key, v)
+}
+
+func (t *txn) leaseExist(leaseID int64) bool {
+	if _, ok := t.revoked[leaseID]; ok {
+		return false
+	}
+	_, ok := t.leases[leaseID]
+	return ok
+}
+
+func (t *txn) compare(cmps []client.CompareOp) bool {
+	for _, cmp := range cmps {
+		for _, kv := range t.compared(cmp) {
+			if !compareKv(kv, cmp) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// compared returns the kvs of the compare, a nil kv means the key or the
+// prefix does not exist
+func (t *txn) compared(cmp client.CompareOp) []*mvccpb.KeyValue {
+	if !cmp.Prefix {
+		return []*mvccpb.KeyValue{t.get(cmp.Key)}
+	}
+	var kvs []*mvccpb.KeyValue
+	forEachInRange(t.tx, cmp.Key, nil, true, func(kv *mvccpb.KeyValue) {
+		kvs = append(kvs, kv)
+	})
+	if len(kvs) == 0 {
+		return []*mvccpb.KeyValue{nil}
+	}
+	return kvs
+}
+
+func compareKv(kv *mvccpb.KeyValue, cmp client.CompareOp) bool {
+	var r int
+	switch cmp.Type {
+	case client.CmpValue:
+		v, ok := toBytes(cmp.Value)
+		if kv == nil || !ok {
+			return false
+		}
+		r = bytes.Compare(kv.Value, v)
+	default:
+		var actual int64
+		if kv != nil {
+			switch cmp.Type {
+			case client.CmpVersion:
+				actual = kv.Version
+			case client.CmpCreate:
+				actual = kv.CreateRevision
+			case client.CmpMod:
+				actual = kv.ModRevision
+			}
+		}
+		expect, ok := toInt64(cmp.Value)
+		if !ok {
+			return false
+		}
+		switch {
+		case actual < expect:
+			r = -1
+		case actual > expect:
+			r = 1
+		}
+	}
+	return compareResult(r, cmp.Result)
+}
+
+func (t *txn) put(op client.PluginOp) error {
+	prev := t.get(op.Key)
+	leaseID := op.Lease
+	if op.IgnoreLease {
+		if prev == nil {
+			return rpctypes.ErrKeyNotFound
+		}
+		leaseID = prev.Lease
+	}
+	if leaseID > 0 && !t.leaseExist(leaseID) {
+		return rpctypes.ErrLeaseNotFound
+	}
+
+	kv := &mvccpb.KeyValue{
+		Key:            op.Key,
+		Value:          op.Value,
+		CreateRevision: t.rev,
+		ModRevision:    t.rev,
+		Version:        1,
+		Lease:          leaseID,
+	}
+	var prevLease int64
+	if prev != nil {
+		kv.CreateRevision = prev.CreateRevision
+		kv.Version = prev.Version + 1
+		prevLease = prev.Lease
+	}
+	data, err := kv.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := t.tx.Bucket(bucketKv).Put(op.Key, data); err != nil {
+		return err
+	}
+
+	t.events = append(t.events, mvccpb.Event{Type: mvccpb.PUT, Kv: kv, PrevKv: prev})
+	if prevLease != leaseID {
+		t.leaseChanges = append(t.leaseChanges, leaseChange{key: string(op.Key), from: prevLease, to: leaseID})
+	}
+	return nil
+}
+
+func (t *txn) delete(op client.PluginOp) error {
+	var kvs []*mvccpb.KeyValue
+	forEachInRange(t.tx, op.Key, op.EndKey, op.Prefix, func(kv *mvccpb.KeyValue) {
+		kvs = append(kvs, kv)
+	})
+	for _, kv := range kvs {
+		if err := t.deleteKv(kv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *txn) deleteKv(prev *mvccpb.KeyValue) error {
+	if err := t.tx.Bucket(bucketKv).Delete(prev.Key); err != nil {
+		return err
+	}
+	t.events = append(t.events, mvccpb.Event{
+		Type:   mvccpb.DELETE,
+		Kv:     &mvccpb.KeyValue{Key: prev.Key, ModRevision: t.rev},
+		PrevKv: prev,
+	})
+	if prev.Lease > 0 {
+		t.leaseChanges = append(t.leaseChanges, leaseChange{key: string(prev.Key), from: prev.Lease})
+	}
+	return nil
+}
+
+// revoke deletes the lease and all the keys attached to it
+func (t *txn) revoke(leaseID int64) error {
+	l, ok := t.leases[leaseID]
+	if !ok {
+		return nil
+	}
+	for key := range l.keys {
+		kv := t.get([]byte(key))
+		if kv == nil || kv.Lease != leaseID {
+			continue
+		}
+		if err := t.deleteKv(kv); err != nil {
+			return err
+		}
+	}
+	if err := t.tx.Bucket(bucketLease).Delete(leaseIDToBytes(leaseID)); err != nil {
+		return err
+	}
+	t.revoked[leaseID] = struct{}{}
+	return nil
+}
+
+// commitLeases applies the lease changes to the memory after the txn committed
+func (t *txn) commitLeases() {
+	for _, c := range t.leaseChanges {
+		if l, ok := t.leases[c.from]; ok {
+			delete(l.keys, c.key)
+		}
+		if l, ok := t.leases[c.to]; ok {
+			l.keys[c.key] = struct{}{}
+		}
+	}
+	for leaseID := range t.revoked {
+		delete(t.leases, leaseID)
+	}
+}
+
+func compareResult(r int, result client.CompareResult) bool {
+	switch result {
+	case client.CmpEqual:
+		return r == 0
+	case client.CmpNotEqual:
+		return r != 0
+	case client.CmpGreater:
+		return r > 0
+	case client.CmpLess:
+		return r < 0
+	}
+	return false
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch i := v.(type) {
+	case nil:
+		return 0, true
+	case int:
+		return int64(i), true
+	case int32:
+		return int64(i), true
+	case int64:
+		return i, true
+	}
+	return 0, false
+}
+
+func toBytes(v interface{}) ([]byte, bool) {
+	switch b := v.(type) {
+	case []byte:
+		return b, true
+	case string:
+		return []byte(b), true
+	}
+	return nil, false
+}
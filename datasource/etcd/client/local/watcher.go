@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"bytes"
+
+	"github.com/coreos/etcd/mvcc/mvccpb"
+)
+
+// watcher receives the events of the keys in range, the methods must be
+// called with Registry.lock held
+type watcher struct {
+	key    []byte
+	end    []byte
+	prefix bool
+
+	ch     chan []mvccpb.Event
+	err    error
+	closed bool
+}
+
+func (w *watcher) match(key []byte) bool {
+	switch {
+	case w.prefix:
+		return bytes.HasPrefix(key, w.key)
+	case len(w.end) > 0:
+		return bytes.Compare(key, w.key) >= 0 && bytes.Compare(key, w.end) < 0
+	default:
+		return bytes.Equal(key, w.key)
+	}
+}
+
+func (w *watcher) send(evts []mvccpb.Event) {
+	if w.closed {
+		return
+	}
+	var matched []mvccpb.Event
+	for _, evt := range evts {
+		if w.match(evt.Kv.Key) {
+			matched = append(matched, evt)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	select {
+	case w.ch <- matched:
+	default:
+		// never block the writer, the watcher should list again
+		w.close(ErrWatcherClosed)
+	}
+}
+
+func (w *watcher) close(err error) {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	w.err = err
+	close(w.ch)
+}
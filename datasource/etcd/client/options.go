@@ -178,6 +178,9 @@ type CompareOp struct {
 	Type   CompareType
 	Result CompareResult
 	Value  interface{}
+	// Prefix compares all the keys with the prefix Key, a revision
+	// compares as 0 if none exists
+	Prefix bool
 }
 
 func (op CompareOp) String() string {
@@ -201,6 +204,11 @@ func CmpModRev(key []byte) CompareOperation {
 func CmpVal(key []byte) CompareOperation {
 	return func(op *CompareOp) { op.Key = key; op.Type = CmpValue }
 }
+
+// CmpPrefix makes the compare apply to all the keys with the prefix
+func CmpPrefix(opt CompareOperation) CompareOperation {
+	return func(op *CompareOp) { opt(op); op.Prefix = true }
+}
 func CmpStrVer(key string) CompareOperation       { return CmpVer([]byte(key)) }
 func CmpStrCreateRev(key string) CompareOperation { return CmpCreateRev([]byte(key)) }
 func CmpStrModRev(key string) CompareOperation    { return CmpModRev([]byte(key)) }
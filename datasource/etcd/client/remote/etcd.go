@@ -355,7 +355,11 @@ func (c *Client) toCompares(cmps []client.CompareOp) []clientv3.Cmp {
 		case client.CmpNotEqual:
 			cmpResult = "!="
 		}
-		etcdCmps = append(etcdCmps, clientv3.Compare(cmpType, cmpResult, cmp.Value))
+		etcdCmp := clientv3.Compare(cmpType, cmpResult, cmp.Value)
+		if cmp.Prefix {
+			etcdCmp = etcdCmp.WithPrefix()
+		}
+		etcdCmps = append(etcdCmps, etcdCmp)
 	}
 	return etcdCmps
 }
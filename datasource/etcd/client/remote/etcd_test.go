@@ -460,7 +460,7 @@ func TestEtcdClient_Txn(t *testing.T) {
 		{Action: client.ActionPut, Key: []byte("/test_txn/a"), Value: []byte("a")},
 		{Action: client.ActionPut, Key: []byte("/test_txn/b"), Value: []byte("b")},
 	}, []client.CompareOp{
-		{[]byte("/test_txn/a"), client.CmpValue, client.CmpEqual, "a"},
+		client.OpCmp(client.CmpStrVal("/test_txn/a"), client.CmpEqual, "a"),
 	}, []client.PluginOp{
 		{Action: client.ActionPut, Key: []byte("/test_txn/c"), Value: []byte("c")},
 		{Action: client.ActionPut, Key: []byte("/test_txn/d"), Value: []byte("d")},
@@ -471,7 +471,7 @@ func TestEtcdClient_Txn(t *testing.T) {
 
 	// case: range request
 	resp, err = etcd.TxnWithCmp(context.Background(), nil, []client.CompareOp{
-		{[]byte("/test_txn/c"), client.CmpValue, client.CmpEqual, "c"},
+		client.OpCmp(client.CmpStrVal("/test_txn/c"), client.CmpEqual, "c"),
 	}, []client.PluginOp{
 		{Action: client.ActionGet, Key: []byte("/test_txn/a")},
 		{Action: client.ActionGet, Key: []byte("/test_txn/"), Prefix: true},
@@ -485,7 +485,7 @@ func TestEtcdClient_Txn(t *testing.T) {
 		{Action: client.ActionPut, Key: []byte("/test_txn/a"), Value: []byte("a")},
 		{Action: client.ActionPut, Key: []byte("/test_txn/b"), Value: []byte("b")},
 	}, []client.CompareOp{
-		{[]byte("/test_txn/c"), client.CmpValue, client.CmpEqual, "c"},
+		client.OpCmp(client.CmpStrVal("/test_txn/c"), client.CmpEqual, "c"),
 	}, []client.PluginOp{
 		{Action: client.ActionDelete, Key: []byte("/test_txn/"), Prefix: true},
 	})
@@ -102,6 +102,15 @@ func (dm *DepManager) SearchConsumerDependency(ctx context.Context, request *pb.
 	}, nil
 }
 
+func (dm *DepManager) GetConsumerDependencyRules(ctx context.Context, consumer *pb.MicroServiceKey) ([]*pb.MicroServiceKey, error) {
+	key := path.GenerateConsumerDependencyRuleKey(util.ParseDomainProject(ctx), consumer)
+	dep, err := serviceUtil.TransferToMicroServiceDependency(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return dep.Dependency, nil
+}
+
 func (dm *DepManager) DeleteDependency() {
 	panic("implement me")
 }
@@ -38,13 +38,21 @@ func init() {
 	datasource.Install("etcd", NewDataSource)
 	datasource.Install("embeded_etcd", NewDataSource) //TODO remove misspell in future
 	datasource.Install("embedded_etcd", NewDataSource)
+	datasource.Install("local", NewDataSource)
 }
 
 type DataSource struct {
 	accountLockManager datasource.AccountLockManager
+	apiKeyManager      datasource.APIKeyManager
+	tokenManager       datasource.TokenManager
+	pwdHistoryManager  datasource.PasswordHistoryManager
 	accountManager     datasource.AccountManager
 	metadataManager    datasource.MetadataManager
 	roleManager        datasource.RoleManager
+	quotaManager       datasource.QuotaManager
+	auditLogManager    datasource.AuditLogManager
+	govPolicyManager   datasource.GovPolicyManager
+	govRevisionManager datasource.GovRevisionManager
 	sysManager         datasource.SystemManager
 	depManager         datasource.DependencyManager
 	scManager          datasource.SCManager
@@ -55,6 +63,34 @@ func (ds *DataSource) AccountLockManager() datasource.AccountLockManager {
 	return ds.accountLockManager
 }
 
+func (ds *DataSource) APIKeyManager() datasource.APIKeyManager {
+	return ds.apiKeyManager
+}
+
+func (ds *DataSource) TokenManager() datasource.TokenManager {
+	return ds.tokenManager
+}
+
+func (ds *DataSource) PasswordHistoryManager() datasource.PasswordHistoryManager {
+	return ds.pwdHistoryManager
+}
+
+func (ds *DataSource) QuotaManager() datasource.QuotaManager {
+	return ds.quotaManager
+}
+
+func (ds *DataSource) AuditLogManager() datasource.AuditLogManager {
+	return ds.auditLogManager
+}
+
+func (ds *DataSource) GovPolicyManager() datasource.GovPolicyManager {
+	return ds.govPolicyManager
+}
+
+func (ds *DataSource) GovRevisionManager() datasource.GovRevisionManager {
+	return ds.govRevisionManager
+}
+
 func (ds *DataSource) SystemManager() datasource.SystemManager {
 	return ds.sysManager
 }
@@ -101,6 +137,13 @@ func NewDataSource(opts datasource.Options) (datasource.DataSource, error) {
 	inst.accountManager = &AccountManager{}
 	inst.accountLockManager = NewAccountLockManager(opts.ReleaseAccountAfter)
 	inst.roleManager = &RoleManager{}
+	inst.quotaManager = &QuotaManager{}
+	inst.auditLogManager = &AuditLogManager{}
+	inst.govPolicyManager = &GovPolicyManager{}
+	inst.govRevisionManager = &GovRevisionManager{}
+	inst.apiKeyManager = &APIKeyManager{}
+	inst.tokenManager = &TokenManager{}
+	inst.pwdHistoryManager = &PasswordHistoryManager{}
 	inst.metadataManager = newMetadataManager(opts.SchemaNotEditable, opts.InstanceTTL)
 	inst.sysManager = newSysManager()
 	inst.depManager = &DepManager{}
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type GovPolicyManager struct {
+}
+
+// CreatePolicy saves the policy and its name key in one txn, the txn fails
+// if the id or the name is taken
+func (gm *GovPolicyManager) CreatePolicy(ctx context.Context, p *datasource.GovPolicy) error {
+	value, err := json.Marshal(p)
+	if err != nil {
+		log.Error("policy is invalid", err)
+		return err
+	}
+	key := path.GenerateGovPolicyKey(p.Project, p.Kind, p.ID)
+	nameKey := path.GenerateGovPolicyNameKey(p.Project, p.Kind, p.App, p.Environment, p.Name)
+	resp, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+		client.OpPut(client.WithStrKey(key), client.WithValue(value)),
+		client.OpPut(client.WithStrKey(nameKey), client.WithStrValue(p.ID)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrCreateRev(key), client.CmpEqual, 0),
+		client.OpCmp(client.CmpStrCreateRev(nameKey), client.CmpEqual, 0),
+	}, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save %s policy %s", p.Kind, p.Name), err)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrPolicyAlreadyExists
+	}
+	return nil
+}
+
+func (gm *GovPolicyManager) UpdatePolicy(ctx context.Context, p *datasource.GovPolicy) error {
+	exist, err := client.Exist(ctx, path.GenerateGovPolicyKey(p.Project, p.Kind, p.ID))
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return datasource.ErrPolicyNotExist
+	}
+	return gm.putPolicy(ctx, p)
+}
+
+func (gm *GovPolicyManager) putPolicy(ctx context.Context, p *datasource.GovPolicy) error {
+	value, err := json.Marshal(p)
+	if err != nil {
+		log.Error("policy is invalid", err)
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateGovPolicyKey(p.Project, p.Kind, p.ID), value)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save %s policy %s", p.Kind, p.Name), err)
+		return err
+	}
+	return nil
+}
+
+func (gm *GovPolicyManager) GetPolicy(ctx context.Context, project, kind, id string) (*datasource.GovPolicy, error) {
+	key := path.GenerateGovPolicyKey(project, kind, id)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrPolicyNotExist
+	}
+	p := &datasource.GovPolicy{}
+	err = json.Unmarshal(resp.Kvs[0].Value, p)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (gm *GovPolicyManager) ListPolicy(ctx context.Context, project, kind string) ([]*datasource.GovPolicy, error) {
+	kvs, _, err := client.List(ctx, path.GenerateGovPolicyPrefix(project, kind))
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*datasource.GovPolicy, 0, len(kvs))
+	for _, kv := range kvs {
+		p := &datasource.GovPolicy{}
+		err = json.Unmarshal(kv.Value, p)
+		if err != nil {
+			log.Error(fmt.Sprintf("key %s format invalid", kv.Key), err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// DeletePolicy removes the policy and its name key
+func (gm *GovPolicyManager) DeletePolicy(ctx context.Context, project, kind, id string) error {
+	p, err := gm.GetPolicy(ctx, project, kind, id)
+	if err != nil {
+		if err == datasource.ErrPolicyNotExist {
+			return nil
+		}
+		return err
+	}
+	nameKey := path.GenerateGovPolicyNameKey(project, kind, p.App, p.Environment, p.Name)
+	_, err = client.Delete(ctx, path.GenerateGovPolicyKey(project, kind, id))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete %s policy %s", kind, id), err)
+		return err
+	}
+	// the name key of the policy saved before may be missing or taken by
+	// another policy, only delete the one of this policy
+	_, err = client.Instance().TxnWithCmp(ctx, []client.PluginOp{
+		client.OpDel(client.WithStrKey(nameKey)),
+	}, []client.CompareOp{
+		client.OpCmp(client.CmpStrVal(nameKey), client.CmpEqual, id),
+	}, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete the name of %s policy %s", kind, id), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type GovRevisionManager struct {
+}
+
+// AddRevision saves the record with the next revision, the key is put only
+// if it does not exist, so the concurrent records never override each other
+func (rm *GovRevisionManager) AddRevision(ctx context.Context, r *datasource.GovRevision) error {
+	for i := 0; i <= datasource.MaxAddRevisionRetries; i++ {
+		revisions, err := rm.ListRevision(ctx, r.Project, r.Kind, r.PolicyID)
+		if err != nil {
+			return err
+		}
+		r.Revision = 1
+		if len(revisions) > 0 {
+			r.Revision = revisions[len(revisions)-1].Revision + 1
+		}
+		value, err := json.Marshal(r)
+		if err != nil {
+			log.Error("revision is invalid", err)
+			return err
+		}
+		ok, err := client.Instance().PutNoOverride(ctx,
+			client.WithStrKey(path.GenerateGovRevisionKey(r.Project, r.Kind, r.PolicyID, r.Revision)),
+			client.WithValue(value))
+		if err != nil {
+			log.Error(fmt.Sprintf("can not save revision %d of %s policy %s", r.Revision, r.Kind, r.PolicyID), err)
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("can not save the revision of %s policy %s, it is changed concurrently", r.Kind, r.PolicyID)
+}
+
+func (rm *GovRevisionManager) ListRevision(ctx context.Context, project, kind, id string) ([]*datasource.GovRevision, error) {
+	kvs, _, err := client.List(ctx, path.GenerateGovRevisionPrefix(project, kind, id))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]*datasource.GovRevision, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &datasource.GovRevision{}
+		err = json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error(fmt.Sprintf("key %s format invalid", kv.Key), err)
+			continue
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, nil
+}
+
+func (rm *GovRevisionManager) GetRevision(ctx context.Context, project, kind, id string, revision int64) (*datasource.GovRevision, error) {
+	key := path.GenerateGovRevisionKey(project, kind, id, revision)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrRevisionNotExist
+	}
+	r := &datasource.GovRevision{}
+	err = json.Unmarshal(resp.Kvs[0].Value, r)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return nil, err
+	}
+	return r, nil
+}
@@ -179,8 +179,8 @@ func (ds *MetadataManager) RegisterService(ctx context.Context, request *pb.Crea
 
 }
 
-func (ds *MetadataManager) GetServices(ctx context.Context, request *pb.GetServicesRequest) (
-	*pb.GetServicesResponse, error) {
+func (ds *MetadataManager) GetServices(ctx context.Context, request *pb.GetServicesRequest,
+	opts ...datasource.ListOption) (*pb.GetServicesResponse, error) {
 	services, err := serviceUtil.GetAllServiceUtil(ctx)
 	if err != nil {
 		log.Error("get all services by domain failed", err)
@@ -188,6 +188,13 @@ func (ds *MetadataManager) GetServices(ctx context.Context, request *pb.GetServi
 			Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
 		}, err
 	}
+	_ = util.WithResponseTotal(ctx, int64(len(services)))
+	services, err = datasource.PageServices(services, datasource.ToListOptions(opts...))
+	if err != nil {
+		return &pb.GetServicesResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		}, nil
+	}
 
 	return &pb.GetServicesResponse{
 		Response: pb.CreateResponse(pb.ResponseSuccess, "Get all services successfully."),
@@ -760,8 +767,8 @@ func (ds *MetadataManager) GetInstance(ctx context.Context, request *pb.GetOneIn
 	}, nil
 }
 
-func (ds *MetadataManager) GetInstances(ctx context.Context, request *pb.GetInstancesRequest) (*pb.GetInstancesResponse,
-	error) {
+func (ds *MetadataManager) GetInstances(ctx context.Context, request *pb.GetInstancesRequest,
+	opts ...datasource.ListOption) (*pb.GetInstancesResponse, error) {
 	domainProject := util.ParseDomainProject(ctx)
 
 	service := &pb.MicroService{}
@@ -833,6 +840,13 @@ func (ds *MetadataManager) GetInstances(ctx context.Context, request *pb.GetInst
 		instances = nil // for gRPC
 	}
 	_ = util.WithResponseRev(ctx, item.Rev)
+	_ = util.WithResponseTotal(ctx, int64(len(item.Instances)))
+	instances, err = datasource.PageInstances(instances, datasource.ToListOptions(opts...))
+	if err != nil {
+		return &pb.GetInstancesResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		}, nil
+	}
 
 	return &pb.GetInstancesResponse{
 		Response:  pb.CreateResponse(pb.ResponseSuccess, "Query service instances successfully."),
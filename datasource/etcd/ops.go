@@ -46,6 +46,10 @@ func (ds *MetadataManager) GetServiceCount(ctx context.Context, request *pb.GetS
 	}, nil
 }
 
+func (ds *MetadataManager) ListServicesAcrossDomainProject(ctx context.Context) (map[string][]*pb.MicroService, error) {
+	return serviceUtil.GetAllServicesAcrossDomainProject(ctx)
+}
+
 func (ds *MetadataManager) getGlobalServiceCount(ctx context.Context, domainProject string) (int64, error) {
 	if strings.Index(datasource.RegistryDomainProject+datasource.SPLIT, domainProject+datasource.SPLIT) != 0 {
 		return 0, nil
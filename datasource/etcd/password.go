@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type PasswordHistoryManager struct {
+}
+
+func (pm *PasswordHistoryManager) GetPasswordHistory(ctx context.Context, account string) (*datasource.PasswordHistory, error) {
+	key := path.GeneratePasswordHistoryKey(account)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrPasswordHistoryNotExist
+	}
+	h := &datasource.PasswordHistory{}
+	err = json.Unmarshal(resp.Kvs[0].Value, h)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return nil, err
+	}
+	return h, nil
+}
+
+func (pm *PasswordHistoryManager) PutPasswordHistory(ctx context.Context, h *datasource.PasswordHistory) error {
+	value, err := json.Marshal(h)
+	if err != nil {
+		log.Error("password history is invalid", err)
+		return err
+	}
+	err = client.PutBytes(ctx, path.GeneratePasswordHistoryKey(h.Account), value)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save password history of account %s", h.Account), err)
+		return err
+	}
+	return nil
+}
+
+func (pm *PasswordHistoryManager) DeletePasswordHistory(ctx context.Context, account string) error {
+	_, err := client.Delete(ctx, path.GeneratePasswordHistoryKey(account))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete password history of account %s", account), err)
+		return err
+	}
+	return nil
+}
@@ -18,6 +18,8 @@
 package path
 
 import (
+	"fmt"
+
 	"github.com/go-chassis/cari/discovery"
 
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -105,6 +107,28 @@ func GenRoleAccountPrefixIdxKey(role string) string {
 	}, SPLIT)
 }
 
+func GenRoleParentIdxKey(parent, role string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"idx-role-parent",
+		parent, role,
+	}, SPLIT)
+}
+func GenRoleParentPrefixIdxKey(parent string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"idx-role-parent",
+		parent, "",
+	}, SPLIT)
+}
+func GenerateRoleParentsKey(role string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"role-parents",
+		role,
+	}, SPLIT)
+}
+
 func GetServiceRootKey(domainProject string) string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -368,6 +392,149 @@ func GenerateAccountLockKey(key string) string {
 		key,
 	}, SPLIT)
 }
+func GetAPIKeyRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"api-keys",
+	}, SPLIT)
+}
+
+// GenerateAPIKeyKey returns the key of the API key record, the keys of an
+// account are listed by the prefix of an empty id
+func GenerateAPIKeyKey(account, id string) string {
+	return util.StringJoin([]string{
+		GetAPIKeyRootKey(),
+		account,
+		id,
+	}, SPLIT)
+}
+
+// GenerateAPIKeyIDKey returns the key holding the account of the API key,
+// the key is authenticated by the id only
+func GenerateAPIKeyIDKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"api-key-ids",
+		id,
+	}, SPLIT)
+}
+func GenerateRevokedTokenKey(hash string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"revoked-tokens",
+		hash,
+	}, SPLIT)
+}
+func GenerateTokenGenerationKey(account string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"token-generations",
+		account,
+	}, SPLIT)
+}
+func GeneratePasswordHistoryKey(account string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"password-histories",
+		account,
+	}, SPLIT)
+}
+func GenerateCheckpointKey(task string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"checkpoints",
+		task,
+	}, SPLIT)
+}
+func GenerateQuotaKey(domain, project string) string {
+	if len(project) == 0 {
+		return util.StringJoin([]string{
+			GetRootKey(),
+			"quotas",
+			domain,
+		}, SPLIT)
+	}
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quotas",
+		domain,
+		project,
+	}, SPLIT)
+}
+func GetAuditLogRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"audit-logs",
+	}, SPLIT)
+}
+
+// GenerateAuditLogKey returns the key sorted by the record time, the
+// timestamp is the unix nano time
+func GenerateAuditLogKey(timestamp int64, id string) string {
+	return util.StringJoin([]string{
+		GetAuditLogRootKey(),
+		fmt.Sprintf("%019d", timestamp),
+		id,
+	}, SPLIT)
+}
+func GenerateGovPolicyKey(project, kind, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-policies",
+		project,
+		kind,
+		id,
+	}, SPLIT)
+}
+
+// GenerateGovPolicyNameKey returns the key holding the policy id, it makes
+// the name unique in the kind and the selector
+func GenerateGovPolicyNameKey(project, kind, app, env, name string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-policy-names",
+		project,
+		kind,
+		app,
+		env,
+		name,
+	}, SPLIT)
+}
+
+// GenerateGovRevisionKey returns the key sorted by the revision
+func GenerateGovRevisionKey(project, kind, id string, revision int64) string {
+	return GenerateGovRevisionPrefix(project, kind, id) + fmt.Sprintf("%019d", revision)
+}
+
+// GenerateGovRevisionPrefix returns the prefix of the revisions of the
+// policy, it ends with the separator
+func GenerateGovRevisionPrefix(project, kind, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-revisions",
+		project,
+		kind,
+		id, "",
+	}, SPLIT)
+}
+
+// GenerateGovPolicyPrefix returns the prefix of the policies of the
+// project, or of the kind if it is not empty
+func GenerateGovPolicyPrefix(project, kind string) string {
+	if len(kind) == 0 {
+		return util.StringJoin([]string{
+			GetRootKey(),
+			"gov-policies",
+			project, "",
+		}, SPLIT)
+	}
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"gov-policies",
+		project,
+		kind, "",
+	}, SPLIT)
+}
 func GenerateRBACSecretKey() string {
 	return util.StringJoin([]string{
 		GetRootKey(),
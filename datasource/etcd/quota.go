@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+)
+
+type QuotaManager struct {
+}
+
+func (qm *QuotaManager) GetQuota(ctx context.Context, domain, project string) (*quota.Quota, error) {
+	key := path.GenerateQuotaKey(domain, project)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrQuotaNotExist
+	}
+	q := &quota.Quota{}
+	err = json.Unmarshal(resp.Kvs[0].Value, q)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return nil, err
+	}
+	return q, nil
+}
+
+func (qm *QuotaManager) PutQuota(ctx context.Context, q *quota.Quota) error {
+	value, err := json.Marshal(q)
+	if err != nil {
+		log.Error("quota is invalid", err)
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateQuotaKey(q.Domain, q.Project), value)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save quota of %s/%s", q.Domain, q.Project), err)
+		return err
+	}
+	return nil
+}
+
+func (qm *QuotaManager) DeleteQuota(ctx context.Context, domain, project string) error {
+	_, err := client.Delete(ctx, path.GenerateQuotaKey(domain, project))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove quota of %s/%s", domain, project), err)
+		return err
+	}
+	return nil
+}
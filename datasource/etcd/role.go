@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chassis/cari/rbac"
@@ -37,7 +38,9 @@ import (
 type RoleManager struct {
 }
 
-func (rm *RoleManager) CreateRole(ctx context.Context, r *rbac.Role) error {
+// CreateRole saves the role and its parents in one txn, the txn fails if
+// the role exists or any parent does not exist
+func (rm *RoleManager) CreateRole(ctx context.Context, r *rbac.Role, parents ...string) error {
 	lock, err := etcdsync.Lock("/role-creating/"+r.Name, -1, false)
 	if err != nil {
 		return fmt.Errorf("role %s is creating", r.Name)
@@ -65,11 +68,24 @@ func (rm *RoleManager) CreateRole(ctx context.Context, r *rbac.Role) error {
 		log.Error("role info is invalid", err)
 		return err
 	}
-	err = client.PutBytes(ctx, key, value)
+	opts, cmps, err := parentsOps(r.Name, nil, parents)
 	if err != nil {
-		log.Error("can not save account info", err)
 		return err
 	}
+	opts = append(opts, client.OpPut(client.WithStrKey(key), client.WithValue(value)))
+	cmps = append(cmps, client.OpCmp(client.CmpStrCreateRev(key), client.CmpEqual, 0))
+	resp, err := client.Instance().TxnWithCmp(ctx, opts, cmps, nil)
+	if err != nil {
+		log.Error("can not save role info", err)
+		return err
+	}
+	if !resp.Succeeded {
+		exist, err := rm.RoleExist(ctx, r.Name)
+		if err == nil && exist {
+			return datasource.ErrRoleDuplicated
+		}
+		return datasource.ErrRoleNotExist
+	}
 	log.Info("create new role: " + r.ID)
 	return nil
 }
@@ -105,7 +121,7 @@ func (rm *RoleManager) GetRole(ctx context.Context, name string) (*rbac.Role, er
 	}
 	return role, nil
 }
-func (rm *RoleManager) ListRole(ctx context.Context) ([]*rbac.Role, int64, error) {
+func (rm *RoleManager) ListRole(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Role, int64, error) {
 	resp, err := client.Instance().Do(ctx, client.GET,
 		client.WithStrKey(path.GenerateRBACRoleKey("")), client.WithPrefix())
 	if err != nil {
@@ -122,8 +138,15 @@ func (rm *RoleManager) ListRole(ctx context.Context) ([]*rbac.Role, int64, error
 
 		roles = append(roles, r)
 	}
+	roles, err = datasource.PageRoles(roles, datasource.ToListOptions(opts...))
+	if err != nil {
+		return nil, 0, err
+	}
 	return roles, resp.Count, nil
 }
+
+// DeleteRole removes the role and its parents in one txn, the txn fails if
+// the role is bound or inherited at the time
 func (rm *RoleManager) DeleteRole(ctx context.Context, name string) (bool, error) {
 	exists, err := RoleBindingExists(ctx, name)
 	if err != nil {
@@ -133,11 +156,38 @@ func (rm *RoleManager) DeleteRole(ctx context.Context, name string) (bool, error
 	if exists {
 		return false, datasource.ErrRoleBindingExist
 	}
-	resp, err := client.Instance().Do(ctx, client.DEL,
-		client.WithStrKey(path.GenerateRBACRoleKey(name)))
+	_, total, err := client.List(ctx, path.GenRoleParentPrefixIdxKey(name))
+	if err != nil {
+		log.Error("", err)
+		return false, err
+	}
+	if total > 0 {
+		return false, datasource.ErrRoleInherited
+	}
+	old, err := rm.GetRoleParents(ctx, name)
 	if err != nil {
 		return false, err
 	}
+	opts, _, err := parentsOps(name, old, nil)
+	if err != nil {
+		return false, err
+	}
+	roleKey := path.GenerateRBACRoleKey(name)
+	opts = append(opts, client.OpDel(client.WithStrKey(roleKey)))
+	resp, err := client.Instance().TxnWithCmp(ctx, opts, []client.CompareOp{
+		client.OpCmp(client.CmpStrCreateRev(roleKey), client.CmpNotEqual, 0),
+		client.OpCmp(client.CmpPrefix(client.CmpStrCreateRev(path.GenRoleAccountPrefixIdxKey(name)+path.SPLIT)),
+			client.CmpEqual, 0),
+		client.OpCmp(client.CmpPrefix(client.CmpStrCreateRev(path.GenRoleParentPrefixIdxKey(name))),
+			client.CmpEqual, 0),
+	}, nil)
+	if err != nil {
+		return false, err
+	}
+	if !resp.Succeeded {
+		// the role may be bound or inherited since the check
+		log.Warn(fmt.Sprintf("role %s is changed while deleting", name))
+	}
 	return resp.Succeeded, nil
 }
 func RoleBindingExists(ctx context.Context, role string) (bool, error) {
@@ -160,3 +210,89 @@ func (rm *RoleManager) UpdateRole(ctx context.Context, name string, role *rbac.R
 		client.WithValue(value))
 	return err
 }
+
+func (rm *RoleManager) GetRoleParents(ctx context.Context, name string) ([]string, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRoleParentsKey(name)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, nil
+	}
+	var parents []string
+	err = json.Unmarshal(resp.Kvs[0].Value, &parents)
+	if err != nil {
+		log.Error(fmt.Sprintf("the parents of role %s format invalid", name), err)
+		return nil, err
+	}
+	return parents, nil
+}
+
+// SetRoleParents saves the parents and the parent-role indexes, the
+// indexes are used to prevent deleting the inherited roles
+func (rm *RoleManager) SetRoleParents(ctx context.Context, name string, parents []string) error {
+	old, err := rm.GetRoleParents(ctx, name)
+	if err != nil {
+		return err
+	}
+	opts, cmps, err := parentsOps(name, old, parents)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Instance().TxnWithCmp(ctx, opts, cmps, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save the parents of role %s", name), err)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrRoleNotExist
+	}
+	return nil
+}
+
+// parentsOps returns the ops replacing the parents of the role, and the
+// compares that the new parents exist
+func parentsOps(name string, old, parents []string) ([]client.PluginOp, []client.CompareOp, error) {
+	opts := make([]client.PluginOp, 0, len(old)+len(parents)+1)
+	if len(parents) == 0 {
+		opts = append(opts, client.OpDel(client.WithStrKey(path.GenerateRoleParentsKey(name))))
+	} else {
+		value, err := json.Marshal(parents)
+		if err != nil {
+			log.Error("role parents are invalid", err)
+			return nil, nil, err
+		}
+		opts = append(opts, client.OpPut(client.WithStrKey(path.GenerateRoleParentsKey(name)), client.WithValue(value)))
+	}
+	for _, p := range old {
+		if !util.SliceHave(parents, p) {
+			opts = append(opts, client.OpDel(client.WithStrKey(path.GenRoleParentIdxKey(p, name))))
+		}
+	}
+	cmps := make([]client.CompareOp, 0, len(parents))
+	for _, p := range parents {
+		opts = append(opts, client.OpPut(client.WithStrKey(path.GenRoleParentIdxKey(p, name))))
+		cmps = append(cmps, client.OpCmp(client.CmpStrCreateRev(path.GenerateRBACRoleKey(p)), client.CmpNotEqual, 0))
+	}
+	return opts, cmps, nil
+}
+
+func (rm *RoleManager) ListRoleParents(ctx context.Context) (map[string][]string, error) {
+	prefix := path.GenerateRoleParentsKey("")
+	kvs, _, err := client.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string][]string, len(kvs))
+	for _, kv := range kvs {
+		var parents []string
+		err = json.Unmarshal(kv.Value, &parents)
+		if err != nil {
+			log.Error(fmt.Sprintf("key %s format invalid", kv.Key), err)
+			continue
+		}
+		all[strings.TrimPrefix(string(kv.Key), prefix)] = parents
+	}
+	return all, nil
+}
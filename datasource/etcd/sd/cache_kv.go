@@ -124,7 +124,8 @@ func (c *KvCache) getPrefixKey(arr *[]*KeyValue, prefix string) (count int) {
 		return 0
 	}
 
-	// TODO support sort option
+	// the order is undefined here, CacheIndexer sorts the result by the
+	// sort option of the search
 	if arr == nil {
 		for key := range keysRef {
 			if n := c.getPrefixKey(nil, key); n > 0 {
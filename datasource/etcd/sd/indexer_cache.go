@@ -18,7 +18,9 @@
 package sd
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
@@ -72,6 +74,9 @@ func (i *CacheIndexer) searchByPrefix(op client.PluginOp) *Response {
 	t := time.Now()
 	kvs := make([]*KeyValue, 0, resp.Count)
 	i.Cache.GetPrefix(prefix, &kvs)
+	if op.SortOrder != client.SortNone {
+		sortKeyValues(kvs, op.OrderBy, op.SortOrder)
+	}
 	log.NilOrWarnf(t, "too long to index data[%d] from cache '%s'", len(kvs), i.Cache.Name())
 
 	resp.Kvs = kvs
@@ -88,3 +93,32 @@ func NewCacheIndexer(cache CacheReader) *CacheIndexer {
 		Cache: cache,
 	}
 }
+
+// sortKeyValues sorts the kvs in the same way as etcd, by the key or the
+// create revision
+func sortKeyValues(kvs []*KeyValue, target client.SortTarget, order client.SortOrder) {
+	sort.Slice(kvs, func(i, j int) bool {
+		c := 0
+		if target == client.OrderByCreate {
+			c = compareInt64(kvs[i].CreateRevision, kvs[j].CreateRevision)
+		}
+		if c == 0 {
+			c = bytes.Compare(kvs[i].Key, kvs[j].Key)
+		}
+		if order == client.SortDescend {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
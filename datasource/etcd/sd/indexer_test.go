@@ -95,3 +95,31 @@ func TestCacheIndexer_Search(t *testing.T) {
 		t.Fatalf("TestEtcdIndexer_Search failed, %v, %v", err, resp)
 	}
 }
+
+func TestCacheIndexer_SearchSorted(t *testing.T) {
+	c := NewKvCache("test", Configure())
+	c.Put("/a/b/2", &KeyValue{Key: []byte("/a/b/2"), CreateRevision: 1})
+	c.Put("/a/b/1", &KeyValue{Key: []byte("/a/b/1"), CreateRevision: 3})
+	c.Put("/a/b/3", &KeyValue{Key: []byte("/a/b/3"), CreateRevision: 2})
+	i := NewCacheIndexer(c)
+
+	keys := func(opts ...client.PluginOpOption) (s string) {
+		resp, err := i.Search(context.Background(), append(opts, client.WithStrKey("/a/b/"), client.WithPrefix())...)
+		if err != nil {
+			t.Fatalf("TestCacheIndexer_SearchSorted failed, %v", err)
+		}
+		for _, kv := range resp.Kvs {
+			s += string(kv.Key[len(kv.Key)-1:])
+		}
+		return
+	}
+	if s := keys(client.WithAscendOrder()); s != "123" {
+		t.Fatalf("TestCacheIndexer_SearchSorted failed, %s", s)
+	}
+	if s := keys(client.WithDescendOrder()); s != "321" {
+		t.Fatalf("TestCacheIndexer_SearchSorted failed, %s", s)
+	}
+	if s := keys(client.WithAscendOrder(), client.WithOrderByCreate()); s != "231" {
+		t.Fatalf("TestCacheIndexer_SearchSorted failed, %s", s)
+	}
+}
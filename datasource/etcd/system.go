@@ -19,15 +19,19 @@ package etcd
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/kv"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/mux"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/sd"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
 	"github.com/apache/servicecomb-service-center/pkg/etcdsync"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 )
 
 type SysManager struct {
@@ -74,8 +78,8 @@ func (sm *SysManager) DLock(ctx context.Context, request *datasource.DLockReques
 		lock *etcdsync.DLock
 		err  error
 	)
-	sm.lockMux.Lock()
-
+	// do not hold lockMux while acquiring, a waiting acquirement would block
+	// DUnlock of the holder forever
 	id := mux.Type(request.ID)
 	if request.Wait {
 		lock, err = mux.Lock(id)
@@ -83,11 +87,11 @@ func (sm *SysManager) DLock(ctx context.Context, request *datasource.DLockReques
 		lock, err = mux.Try(id)
 	}
 	if err != nil {
-		sm.lockMux.Unlock()
 		return err
 	}
-	sm.locks[request.ID] = lock
 
+	sm.lockMux.Lock()
+	sm.locks[request.ID] = lock
 	sm.lockMux.Unlock()
 	return nil
 }
@@ -107,3 +111,32 @@ func (sm *SysManager) DUnlock(ctx context.Context, request *datasource.DUnlockRe
 	sm.lockMux.Unlock()
 	return err
 }
+
+func (sm *SysManager) GetCheckpoint(ctx context.Context, task string) ([]byte, error) {
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(path.GenerateCheckpointKey(task)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, nil
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func (sm *SysManager) PutCheckpoint(ctx context.Context, task string, data []byte) error {
+	err := client.PutBytes(ctx, path.GenerateCheckpointKey(task), data)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save checkpoint of %s", task), err)
+		return err
+	}
+	return nil
+}
+
+func (sm *SysManager) DeleteCheckpoint(ctx context.Context, task string) error {
+	_, err := client.Delete(ctx, path.GenerateCheckpointKey(task))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove checkpoint of %s", task), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type TokenManager struct {
+}
+
+// RevokeToken saves the revoked token with a lease, it is removed by etcd
+// after the token expires
+func (tm *TokenManager) RevokeToken(ctx context.Context, t *datasource.RevokedToken) error {
+	value, err := json.Marshal(t)
+	if err != nil {
+		log.Error("revoked token is invalid", err)
+		return err
+	}
+	opts := []client.PluginOpOption{client.PUT, client.WithStrKey(path.GenerateRevokedTokenKey(t.Hash)), client.WithValue(value)}
+	if t.ExpireAt > 0 {
+		ttl := t.ExpireAt - time.Now().Unix()
+		if ttl <= 0 {
+			return nil
+		}
+		leaseID, err := client.Instance().LeaseGrant(ctx, ttl)
+		if err != nil {
+			log.Error(fmt.Sprintf("can not grant lease for the revoked token of account %s", t.Account), err)
+			return err
+		}
+		opts = append(opts, client.WithLease(leaseID))
+	}
+	_, err = client.Instance().Do(ctx, opts...)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save the revoked token of account %s", t.Account), err)
+		return err
+	}
+	return nil
+}
+
+func (tm *TokenManager) TokenRevoked(ctx context.Context, hash string) (bool, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRevokedTokenKey(hash)), client.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
+
+func (tm *TokenManager) GetGeneration(ctx context.Context, account string) (int64, error) {
+	key := path.GenerateTokenGenerationKey(account)
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		return 0, err
+	}
+	if resp.Count == 0 {
+		return 0, nil
+	}
+	g := &datasource.TokenGeneration{}
+	err = json.Unmarshal(resp.Kvs[0].Value, g)
+	if err != nil {
+		log.Error(fmt.Sprintf("key %s format invalid", key), err)
+		return 0, err
+	}
+	return g.Generation, nil
+}
+
+func (tm *TokenManager) IncGeneration(ctx context.Context, account string) (int64, error) {
+	generation, err := tm.GetGeneration(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	g := &datasource.TokenGeneration{Account: account, Generation: generation + 1}
+	value, err := json.Marshal(g)
+	if err != nil {
+		log.Error("token generation is invalid", err)
+		return 0, err
+	}
+	err = client.PutBytes(ctx, path.GenerateTokenGenerationKey(account), value)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save token generation of account %s", account), err)
+		return 0, err
+	}
+	return g.Generation, nil
+}
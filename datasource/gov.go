@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrPolicyNotExist      = errors.New("policy not exist")
+	ErrPolicyAlreadyExists = errors.New("policy already exists")
+)
+
+// GovPolicyManager saves the governance policies of the buildin config
+// distributor
+type GovPolicyManager interface {
+	// CreatePolicy fails with ErrPolicyAlreadyExists if the id is taken, or
+	// the name is taken in the kind and the selector
+	CreatePolicy(ctx context.Context, p *GovPolicy) error
+	UpdatePolicy(ctx context.Context, p *GovPolicy) error
+	GetPolicy(ctx context.Context, project, kind, id string) (*GovPolicy, error)
+	// ListPolicy returns the policies of the kind, all the kinds if it is empty
+	ListPolicy(ctx context.Context, project, kind string) ([]*GovPolicy, error)
+	DeletePolicy(ctx context.Context, project, kind, id string) error
+}
+
+// GovPolicy is a governance policy, the spec is saved as JSON
+type GovPolicy struct {
+	Project     string `json:"project" bson:"project"`
+	Kind        string `json:"kind" bson:"kind"`
+	ID          string `json:"id" bson:"id"`
+	Name        string `json:"name" bson:"name"`
+	Status      string `json:"status,omitempty" bson:"status"`
+	App         string `json:"app,omitempty" bson:"app"`
+	Environment string `json:"environment,omitempty" bson:"environment"`
+	Spec        string `json:"spec" bson:"spec"`
+	CreateTime  int64  `json:"createTime" bson:"create_time"`
+	UpdateTime  int64  `json:"updateTime" bson:"update_time"`
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrRevisionNotExist = errors.New("revision not exist")
+
+// MaxAddRevisionRetries is the max number of retries if the next revision
+// is taken by the others
+const MaxAddRevisionRetries = 5
+
+// GovRevisionManager saves the revision history of the governance policies
+type GovRevisionManager interface {
+	// AddRevision saves r as the latest revision of the policy, it sets
+	// the revision number of r
+	AddRevision(ctx context.Context, r *GovRevision) error
+	// ListRevision returns the revisions of the policy in ascending order
+	ListRevision(ctx context.Context, project, kind, id string) ([]*GovRevision, error)
+	GetRevision(ctx context.Context, project, kind, id string, revision int64) (*GovRevision, error)
+}
+
+// GovRevision is a revision of a governance policy, the policy and the diff
+// are saved as JSON
+type GovRevision struct {
+	Project   string `json:"project" bson:"project"`
+	Kind      string `json:"kind" bson:"kind"`
+	PolicyID  string `json:"policyId" bson:"policy_id"`
+	Revision  int64  `json:"revision" bson:"revision"`
+	Action    string `json:"action" bson:"action"`
+	Author    string `json:"author,omitempty" bson:"author"`
+	Timestamp int64  `json:"timestamp" bson:"timestamp"`
+	Policy    string `json:"policy" bson:"policy"`
+	Diff      string `json:"diff,omitempty" bson:"diff"`
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGovRevision_AddConcurrently(t *testing.T) {
+	const n = 5
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := datasource.GetGovRevisionManager().AddRevision(getContext(), &datasource.GovRevision{
+				Project: "revision_test", Kind: "retry", PolicyID: "concurrent", Action: "update",
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	revisions, err := datasource.GetGovRevisionManager().ListRevision(getContext(), "revision_test", "retry", "concurrent")
+	assert.NoError(t, err)
+	if assert.Equal(t, n, len(revisions)) {
+		for i, r := range revisions {
+			assert.Equal(t, int64(i+1), r.Revision)
+		}
+	}
+}
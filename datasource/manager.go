@@ -53,19 +53,30 @@ func Init(opts Options) error {
 		return nil
 	}
 
-	dataSourceEngine, ok := plugins[opts.Kind]
-	if !ok {
-		return fmt.Errorf("plugin implement not supported [%s]", opts.Kind)
-	}
-	var err error
-	dataSourceInst, err = dataSourceEngine(opts)
+	inst, err := New(opts)
 	if err != nil {
 		return err
 	}
+	dataSourceInst = inst
 	log.Info(fmt.Sprintf("datasource plugin [%s] enabled", opts.Kind))
 	return nil
 }
 
+// New constructs a datasource instance of opts.Kind without replacing the
+// global one, it is used to access another backend, e.g. data migration
+func New(opts Options) (DataSource, error) {
+	dataSourceEngine, ok := plugins[opts.Kind]
+	if !ok {
+		return nil, fmt.Errorf("plugin implement not supported [%s]", opts.Kind)
+	}
+	return dataSourceEngine(opts)
+}
+
+// GetDataSource returns the datasource instance enabled by Init
+func GetDataSource() DataSource {
+	return dataSourceInst
+}
+
 func GetSCManager() SCManager {
 	return dataSourceInst.SCManager()
 }
@@ -84,6 +95,27 @@ func GetAccountManager() AccountManager {
 func GetAccountLockManager() AccountLockManager {
 	return dataSourceInst.AccountLockManager()
 }
+func GetAPIKeyManager() APIKeyManager {
+	return dataSourceInst.APIKeyManager()
+}
+func GetTokenManager() TokenManager {
+	return dataSourceInst.TokenManager()
+}
+func GetPasswordHistoryManager() PasswordHistoryManager {
+	return dataSourceInst.PasswordHistoryManager()
+}
+func GetQuotaManager() QuotaManager {
+	return dataSourceInst.QuotaManager()
+}
+func GetAuditLogManager() AuditLogManager {
+	return dataSourceInst.AuditLogManager()
+}
+func GetGovPolicyManager() GovPolicyManager {
+	return dataSourceInst.GovPolicyManager()
+}
+func GetGovRevisionManager() GovRevisionManager {
+	return dataSourceInst.GovRevisionManager()
+}
 func GetDependencyManager() DependencyManager {
 	return dataSourceInst.DependencyManager()
 }
@@ -48,11 +48,13 @@ func (ds *AccountManager) CreateAccount(ctx context.Context, a *rbac.Account) er
 	if exist {
 		return datasource.ErrAccountDuplicated
 	}
-	a.Password, err = privacy.ScryptPassword(a.Password)
-	if err != nil {
-		msg := fmt.Sprintf("failed to hash account pwd, account name %s", a.Name)
-		log.Error(msg, err)
-		return err
+	if !util.HashedPassword(ctx) {
+		a.Password, err = privacy.ScryptPassword(a.Password)
+		if err != nil {
+			msg := fmt.Sprintf("failed to hash account pwd, account name %s", a.Name)
+			log.Error(msg, err)
+			return err
+		}
 	}
 	a.Role = ""
 	a.CurrentPassword = ""
@@ -107,9 +109,14 @@ func (ds *AccountManager) GetAccount(ctx context.Context, name string) (*rbac.Ac
 	return &account, nil
 }
 
-func (ds *AccountManager) ListAccount(ctx context.Context) ([]*rbac.Account, int64, error) {
+func (ds *AccountManager) ListAccount(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Account, int64, error) {
 	filter := mutil.NewFilter()
-	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAccount, filter)
+	o := datasource.ToListOptions(opts...)
+	findOpts, ok := findOptions(o, rbacSortColumns, datasource.SortByName)
+	if !ok {
+		return nil, 0, datasource.ErrInvalidSortKey
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAccount, filter, findOpts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -125,7 +132,14 @@ func (ds *AccountManager) ListAccount(ctx context.Context) ([]*rbac.Account, int
 		account.Password = ""
 		accounts = append(accounts, &account)
 	}
-	return accounts, int64(len(accounts)), nil
+	if !o.Paged() {
+		return accounts, int64(len(accounts)), nil
+	}
+	total, err := client.Count(ctx, model.CollectionAccount, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return accounts, total, nil
 }
 
 func (ds *AccountManager) DeleteAccount(ctx context.Context, names []string) (bool, error) {
@@ -56,6 +56,25 @@ func (al *AccountLockManager) GetLock(ctx context.Context, key string) (*datasou
 	return &lock, nil
 }
 
+func (al *AccountLockManager) ListLock(ctx context.Context) ([]*datasource.AccountLock, int64, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAccountLock, mutil.NewFilter())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	var locks []*datasource.AccountLock
+	for cursor.Next(ctx) {
+		lock := &datasource.AccountLock{}
+		err = cursor.Decode(lock)
+		if err != nil {
+			log.Error("failed to decode account lock", err)
+			continue
+		}
+		locks = append(locks, lock)
+	}
+	return locks, int64(len(locks)), nil
+}
+
 func (al *AccountLockManager) DeleteLock(ctx context.Context, key string) error {
 	filter := mutil.NewFilter(mutil.AccountLockKey(key))
 	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAccountLock, filter)
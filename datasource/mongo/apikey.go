@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type APIKeyManager struct {
+}
+
+func (km *APIKeyManager) CreateKey(ctx context.Context, key *apikey.APIKey) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAPIKey, key)
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrAPIKeyDuplicated
+		}
+		log.Error(fmt.Sprintf("can not save api key %s of account %s", key.ID, key.Account), err)
+		return err
+	}
+	return nil
+}
+
+func (km *APIKeyManager) GetKey(ctx context.Context, id string) (*apikey.APIKey, error) {
+	filter := mutil.NewFilter(mutil.APIKeyID(id))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrAPIKeyNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query api key %s", id), err)
+		return nil, err
+	}
+	k := &apikey.APIKey{}
+	err = result.Decode(k)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode api key %s", id), err)
+		return nil, err
+	}
+	return k, nil
+}
+
+func (km *APIKeyManager) ListKey(ctx context.Context, account string) ([]*apikey.APIKey, error) {
+	filter := mutil.NewFilter(mutil.APIKeyAccount(account))
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var keys []*apikey.APIKey
+	for cursor.Next(ctx) {
+		k := &apikey.APIKey{}
+		err = cursor.Decode(k)
+		if err != nil {
+			log.Error("failed to decode api key", err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (km *APIKeyManager) UpdateKey(ctx context.Context, key *apikey.APIKey) error {
+	filter := mutil.NewFilter(mutil.APIKeyID(key.ID))
+	update := mutil.NewFilter(mutil.Set(key))
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionAPIKey, filter, update)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not update api key %s of account %s", key.ID, key.Account), err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return datasource.ErrAPIKeyNotExist
+	}
+	return nil
+}
+
+func (km *APIKeyManager) DeleteKey(ctx context.Context, id string) error {
+	filter := mutil.NewFilter(mutil.APIKeyID(id))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove api key %s", id), err)
+		return err
+	}
+	return nil
+}
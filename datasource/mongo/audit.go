@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type AuditLogManager struct {
+}
+
+// AddAuditLog saves the record, it is removed by the TTL index of
+// expire_at after the retention
+func (am *AuditLogManager) AddAuditLog(ctx context.Context, r *audit.Record) error {
+	doc := &model.AuditLog{Record: *r}
+	if r.ExpireAt > 0 {
+		expireAt := time.Unix(r.ExpireAt, 0)
+		if !expireAt.After(time.Now()) {
+			return nil
+		}
+		doc.ExpireAt = &expireAt
+	}
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAuditLog, doc)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save the audit record %s", r.ID), err)
+		return err
+	}
+	return nil
+}
+
+// ListAuditLog queries the matched records with the limit, the resource
+// matches the type or any value of the resource IDs
+func (am *AuditLogManager) ListAuditLog(ctx context.Context, req *audit.Request) ([]*audit.Record, int64, error) {
+	end := req.End
+	if end <= 0 {
+		end = math.MaxInt64
+	}
+	opts := []mutil.Option{mutil.AuditTimestamp(mutil.NewFilter(mutil.Gte(req.Start), mutil.Lt(end)))}
+	if len(req.Actor) > 0 {
+		opts = append(opts, mutil.AuditActor(req.Actor))
+	}
+	if len(req.Resource) > 0 {
+		opts = append(opts, mutil.Or(mutil.AuditResource(req.Resource), mutil.AuditResourceID(req.Resource)))
+	}
+	findOpts := options.Find().SetSort(bson.M{model.ColumnAuditTimestamp: -1})
+	if req.Limit > 0 {
+		findOpts.SetLimit(req.Limit)
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAuditLog, mutil.NewFilter(opts...), findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	var records []*audit.Record
+	for cursor.Next(ctx) {
+		doc := &model.AuditLog{}
+		err = cursor.Decode(doc)
+		if err != nil {
+			log.Error("failed to decode audit record", err)
+			continue
+		}
+		r := doc.Record
+		if doc.ExpireAt != nil {
+			r.ExpireAt = doc.ExpireAt.Unix()
+		}
+		records = append(records, &r)
+	}
+	return records, int64(len(records)), nil
+}
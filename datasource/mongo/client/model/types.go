@@ -21,19 +21,31 @@ import (
 	"time"
 
 	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/audit"
 )
 
 const (
-	CollectionAccount     = "account"
-	CollectionAccountLock = "account_lock"
-	CollectionService     = "service"
-	CollectionSchema      = "schema"
-	CollectionRule        = "rule"
-	CollectionInstance    = "instance"
-	CollectionDep         = "dependency"
-	CollectionRole        = "role"
-	CollectionDomain      = "domain"
-	CollectionProject     = "project"
+	CollectionAccount         = "account"
+	CollectionAccountLock     = "account_lock"
+	CollectionService         = "service"
+	CollectionSchema          = "schema"
+	CollectionRule            = "rule"
+	CollectionInstance        = "instance"
+	CollectionDep             = "dependency"
+	CollectionRole            = "role"
+	CollectionDomain          = "domain"
+	CollectionProject         = "project"
+	CollectionDLock           = "dlock"
+	CollectionQuota           = "quota"
+	CollectionAPIKey          = "api_key"
+	CollectionRevokedToken    = "revoked_token"
+	CollectionTokenGeneration = "token_generation"
+	CollectionPasswordHistory = "password_history"
+	CollectionAuditLog        = "audit_log"
+	CollectionGovPolicy       = "gov_policy"
+	CollectionGovRevision     = "gov_revision"
+	CollectionCheckpoint      = "checkpoint"
 )
 
 const (
@@ -46,6 +58,7 @@ const (
 	ColumnService              = "service"
 	ColumnProperty             = "properties"
 	ColumnModTime              = "mod_timestamp"
+	ColumnTimestamp            = "timestamp"
 	ColumnEnv                  = "env"
 	ColumnAppID                = "app"
 	ColumnServiceName          = "service_name"
@@ -70,6 +83,9 @@ const (
 	ColumnAccountName          = "name"
 	ColumnRoleName             = "name"
 	ColumnPerms                = "perms"
+	ColumnRoleParents          = "parents"
+	ColumnRoleChildren         = "children"
+	ColumnCreateTime           = "createtime"
 	ColumnAccountUpdateTime    = "updatetime"
 	ColumnRoleUpdateTime       = "updatetime"
 	ColumnPassword             = "password"
@@ -81,6 +97,33 @@ const (
 	ColumnAccountLockKey       = "key"
 	ColumnAccountLockStatus    = "status"
 	ColumnAccountLockReleaseAt = "release_at"
+	ColumnDLockKey             = "key"
+	ColumnDLockOwner           = "owner"
+	ColumnDLockToken           = "token"
+	ColumnDLockExpireAt        = "expire_at"
+	ColumnQuotaLimits          = "limits"
+	ColumnAPIKeyID             = "id"
+	ColumnAPIKeyAccount        = "account"
+	ColumnTokenHash            = "hash"
+	ColumnTokenExpireAt        = "expire_at"
+	ColumnTokenAccount         = "account"
+	ColumnTokenGeneration      = "generation"
+	ColumnPasswordAccount      = "account"
+	ColumnAuditTimestamp       = "timestamp"
+	ColumnAuditActor           = "actor"
+	ColumnAuditResource        = "resource"
+	ColumnAuditResourceIDs     = "resource_ids"
+	ColumnAuditExpireAt        = "expire_at"
+	ColumnGovProject           = "project"
+	ColumnGovKind              = "kind"
+	ColumnGovID                = "id"
+	ColumnGovName              = "name"
+	ColumnGovApp               = "app"
+	ColumnGovEnvironment       = "environment"
+	ColumnGovPolicyID          = "policy_id"
+	ColumnGovRevision          = "revision"
+	ColumnCheckpointTask       = "task"
+	ColumnCheckpointData       = "data"
 )
 
 type Service struct {
@@ -142,3 +185,25 @@ type Project struct {
 	Domain  string `json:"domain,omitempty"`
 	Project string `json:"project,omitempty"`
 }
+
+// DLock is the lease of a distributed lock, the document is never deleted
+// to keep the fencing token increasing
+type DLock struct {
+	Key      string    `json:"key,omitempty"`
+	Owner    string    `json:"owner,omitempty"`
+	Token    int64     `json:"token,omitempty"`
+	ExpireAt time.Time `json:"expireAt,omitempty" bson:"expire_at"`
+}
+
+// Checkpoint is the saved progress of a system task, e.g. the migration
+type Checkpoint struct {
+	Task string `json:"task,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// AuditLog is the stored audit record, the expire time is a date so that the
+// record is removed by the TTL index, it is nil if the record never expires
+type AuditLog struct {
+	audit.Record `bson:",inline"`
+	ExpireAt     *time.Time `json:"expireAt,omitempty" bson:"expire_at,omitempty"`
+}
@@ -37,6 +37,15 @@ func EnsureDB() {
 	EnsureSchema()
 	EnsureDep()
 	EnsureAccountLock()
+	EnsureDLock()
+	EnsureQuota()
+	EnsureAPIKey()
+	EnsureToken()
+	EnsurePasswordHistory()
+	EnsureAuditLog()
+	EnsureGovPolicy()
+	EnsureGovRevision()
+	EnsureCheckpoint()
 }
 
 func EnsureService() {
@@ -99,6 +108,72 @@ func EnsureAccountLock() {
 		mutil.BuildIndexDoc(model.ColumnAccountLockKey)})
 }
 
+func EnsureDLock() {
+	dlockIndex := mutil.BuildIndexDoc(model.ColumnDLockKey)
+	dlockIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionDLock, []mongo.IndexModel{dlockIndex})
+}
+
+func EnsureQuota() {
+	quotaIndex := mutil.BuildIndexDoc(model.ColumnDomain, model.ColumnProject)
+	quotaIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionQuota, []mongo.IndexModel{quotaIndex})
+}
+
+func EnsureCheckpoint() {
+	taskIndex := mutil.BuildIndexDoc(model.ColumnCheckpointTask)
+	taskIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionCheckpoint, []mongo.IndexModel{taskIndex})
+}
+
+func EnsureAPIKey() {
+	idIndex := mutil.BuildIndexDoc(model.ColumnAPIKeyID)
+	idIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionAPIKey, []mongo.IndexModel{idIndex,
+		mutil.BuildIndexDoc(model.ColumnAPIKeyAccount)})
+}
+
+func EnsureToken() {
+	hashIndex := mutil.BuildIndexDoc(model.ColumnTokenHash)
+	hashIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionRevokedToken, []mongo.IndexModel{hashIndex,
+		mutil.BuildIndexDoc(model.ColumnTokenExpireAt)})
+	accountIndex := mutil.BuildIndexDoc(model.ColumnTokenAccount)
+	accountIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionTokenGeneration, []mongo.IndexModel{accountIndex})
+}
+
+func EnsurePasswordHistory() {
+	accountIndex := mutil.BuildIndexDoc(model.ColumnPasswordAccount)
+	accountIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionPasswordHistory, []mongo.IndexModel{accountIndex})
+}
+
+func EnsureAuditLog() {
+	expireIndex := mutil.BuildIndexDoc(model.ColumnAuditExpireAt)
+	expireIndex.Options = options.Index().SetExpireAfterSeconds(0)
+	EnsureCollection(model.CollectionAuditLog, []mongo.IndexModel{
+		mutil.BuildIndexDoc(model.ColumnAuditTimestamp),
+		mutil.BuildIndexDoc(model.ColumnAuditActor, model.ColumnAuditTimestamp),
+		expireIndex})
+}
+
+func EnsureGovPolicy() {
+	idIndex := mutil.BuildIndexDoc(model.ColumnGovProject, model.ColumnGovKind, model.ColumnGovID)
+	idIndex.Options = options.Index().SetUnique(true)
+	nameIndex := mutil.BuildIndexDoc(model.ColumnGovProject, model.ColumnGovKind,
+		model.ColumnGovApp, model.ColumnGovEnvironment, model.ColumnGovName)
+	nameIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionGovPolicy, []mongo.IndexModel{idIndex, nameIndex})
+}
+
+func EnsureGovRevision() {
+	revisionIndex := mutil.BuildIndexDoc(model.ColumnGovProject, model.ColumnGovKind,
+		model.ColumnGovPolicyID, model.ColumnGovRevision)
+	revisionIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionGovRevision, []mongo.IndexModel{revisionIndex})
+}
+
 func EnsureCollection(col string, indexes []mongo.IndexModel) {
 	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), col, options.CreateCollection().SetValidator(nil))
 	wrapCreateCollectionError(err)
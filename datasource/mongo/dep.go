@@ -173,6 +173,16 @@ func (ds *DepManager) AddOrUpdateDependencies(ctx context.Context, dependencys [
 	return discovery.CreateResponse(discovery.ResponseSuccess, "Create dependency successfully."), nil
 }
 
+func (ds *DepManager) GetConsumerDependencyRules(ctx context.Context, consumer *discovery.MicroServiceKey) ([]*discovery.MicroServiceKey, error) {
+	filter := GenerateConsumerDependencyRuleKey(util.ParseDomainProject(ctx), consumer)
+	dep, err := TransferToMicroServiceDependency(ctx, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("get dependency rule [%v] failed", filter), err)
+		return nil, err
+	}
+	return dep.Dependency, nil
+}
+
 func (ds *DepManager) DeleteDependency() {
 	panic("implement me")
 }
@@ -0,0 +1,231 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dlock implements the distributed lock based on the mongo lease
+// documents, it behaves like pkg/etcdsync.DLock
+package dlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	DefaultLockTTL       = 60
+	DefaultRetryInterval = time.Second
+)
+
+var (
+	ErrLocked   = errors.New("lock is held by others")
+	ErrLockLost = errors.New("lock lease is lost")
+
+	hostname = util.HostName()
+	pid      = os.Getpid()
+)
+
+// DLock is a lease of the key, the lease is renewed in background until
+// Unlock is called or it is taken over by others after expired
+type DLock struct {
+	key   string
+	id    string
+	ttl   time.Duration
+	token int64
+
+	mux    sync.Mutex
+	err    error
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Lock creates the lease of the key, if wait is true, it retries until
+// the lease is acquired or ctx is done, otherwise returns ErrLocked
+func Lock(ctx context.Context, key string, ttl int64, wait bool) (*DLock, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty lock key")
+	}
+	if ttl < 1 {
+		ttl = DefaultLockTTL
+	}
+	now := time.Now()
+	l := &DLock{
+		key: key,
+		id:  fmt.Sprintf("%v-%v-%v", hostname, pid, now.Format("20060102-15:04:05.999999999")),
+		ttl: time.Duration(ttl) * time.Second,
+	}
+	for {
+		err := l.acquire(ctx)
+		if err == nil {
+			break
+		}
+		if err != ErrLocked || !wait {
+			return nil, err
+		}
+		log.Debug(fmt.Sprintf("key %s is locked, waiting for other node releases it, id=%s", l.key, l.id))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(DefaultRetryInterval):
+		}
+	}
+	l.keepalive()
+	log.Info(fmt.Sprintf("create lock OK, key=%s, id=%s, token=%d", l.key, l.id, l.token))
+	return l, nil
+}
+
+// ID returns the owner id of the lock
+func (l *DLock) ID() string {
+	return l.id
+}
+
+// Token returns the fencing token, it increases every time the lock is acquired
+func (l *DLock) Token() int64 {
+	return l.token
+}
+
+// Err returns ErrLockLost if the lease can not be renewed any more
+func (l *DLock) Err() error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.err
+}
+
+func (l *DLock) acquire(ctx context.Context) error {
+	now := time.Now()
+	filter := mutil.NewFilter(
+		mutil.DLockKey(l.key),
+		mutil.Or(
+			mutil.DLockExpireAt(mutil.NewFilter(mutil.Lt(now))),
+			mutil.DLockOwner(l.id),
+		),
+	)
+	update := mutil.NewFilter(
+		mutil.Set(mutil.NewFilter(
+			mutil.DLockOwner(l.id),
+			mutil.DLockExpireAt(now.Add(l.ttl)),
+		)),
+		mutil.Inc(mutil.NewFilter(mutil.DLockToken(1))),
+	)
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionDLock, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+	if err != nil {
+		return err
+	}
+	if err = result.Err(); err != nil {
+		if client.IsDuplicateKey(err) {
+			// the key exists and is held by others
+			return ErrLocked
+		}
+		return err
+	}
+	var lock model.DLock
+	if err = result.Decode(&lock); err != nil {
+		return err
+	}
+	l.token = lock.Token
+	return nil
+}
+
+// Renew extends the lease, returns ErrLockLost if the lock is taken over
+func (l *DLock) Renew(ctx context.Context) error {
+	filter := l.ownerFilter()
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.DLockExpireAt(time.Now().Add(l.ttl)))))
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionDLock, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (l *DLock) keepalive() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	l.done = make(chan struct{})
+	gopool.Go(func(_ context.Context) {
+		defer close(l.done)
+		interval := l.ttl / 3
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			err := l.Renew(ctx)
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Error(fmt.Sprintf("renew lock failed, key=%s, id=%s", l.key, l.id), err)
+			if err == ErrLockLost {
+				l.mux.Lock()
+				l.err = err
+				l.mux.Unlock()
+				return
+			}
+		}
+	})
+}
+
+// Unlock stops renewing and releases the lease, the lease document is
+// kept to make the fencing token increase monotonically
+func (l *DLock) Unlock() error {
+	l.cancel()
+	<-l.done
+
+	ctx := context.Background()
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(
+		mutil.DLockOwner(""),
+		mutil.DLockExpireAt(time.Now()),
+	)))
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionDLock, l.ownerFilter(), update)
+	if err != nil {
+		log.Error(fmt.Sprintf("delete lock failed, key=%s, id=%s", l.key, l.id), err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		log.Warn(fmt.Sprintf("lock is already lost, key=%s, id=%s", l.key, l.id))
+		return nil
+	}
+	log.Info(fmt.Sprintf("delete lock OK, key=%s, id=%s", l.key, l.id))
+	return nil
+}
+
+func (l *DLock) ownerFilter() bson.M {
+	return mutil.NewFilter(
+		mutil.DLockKey(l.key),
+		mutil.DLockOwner(l.id),
+		mutil.DLockToken(l.token),
+	)
+}
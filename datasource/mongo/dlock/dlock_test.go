@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dlock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/go-chassis/v2/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/dlock"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+)
+
+func init() {
+	client.NewMongoClient(storage.Options{
+		URI: "mongodb://localhost:27017",
+	})
+	mongo.EnsureDLock()
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	key := "/test/dlock/" + time.Now().Format("150405.000000000")
+
+	t.Run("lock and unlock, should pass", func(t *testing.T) {
+		l, err := dlock.Lock(ctx, key, 3, false)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, l.ID())
+		token := l.Token()
+
+		_, err = dlock.Lock(ctx, key, 3, false)
+		assert.Equal(t, dlock.ErrLocked, err)
+
+		assert.NoError(t, l.Unlock())
+
+		l, err = dlock.Lock(ctx, key, 3, false)
+		assert.NoError(t, err)
+		assert.Equal(t, token+1, l.Token())
+		assert.NoError(t, l.Unlock())
+	})
+
+	t.Run("wait for the lock released, should pass", func(t *testing.T) {
+		l, err := dlock.Lock(ctx, key, 3, false)
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(time.Second)
+			_ = l.Unlock()
+		}()
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		l2, err := dlock.Lock(waitCtx, key, 3, true)
+		assert.NoError(t, err)
+		assert.Equal(t, l.Token()+1, l2.Token())
+		assert.NoError(t, l2.Unlock())
+	})
+
+	t.Run("take over the expired lock, should pass", func(t *testing.T) {
+		filter := mutil.NewFilter(mutil.DLockKey(key))
+		_, err := client.GetMongoClient().Update(ctx, model.CollectionDLock, filter,
+			mutil.NewFilter(mutil.Set(mutil.NewFilter(
+				mutil.DLockOwner("crashed"),
+				mutil.DLockExpireAt(time.Now().Add(-time.Second)),
+			))))
+		assert.NoError(t, err)
+
+		l, err := dlock.Lock(ctx, key, 3, false)
+		assert.NoError(t, err)
+		assert.NoError(t, l.Unlock())
+	})
+
+	t.Run("lock is taken over when renewing, should be lost", func(t *testing.T) {
+		l, err := dlock.Lock(ctx, key, 3, false)
+		assert.NoError(t, err)
+
+		filter := mutil.NewFilter(mutil.DLockKey(key))
+		_, err = client.GetMongoClient().Update(ctx, model.CollectionDLock, filter,
+			mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.DLockOwner("others")))))
+		assert.NoError(t, err)
+
+		time.Sleep(2 * time.Second)
+		assert.Equal(t, dlock.ErrLockLost, l.Err())
+		assert.NoError(t, l.Unlock())
+	})
+}
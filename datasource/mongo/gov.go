@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type GovPolicyManager struct {
+}
+
+func (gm *GovPolicyManager) CreatePolicy(ctx context.Context, p *datasource.GovPolicy) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionGovPolicy, p)
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrPolicyAlreadyExists
+		}
+		log.Error(fmt.Sprintf("can not save %s policy %s", p.Kind, p.Name), err)
+		return err
+	}
+	return nil
+}
+
+func (gm *GovPolicyManager) UpdatePolicy(ctx context.Context, p *datasource.GovPolicy) error {
+	filter := mutil.NewFilter(mutil.GovProject(p.Project), mutil.GovKind(p.Kind), mutil.GovID(p.ID))
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionGovPolicy, filter, mutil.NewFilter(mutil.Set(p)))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save %s policy %s", p.Kind, p.Name), err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return datasource.ErrPolicyNotExist
+	}
+	return nil
+}
+
+func (gm *GovPolicyManager) GetPolicy(ctx context.Context, project, kind, id string) (*datasource.GovPolicy, error) {
+	filter := mutil.NewFilter(mutil.GovProject(project), mutil.GovKind(kind), mutil.GovID(id))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovPolicy, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrPolicyNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query %s policy %s", kind, id), err)
+		return nil, err
+	}
+	p := &datasource.GovPolicy{}
+	err = result.Decode(p)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode %s policy %s", kind, id), err)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (gm *GovPolicyManager) ListPolicy(ctx context.Context, project, kind string) ([]*datasource.GovPolicy, error) {
+	opts := []mutil.Option{mutil.GovProject(project)}
+	if len(kind) > 0 {
+		opts = append(opts, mutil.GovKind(kind))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionGovPolicy, mutil.NewFilter(opts...))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	policies := make([]*datasource.GovPolicy, 0)
+	for cursor.Next(ctx) {
+		p := &datasource.GovPolicy{}
+		err = cursor.Decode(p)
+		if err != nil {
+			log.Error("failed to decode policy", err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (gm *GovPolicyManager) DeletePolicy(ctx context.Context, project, kind, id string) error {
+	filter := mutil.NewFilter(mutil.GovProject(project), mutil.GovKind(kind), mutil.GovID(id))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionGovPolicy, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete %s policy %s", kind, id), err)
+		return err
+	}
+	return nil
+}
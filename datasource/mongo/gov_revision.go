@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type GovRevisionManager struct {
+}
+
+// AddRevision inserts the record with the next revision, the unique index
+// rejects the revision taken by the others, then it is retried
+func (rm *GovRevisionManager) AddRevision(ctx context.Context, r *datasource.GovRevision) error {
+	filter := mutil.NewFilter(mutil.GovProject(r.Project), mutil.GovKind(r.Kind), mutil.GovPolicyID(r.PolicyID))
+	for i := 0; i <= datasource.MaxAddRevisionRetries; i++ {
+		result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovRevision, filter,
+			options.FindOne().SetSort(bson.M{model.ColumnGovRevision: -1}))
+		if err != nil {
+			return err
+		}
+		latest := &datasource.GovRevision{}
+		if err = result.Decode(latest); err != nil && err != mongo.ErrNoDocuments {
+			log.Error(fmt.Sprintf("failed to query the revisions of %s policy %s", r.Kind, r.PolicyID), err)
+			return err
+		}
+		r.Revision = latest.Revision + 1
+		_, err = client.GetMongoClient().Insert(ctx, model.CollectionGovRevision, r)
+		if err == nil {
+			return nil
+		}
+		if !client.IsDuplicateKey(err) {
+			log.Error(fmt.Sprintf("can not save revision %d of %s policy %s", r.Revision, r.Kind, r.PolicyID), err)
+			return err
+		}
+	}
+	return fmt.Errorf("can not save the revision of %s policy %s, it is changed concurrently", r.Kind, r.PolicyID)
+}
+
+func (rm *GovRevisionManager) ListRevision(ctx context.Context, project, kind, id string) ([]*datasource.GovRevision, error) {
+	filter := mutil.NewFilter(mutil.GovProject(project), mutil.GovKind(kind), mutil.GovPolicyID(id))
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionGovRevision, filter,
+		options.Find().SetSort(bson.M{model.ColumnGovRevision: 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	revisions := make([]*datasource.GovRevision, 0)
+	for cursor.Next(ctx) {
+		r := &datasource.GovRevision{}
+		err = cursor.Decode(r)
+		if err != nil {
+			log.Error("failed to decode revision", err)
+			continue
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, nil
+}
+
+func (rm *GovRevisionManager) GetRevision(ctx context.Context, project, kind, id string, revision int64) (*datasource.GovRevision, error) {
+	filter := mutil.NewFilter(mutil.GovProject(project), mutil.GovKind(kind), mutil.GovPolicyID(id), mutil.GovRevision(revision))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionGovRevision, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrRevisionNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query revision %d of %s policy %s", revision, kind, id), err)
+		return nil, err
+	}
+	r := &datasource.GovRevision{}
+	err = result.Decode(r)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode revision %d of %s policy %s", revision, kind, id), err)
+		return nil, err
+	}
+	return r, nil
+}
@@ -151,18 +151,43 @@ func (ds *MetadataManager) RegisterService(ctx context.Context, request *discove
 	}, nil
 }
 
-func (ds *MetadataManager) GetServices(ctx context.Context, request *discovery.GetServicesRequest) (*discovery.GetServicesResponse, error) {
+func (ds *MetadataManager) GetServices(ctx context.Context, request *discovery.GetServicesRequest,
+	opts ...datasource.ListOption) (*discovery.GetServicesResponse, error) {
 	domain := util.ParseDomain(ctx)
 	project := util.ParseProject(ctx)
 
 	filter := bson.M{model.ColumnDomain: domain, model.ColumnProject: project}
 
-	services, err := dao.GetMicroServices(ctx, filter)
+	o := datasource.ToListOptions(opts...)
+	findOpts, inDB := findOptions(o, serviceSortColumns, datasource.SortByServiceID)
+	if !inDB {
+		// sort and page in memory
+		findOpts = options.Find()
+	}
+	services, err := dao.GetMicroServices(ctx, filter, findOpts)
 	if err != nil {
 		return &discovery.GetServicesResponse{
 			Response: discovery.CreateResponse(discovery.ErrInternal, "get services data failed."),
 		}, nil
 	}
+	total := int64(len(services))
+	if inDB && o.Paged() {
+		total, err = dao.CountService(ctx, filter)
+		if err != nil {
+			return &discovery.GetServicesResponse{
+				Response: discovery.CreateResponse(discovery.ErrInternal, "count services failed."),
+			}, nil
+		}
+	}
+	if !inDB {
+		services, err = datasource.PageServices(services, o)
+		if err != nil {
+			return &discovery.GetServicesResponse{
+				Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+			}, nil
+		}
+	}
+	_ = util.WithResponseTotal(ctx, total)
 
 	return &discovery.GetServicesResponse{
 		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Get all services successfully."),
@@ -1668,7 +1693,8 @@ func (ds *MetadataManager) GetInstance(ctx context.Context, request *discovery.G
 	}, nil
 }
 
-func (ds *MetadataManager) GetInstances(ctx context.Context, request *discovery.GetInstancesRequest) (*discovery.GetInstancesResponse, error) {
+func (ds *MetadataManager) GetInstances(ctx context.Context, request *discovery.GetInstancesRequest,
+	opts ...datasource.ListOption) (*discovery.GetInstancesResponse, error) {
 	service := &model.Service{}
 	var err error
 
@@ -1741,10 +1767,17 @@ func (ds *MetadataManager) GetInstances(ctx context.Context, request *discovery.
 		}
 	}
 	newRev, _ := formatRevision(request.ConsumerServiceId, instances)
+	_ = util.WithResponseTotal(ctx, int64(len(instances)))
 	if rev == newRev {
 		instances = nil // for gRPC
 	}
 	_ = util.WithResponseRev(ctx, newRev)
+	instances, err = datasource.PageInstances(instances, datasource.ToListOptions(opts...))
+	if err != nil {
+		return &discovery.GetInstancesResponse{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+		}, nil
+	}
 	return &discovery.GetInstancesResponse{
 		Response:  discovery.CreateResponse(discovery.ResponseSuccess, "Query service instances successfully."),
 		Instances: instances,
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type PasswordHistoryManager struct {
+}
+
+func (pm *PasswordHistoryManager) GetPasswordHistory(ctx context.Context, account string) (*datasource.PasswordHistory, error) {
+	filter := mutil.NewFilter(mutil.PasswordAccount(account))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionPasswordHistory, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrPasswordHistoryNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query password history of account %s", account), err)
+		return nil, err
+	}
+	h := &datasource.PasswordHistory{}
+	err = result.Decode(h)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode password history of account %s", account), err)
+		return nil, err
+	}
+	return h, nil
+}
+
+func (pm *PasswordHistoryManager) PutPasswordHistory(ctx context.Context, h *datasource.PasswordHistory) error {
+	filter := mutil.NewFilter(mutil.PasswordAccount(h.Account))
+	update := mutil.NewFilter(mutil.Set(h))
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionPasswordHistory, filter, update,
+		options.Update().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save password history of account %s", h.Account), err)
+		return err
+	}
+	return nil
+}
+
+func (pm *PasswordHistoryManager) DeletePasswordHistory(ctx context.Context, account string) error {
+	filter := mutil.NewFilter(mutil.PasswordAccount(account))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionPasswordHistory, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete password history of account %s", account), err)
+		return err
+	}
+	return nil
+}
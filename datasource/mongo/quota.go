@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+)
+
+type QuotaManager struct {
+}
+
+func (qm *QuotaManager) GetQuota(ctx context.Context, domain, project string) (*quota.Quota, error) {
+	filter := mutil.NewDomainProjectFilter(domain, project)
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionQuota, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrQuotaNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query quota of %s/%s", domain, project), err)
+		return nil, err
+	}
+	q := &quota.Quota{}
+	err = result.Decode(q)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode quota of %s/%s", domain, project), err)
+		return nil, err
+	}
+	return q, nil
+}
+
+func (qm *QuotaManager) PutQuota(ctx context.Context, q *quota.Quota) error {
+	filter := mutil.NewDomainProjectFilter(q.Domain, q.Project)
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.QuotaLimits(q.Limits))))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionQuota, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save quota of %s/%s", q.Domain, q.Project), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save quota of %s/%s", q.Domain, q.Project), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (qm *QuotaManager) DeleteQuota(ctx context.Context, domain, project string) error {
+	filter := mutil.NewDomainProjectFilter(domain, project)
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionQuota, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove quota of %s/%s", domain, project), err)
+		return err
+	}
+	return nil
+}
@@ -19,11 +19,13 @@ package mongo
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/go-chassis/cari/rbac"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
@@ -36,20 +38,26 @@ import (
 type RoleManager struct {
 }
 
-func (ds *RoleManager) CreateRole(ctx context.Context, r *rbac.Role) error {
-	exist, err := ds.RoleExist(ctx, r.Name)
+// roleWithParents is the role document inserted with the parents
+type roleWithParents struct {
+	rbac.Role `bson:",inline"`
+	Parents   []string `bson:"parents,omitempty"`
+}
+
+// CreateRole inserts the role with the parents as one document, the role is
+// added to the children of the parents first, so the parents can not be
+// deleted by DeleteRole at the same time
+func (ds *RoleManager) CreateRole(ctx context.Context, r *rbac.Role, parents ...string) error {
+	err := addChild(ctx, r.Name, parents)
 	if err != nil {
-		log.Error("failed to query role", err)
 		return err
 	}
-	if exist {
-		return datasource.ErrRoleDuplicated
-	}
 	r.ID = util.GenerateUUID()
 	r.CreateTime = strconv.FormatInt(time.Now().Unix(), 10)
 	r.UpdateTime = r.CreateTime
-	_, err = client.GetMongoClient().Insert(ctx, model.CollectionRole, r)
+	_, err = client.GetMongoClient().Insert(ctx, model.CollectionRole, &roleWithParents{Role: *r, Parents: parents})
 	if err != nil {
+		removeChild(ctx, r.Name, parents)
 		if client.IsDuplicateKey(err) {
 			return datasource.ErrRoleDuplicated
 		}
@@ -59,6 +67,39 @@ func (ds *RoleManager) CreateRole(ctx context.Context, r *rbac.Role) error {
 	return nil
 }
 
+// addChild adds the role to the children of the parents, it fails if any
+// parent does not exist
+func addChild(ctx context.Context, name string, parents []string) error {
+	if len(parents) == 0 {
+		return nil
+	}
+	filter := bson.M{model.ColumnRoleName: bson.M{"$in": parents}}
+	update := mutil.NewFilter(mutil.AddToSet(mutil.NewFilter(mutil.RoleChildren(name))))
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionRole, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < int64(len(parents)) {
+		removeChild(ctx, name, parents)
+		return datasource.ErrRoleNotExist
+	}
+	return nil
+}
+
+// removeChild removes the role from the children of the parents, the
+// failure only makes the parents not deletable, so it is logged only
+func removeChild(ctx context.Context, name string, parents []string) {
+	if len(parents) == 0 {
+		return
+	}
+	filter := bson.M{model.ColumnRoleName: bson.M{"$in": parents}}
+	update := mutil.NewFilter(mutil.Pull(mutil.NewFilter(mutil.RoleChildren(name))))
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionRole, filter, update)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove role %s from the children of %v", name, parents), err)
+	}
+}
+
 func (ds *RoleManager) RoleExist(ctx context.Context, name string) (bool, error) {
 	filter := mutil.NewFilter(mutil.RoleName(name))
 	count, err := client.GetMongoClient().Count(ctx, model.CollectionRole, filter)
@@ -89,9 +130,14 @@ func (ds *RoleManager) GetRole(ctx context.Context, name string) (*rbac.Role, er
 	return &role, nil
 }
 
-func (ds *RoleManager) ListRole(ctx context.Context) ([]*rbac.Role, int64, error) {
+func (ds *RoleManager) ListRole(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Role, int64, error) {
 	filter := mutil.NewFilter()
-	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRole, filter)
+	o := datasource.ToListOptions(opts...)
+	findOpts, ok := findOptions(o, rbacSortColumns, datasource.SortByName)
+	if !ok {
+		return nil, 0, datasource.ErrInvalidSortKey
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRole, filter, findOpts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -106,9 +152,20 @@ func (ds *RoleManager) ListRole(ctx context.Context) ([]*rbac.Role, int64, error
 		}
 		roles = append(roles, &role)
 	}
-	return roles, int64(len(roles)), nil
+	if !o.Paged() {
+		return roles, int64(len(roles)), nil
+	}
+	total, err := client.Count(ctx, model.CollectionRole, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return roles, total, nil
 }
 
+// DeleteRole removes the role in a txn, it conflicts with the concurrent
+// creation of the roles inheriting it
+// DeleteRole removes the role only if it has no children, the filter is
+// checked with the deletion atomically
 func (ds *RoleManager) DeleteRole(ctx context.Context, name string) (bool, error) {
 	n, err := client.Count(ctx, model.CollectionAccount, bson.M{"roles": bson.M{"$in": []string{name}}})
 	if err != nil {
@@ -117,14 +174,32 @@ func (ds *RoleManager) DeleteRole(ctx context.Context, name string) (bool, error
 	if n > 0 {
 		return false, datasource.ErrRoleBindingExist
 	}
+	// the roles saved without the children
+	n, err = client.Count(ctx, model.CollectionRole, mutil.NewFilter(mutil.RoleParents(bson.M{"$in": []string{name}})))
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return false, datasource.ErrRoleInherited
+	}
+	parents, err := ds.GetRoleParents(ctx, name)
+	if err != nil {
+		return false, err
+	}
 	filter := mutil.NewFilter(mutil.RoleName(name))
+	filter[mutil.ConnectWithDot([]string{model.ColumnRoleChildren, "0"})] = bson.M{"$exists": false}
 	result, err := client.DeleteDoc(ctx, model.CollectionRole, filter)
 	if err != nil {
 		return false, err
 	}
 	if result.DeletedCount == 0 {
+		exist, err := ds.RoleExist(ctx, name)
+		if err == nil && exist {
+			return false, datasource.ErrRoleInherited
+		}
 		return false, nil
 	}
+	removeChild(ctx, name, parents)
 	return true, nil
 }
 
@@ -143,3 +218,87 @@ func (ds *RoleManager) UpdateRole(ctx context.Context, name string, role *rbac.R
 	}
 	return nil
 }
+
+// roleParents is the role document with the parents only
+type roleParents struct {
+	Name    string   `bson:"name"`
+	Parents []string `bson:"parents"`
+}
+
+func (ds *RoleManager) GetRoleParents(ctx context.Context, name string) ([]string, error) {
+	filter := mutil.NewFilter(mutil.RoleName(name))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionRole, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, result.Err()
+	}
+	var r roleParents
+	err = result.Decode(&r)
+	if err != nil {
+		log.Error("failed to decode role", err)
+		return nil, err
+	}
+	return r.Parents, nil
+}
+
+// SetRoleParents replaces the parents in a txn, the same as CreateRole, it
+// fails if any parent does not exist
+// SetRoleParents adds the role to the children of the new parents before
+// saving the parents, then removes it from the children of the old ones
+func (ds *RoleManager) SetRoleParents(ctx context.Context, name string, parents []string) error {
+	old, err := ds.GetRoleParents(ctx, name)
+	if err != nil {
+		return err
+	}
+	err = addChild(ctx, name, parents)
+	if err != nil {
+		return err
+	}
+	filter := mutil.NewFilter(mutil.RoleName(name))
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.RoleParents(parents))))
+	_, err = client.GetMongoClient().Update(ctx, model.CollectionRole, filter, update)
+	if err != nil {
+		removeChild(ctx, name, excludeRoles(parents, old))
+		return err
+	}
+	removeChild(ctx, name, excludeRoles(old, parents))
+	return nil
+}
+
+// excludeRoles returns the roles not in the excluded
+func excludeRoles(roles, excluded []string) []string {
+	r := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !util.SliceHave(excluded, role) {
+			r = append(r, role)
+		}
+	}
+	return r
+}
+
+func (ds *RoleManager) ListRoleParents(ctx context.Context) (map[string][]string, error) {
+	filter := mutil.NewFilter(mutil.RoleParents(bson.M{"$exists": true, "$ne": bson.A{}}))
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRole, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	all := make(map[string][]string)
+	for cursor.Next(ctx) {
+		var r roleParents
+		err = cursor.Decode(&r)
+		if err != nil {
+			log.Error("failed to decode role", err)
+			continue
+		}
+		if len(r.Parents) > 0 {
+			all[r.Name] = r.Parents
+		}
+	}
+	return all, nil
+}
@@ -19,18 +19,34 @@ package mongo
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/patrickmn/go-cache"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/dlock"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/sd"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
 type SysManager struct {
+	lockMux sync.Mutex
+	locks   map[string]*dlock.DLock
+}
+
+func newSysManager() datasource.SystemManager {
+	return &SysManager{
+		locks: make(map[string]*dlock.DLock),
+	}
 }
 
 func (ds *SysManager) DumpCache(ctx context.Context) *dump.Cache {
@@ -43,10 +59,75 @@ func (ds *SysManager) DumpCache(ctx context.Context) *dump.Cache {
 }
 
 func (ds *SysManager) DLock(ctx context.Context, request *datasource.DLockRequest) error {
+	// do not hold lockMux while acquiring, a waiting acquirement would block
+	// DUnlock of the holder forever
+	lock, err := dlock.Lock(ctx, request.ID, dlock.DefaultLockTTL, request.Wait)
+	if err != nil {
+		return err
+	}
+
+	ds.lockMux.Lock()
+	ds.locks[request.ID] = lock
+	ds.lockMux.Unlock()
 	return nil
 }
 
 func (ds *SysManager) DUnlock(ctx context.Context, request *datasource.DUnlockRequest) error {
+	ds.lockMux.Lock()
+	defer ds.lockMux.Unlock()
+
+	lock, ok := ds.locks[request.ID]
+	if !ok {
+		return datasource.ErrDLockNotFound
+	}
+	delete(ds.locks, request.ID)
+	return lock.Unlock()
+}
+
+func (ds *SysManager) GetCheckpoint(ctx context.Context, task string) ([]byte, error) {
+	filter := mutil.NewFilter(mutil.CheckpointTask(task))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionCheckpoint, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		log.Error(fmt.Sprintf("failed to query checkpoint of %s", task), err)
+		return nil, err
+	}
+	cp := &model.Checkpoint{}
+	if err = result.Decode(cp); err != nil {
+		log.Error(fmt.Sprintf("failed to decode checkpoint of %s", task), err)
+		return nil, err
+	}
+	return cp.Data, nil
+}
+
+func (ds *SysManager) PutCheckpoint(ctx context.Context, task string, data []byte) error {
+	filter := mutil.NewFilter(mutil.CheckpointTask(task))
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.CheckpointData(data))))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionCheckpoint, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save checkpoint of %s", task), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save checkpoint of %s", task), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (ds *SysManager) DeleteCheckpoint(ctx context.Context, task string) error {
+	filter := mutil.NewFilter(mutil.CheckpointTask(task))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionCheckpoint, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not remove checkpoint of %s", task), err)
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type TokenManager struct {
+}
+
+// RevokeToken saves the revoked token and removes the expired ones
+func (tm *TokenManager) RevokeToken(ctx context.Context, t *datasource.RevokedToken) error {
+	now := time.Now().Unix()
+	if t.ExpireAt > 0 && t.ExpireAt <= now {
+		return nil
+	}
+	filter := mutil.NewFilter(mutil.TokenHash(t.Hash))
+	update := mutil.NewFilter(mutil.Set(t))
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionRevokedToken, filter, update,
+		options.Update().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save the revoked token of account %s", t.Account), err)
+		return err
+	}
+	expired := mutil.NewFilter(mutil.TokenExpireAt(mutil.NewFilter(mutil.Gt(0), mutil.Lt(now))))
+	if _, err := client.GetMongoClient().Delete(ctx, model.CollectionRevokedToken, expired); err != nil {
+		log.Error("can not remove the expired revoked tokens", err)
+	}
+	return nil
+}
+
+func (tm *TokenManager) TokenRevoked(ctx context.Context, hash string) (bool, error) {
+	filter := mutil.NewFilter(mutil.TokenHash(hash))
+	n, err := client.Count(ctx, model.CollectionRevokedToken, filter)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (tm *TokenManager) GetGeneration(ctx context.Context, account string) (int64, error) {
+	filter := mutil.NewFilter(mutil.TokenAccount(account))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionTokenGeneration, filter)
+	if err != nil {
+		return 0, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		log.Error(fmt.Sprintf("failed to query token generation of account %s", account), err)
+		return 0, err
+	}
+	g := &datasource.TokenGeneration{}
+	err = result.Decode(g)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode token generation of account %s", account), err)
+		return 0, err
+	}
+	return g.Generation, nil
+}
+
+func (tm *TokenManager) IncGeneration(ctx context.Context, account string) (int64, error) {
+	filter := mutil.NewFilter(mutil.TokenAccount(account))
+	update := mutil.NewFilter(mutil.Inc(mutil.NewFilter(mutil.TokenGeneration(1))))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionTokenGeneration, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save token generation of account %s", account), err)
+		return 0, err
+	}
+	g := &datasource.TokenGeneration{}
+	err = result.Decode(g)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to decode token generation of account %s", account), err)
+		return 0, err
+	}
+	return g.Generation, nil
+}
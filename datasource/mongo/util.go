@@ -20,8 +20,12 @@ package mongo
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/dao"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
 	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
 	"github.com/apache/servicecomb-service-center/pkg/gopool"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -101,3 +105,48 @@ func getInstanceCountByDomain(ctx context.Context, svcIDToNonVerKey map[string]s
 	}
 	resp <- ret
 }
+
+var (
+	serviceSortColumns = map[string]string{
+		datasource.SortByServiceID:    mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnServiceID}),
+		datasource.SortByServiceName:  mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnServiceName}),
+		datasource.SortByAppID:        mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnAppID}),
+		datasource.SortByTimestamp:    mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnTimestamp}),
+		datasource.SortByModTimestamp: mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnModTime}),
+	}
+	rbacSortColumns = map[string]string{
+		datasource.SortByName:       model.ColumnAccountName,
+		datasource.SortByCreateTime: model.ColumnCreateTime,
+		datasource.SortByUpdateTime: model.ColumnAccountUpdateTime,
+	}
+)
+
+// findOptions converts the list options to the find options which sort and
+// page in db, ok is false if the sort key is not in columns, e.g. the
+// version which can not be compared as string
+func findOptions(o datasource.ListOptions, columns map[string]string, defaultKey string) (opts *options.FindOptions, ok bool) {
+	if !o.Paged() {
+		return options.Find(), true
+	}
+	sortBy := o.SortBy
+	if len(sortBy) == 0 {
+		sortBy = defaultKey
+	}
+	column, ok := columns[sortBy]
+	if !ok {
+		return nil, false
+	}
+	order := 1
+	if o.Desc {
+		order = -1
+	}
+	sort := bson.D{{Key: column, Value: order}}
+	if unique := columns[defaultKey]; unique != column {
+		sort = append(sort, bson.E{Key: unique, Value: 1})
+	}
+	opts = options.Find().SetSort(sort).SetSkip(o.Offset)
+	if o.Limit > 0 {
+		opts.SetLimit(o.Limit)
+	}
+	return opts, true
+}
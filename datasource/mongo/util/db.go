@@ -92,6 +92,18 @@ func RoleName(name string) Option {
 	}
 }
 
+func RoleParents(parents interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnRoleParents] = parents
+	}
+}
+
+func RoleChildren(children interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnRoleChildren] = children
+	}
+}
+
 func Perms(perms []*rbac.Permission) Option {
 	return func(filter bson.M) {
 		filter[model.ColumnPerms] = perms
@@ -128,6 +140,149 @@ func AccountLockReleaseAt(releaseAt interface{}) Option {
 	}
 }
 
+func DLockKey(key interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnDLockKey] = key
+	}
+}
+
+func DLockOwner(owner interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnDLockOwner] = owner
+	}
+}
+
+func DLockToken(token interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnDLockToken] = token
+	}
+}
+
+func DLockExpireAt(expireAt interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnDLockExpireAt] = expireAt
+	}
+}
+
+func QuotaLimits(limits interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnQuotaLimits] = limits
+	}
+}
+
+func APIKeyID(id interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyID] = id
+	}
+}
+
+func APIKeyAccount(account interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyAccount] = account
+	}
+}
+
+func TokenHash(hash interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTokenHash] = hash
+	}
+}
+
+func TokenExpireAt(expireAt interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTokenExpireAt] = expireAt
+	}
+}
+
+func TokenAccount(account interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTokenAccount] = account
+	}
+}
+
+func TokenGeneration(generation interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTokenGeneration] = generation
+	}
+}
+
+func PasswordAccount(account interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnPasswordAccount] = account
+	}
+}
+
+func AuditTimestamp(timestamp interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAuditTimestamp] = timestamp
+	}
+}
+
+func AuditActor(actor interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAuditActor] = actor
+	}
+}
+
+func AuditResource(resource interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAuditResource] = resource
+	}
+}
+
+// AuditResourceID matches the records having the id in any value of the
+// resource IDs
+func AuditResourceID(id string) Option {
+	return func(filter bson.M) {
+		filter["$expr"] = bson.M{"$in": bson.A{id, bson.M{"$map": bson.M{
+			"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$" + model.ColumnAuditResourceIDs, bson.M{}}}},
+			"in":    "$$this.v",
+		}}}}
+	}
+}
+
+func CheckpointTask(task interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnCheckpointTask] = task
+	}
+}
+
+func CheckpointData(data interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnCheckpointData] = data
+	}
+}
+
+func GovProject(project interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnGovProject] = project
+	}
+}
+
+func GovKind(kind interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnGovKind] = kind
+	}
+}
+
+func GovID(id interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnGovID] = id
+	}
+}
+
+func GovPolicyID(id interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnGovPolicyID] = id
+	}
+}
+
+func GovRevision(revision interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnGovRevision] = revision
+	}
+}
+
 func In(data interface{}) Option {
 	return func(filter bson.M) {
 		filter["$in"] = data
@@ -146,6 +301,42 @@ func Set(data interface{}) Option {
 	}
 }
 
+func Inc(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$inc"] = data
+	}
+}
+
+func AddToSet(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$addToSet"] = data
+	}
+}
+
+func Pull(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$pull"] = data
+	}
+}
+
+func Gt(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$gt"] = data
+	}
+}
+
+func Gte(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$gte"] = data
+	}
+}
+
+func Lt(data interface{}) Option {
+	return func(filter bson.M) {
+		filter["$lt"] = data
+	}
+}
+
 func Nor(options ...Option) Option {
 	return func(filter bson.M) {
 		filter["$nor"] = bson.A{NewFilter(options...)}
@@ -41,7 +41,9 @@ const (
 type MetadataManager interface {
 	// Microservice management
 	RegisterService(ctx context.Context, request *pb.CreateServiceRequest) (*pb.CreateServiceResponse, error)
-	GetServices(ctx context.Context, request *pb.GetServicesRequest) (*pb.GetServicesResponse, error)
+	// GetServices returns the services of the domain project, the total
+	// count before paging is set in ctx by util.WithResponseTotal
+	GetServices(ctx context.Context, request *pb.GetServicesRequest, opts ...ListOption) (*pb.GetServicesResponse, error)
 	GetService(ctx context.Context, request *pb.GetServiceRequest) (*pb.GetServiceResponse, error)
 
 	GetServiceDetail(ctx context.Context, request *pb.GetServiceRequest) (*pb.GetServiceDetailResponse, error)
@@ -57,13 +59,18 @@ type MetadataManager interface {
 		serviceRespChan chan<- *pb.DelServicesRspInfo) func(context.Context)
 	GetServiceCount(ctx context.Context,
 		request *pb.GetServiceCountRequest) (*pb.GetServiceCountResponse, error)
+	// ListServicesAcrossDomainProject returns services of all domains, projects,
+	// the map's key is domainProject
+	ListServicesAcrossDomainProject(ctx context.Context) (map[string][]*pb.MicroService, error)
 
 	// Instance management
 	RegisterInstance(ctx context.Context, request *pb.RegisterInstanceRequest) (*pb.RegisterInstanceResponse, error)
 	ExistInstanceByID(ctx context.Context, request *pb.MicroServiceInstanceKey) (*pb.GetExistenceByIDResponse, error)
 	// GetInstances returns instances under the specified service
 	GetInstance(ctx context.Context, request *pb.GetOneInstanceRequest) (*pb.GetOneInstanceResponse, error)
-	GetInstances(ctx context.Context, request *pb.GetInstancesRequest) (*pb.GetInstancesResponse, error)
+	// GetInstances returns the instances of the provider, the total count
+	// before paging is set in ctx by util.WithResponseTotal
+	GetInstances(ctx context.Context, request *pb.GetInstancesRequest, opts ...ListOption) (*pb.GetInstancesResponse, error)
 	// GetProviderInstances returns instances under the specified service
 	GetProviderInstances(ctx context.Context,
 		request *pb.GetProviderInstancesRequest) (instances []*pb.MicroServiceInstance, rev string, err error)
@@ -0,0 +1,257 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/pkg/validate"
+)
+
+const (
+	// MaxPageSize limits the records returned in one page
+	MaxPageSize = 1000
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+
+	SortByServiceID    = "serviceId"
+	SortByServiceName  = "serviceName"
+	SortByAppID        = "appId"
+	SortByVersion      = "version"
+	SortByInstanceID   = "instanceId"
+	SortByHostName     = "hostName"
+	SortByStatus       = "status"
+	SortByTimestamp    = "timestamp"
+	SortByModTimestamp = "modTimestamp"
+	SortByName         = "name"
+	SortByCreateTime   = "createTime"
+	SortByUpdateTime   = "updateTime"
+)
+
+var (
+	ErrInvalidPage    = fmt.Errorf("offset must not be negative and limit must be in [0, %d]", MaxPageSize)
+	ErrInvalidOrder   = errors.New("order must be asc or desc")
+	ErrInvalidSortKey = errors.New("unsupported sort key")
+)
+
+// ListOptions is the pagination and sorting of the list APIs, the records
+// are sorted before paging, so a page is stable if the data is not changed
+type ListOptions struct {
+	// Offset is the number of the records to skip
+	Offset int64
+	// Limit is the max number of the records returned, 0 means no limit
+	Limit int64
+	// SortBy is the sort key, the default one is the unique key of the
+	// resource, e.g. serviceId
+	SortBy string
+	Desc   bool
+}
+
+type ListOption func(*ListOptions)
+
+func WithOffset(offset int64) ListOption { return func(o *ListOptions) { o.Offset = offset } }
+func WithLimit(limit int64) ListOption   { return func(o *ListOptions) { o.Limit = limit } }
+func WithSortBy(key string) ListOption   { return func(o *ListOptions) { o.SortBy = key } }
+func WithDescOrder() ListOption          { return func(o *ListOptions) { o.Desc = true } }
+
+func ToListOptions(opts ...ListOption) (o ListOptions) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// Paged returns false if no option is set, the list APIs return all
+// records in the storage order to keep compatible
+func (o ListOptions) Paged() bool {
+	return o.Offset > 0 || o.Limit > 0 || len(o.SortBy) > 0 || o.Desc
+}
+
+// Range returns the index range [start, end) of the page in total records
+func (o ListOptions) Range(total int) (start, end int) {
+	start, end = int(o.Offset), total
+	if start > total {
+		start = total
+	}
+	if o.Limit > 0 && int64(end-start) > o.Limit {
+		end = start + int(o.Limit)
+	}
+	return
+}
+
+// ParseListOptions parses the query parameters offset, limit, sortBy and
+// order of the list APIs
+func ParseListOptions(query url.Values) ([]ListOption, error) {
+	var opts []ListOption
+	for key, f := range map[string]func(int64) ListOption{"offset": WithOffset, "limit": WithLimit} {
+		s := query.Get(key)
+		if len(s) == 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 || (key == "limit" && n > MaxPageSize) {
+			return nil, ErrInvalidPage
+		}
+		opts = append(opts, f(n))
+	}
+	if s := query.Get("sortBy"); len(s) > 0 {
+		opts = append(opts, WithSortBy(s))
+	}
+	switch query.Get("order") {
+	case "", OrderAsc:
+	case OrderDesc:
+		opts = append(opts, WithDescOrder())
+	default:
+		return nil, ErrInvalidOrder
+	}
+	return opts, nil
+}
+
+// less compares the sort key of record i and j, the unique key is compared
+// if the sort keys are equal
+type less func(i, j int) (ok bool, equal bool)
+
+func byString(a, b string) (bool, bool) { return a < b, a == b }
+
+func byInt(a, b string) (bool, bool) {
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	return x < y, x == y
+}
+
+func byVersion(a, b string) (bool, bool) {
+	x, _ := validate.VersionToInt64(a)
+	y, _ := validate.VersionToInt64(b)
+	return x < y, x == y
+}
+
+func sortSlice(slice interface{}, desc bool, key less, unique func(i, j int) bool) {
+	sort.Slice(slice, func(i, j int) bool {
+		ok, equal := key(i, j)
+		if equal {
+			return unique(i, j)
+		}
+		return ok != desc
+	})
+}
+
+// PageServices sorts the services and returns the page, the input slice is
+// not changed as it may be shared with the cache
+func PageServices(services []*pb.MicroService, o ListOptions) ([]*pb.MicroService, error) {
+	if !o.Paged() {
+		return services, nil
+	}
+	s := append([]*pb.MicroService(nil), services...)
+	var key less
+	switch o.SortBy {
+	case "", SortByServiceID:
+		key = func(i, j int) (bool, bool) { return byString(s[i].ServiceId, s[j].ServiceId) }
+	case SortByServiceName:
+		key = func(i, j int) (bool, bool) { return byString(s[i].ServiceName, s[j].ServiceName) }
+	case SortByAppID:
+		key = func(i, j int) (bool, bool) { return byString(s[i].AppId, s[j].AppId) }
+	case SortByVersion:
+		key = func(i, j int) (bool, bool) { return byVersion(s[i].Version, s[j].Version) }
+	case SortByTimestamp:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].Timestamp, s[j].Timestamp) }
+	case SortByModTimestamp:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].ModTimestamp, s[j].ModTimestamp) }
+	default:
+		return nil, ErrInvalidSortKey
+	}
+	sortSlice(s, o.Desc, key, func(i, j int) bool { return s[i].ServiceId < s[j].ServiceId })
+	start, end := o.Range(len(s))
+	return s[start:end], nil
+}
+
+// PageInstances sorts the instances and returns the page, the input slice
+// is not changed as it may be shared with the cache
+func PageInstances(instances []*pb.MicroServiceInstance, o ListOptions) ([]*pb.MicroServiceInstance, error) {
+	if !o.Paged() {
+		return instances, nil
+	}
+	s := append([]*pb.MicroServiceInstance(nil), instances...)
+	var key less
+	switch o.SortBy {
+	case "", SortByInstanceID:
+		key = func(i, j int) (bool, bool) { return byString(s[i].InstanceId, s[j].InstanceId) }
+	case SortByHostName:
+		key = func(i, j int) (bool, bool) { return byString(s[i].HostName, s[j].HostName) }
+	case SortByStatus:
+		key = func(i, j int) (bool, bool) { return byString(s[i].Status, s[j].Status) }
+	case SortByTimestamp:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].Timestamp, s[j].Timestamp) }
+	case SortByModTimestamp:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].ModTimestamp, s[j].ModTimestamp) }
+	default:
+		return nil, ErrInvalidSortKey
+	}
+	sortSlice(s, o.Desc, key, func(i, j int) bool { return s[i].InstanceId < s[j].InstanceId })
+	start, end := o.Range(len(s))
+	return s[start:end], nil
+}
+
+func PageAccounts(accounts []*rbac.Account, o ListOptions) ([]*rbac.Account, error) {
+	if !o.Paged() {
+		return accounts, nil
+	}
+	s := append([]*rbac.Account(nil), accounts...)
+	var key less
+	switch o.SortBy {
+	case "", SortByName:
+		key = func(i, j int) (bool, bool) { return byString(s[i].Name, s[j].Name) }
+	case SortByCreateTime:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].CreateTime, s[j].CreateTime) }
+	case SortByUpdateTime:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].UpdateTime, s[j].UpdateTime) }
+	default:
+		return nil, ErrInvalidSortKey
+	}
+	sortSlice(s, o.Desc, key, func(i, j int) bool { return s[i].Name < s[j].Name })
+	start, end := o.Range(len(s))
+	return s[start:end], nil
+}
+
+func PageRoles(roles []*rbac.Role, o ListOptions) ([]*rbac.Role, error) {
+	if !o.Paged() {
+		return roles, nil
+	}
+	s := append([]*rbac.Role(nil), roles...)
+	var key less
+	switch o.SortBy {
+	case "", SortByName:
+		key = func(i, j int) (bool, bool) { return byString(s[i].Name, s[j].Name) }
+	case SortByCreateTime:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].CreateTime, s[j].CreateTime) }
+	case SortByUpdateTime:
+		key = func(i, j int) (bool, bool) { return byInt(s[i].UpdateTime, s[j].UpdateTime) }
+	default:
+		return nil, ErrInvalidSortKey
+	}
+	sortSlice(s, o.Desc, key, func(i, j int) bool { return s[i].Name < s[j].Name })
+	start, end := o.Range(len(s))
+	return s[start:end], nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func TestParseListOptions(t *testing.T) {
+	t.Run("parse all options, should pass", func(t *testing.T) {
+		opts, err := datasource.ParseListOptions(url.Values{
+			"offset": {"10"}, "limit": {"20"}, "sortBy": {"serviceName"}, "order": {"desc"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, datasource.ListOptions{Offset: 10, Limit: 20, SortBy: "serviceName", Desc: true},
+			datasource.ToListOptions(opts...))
+	})
+
+	t.Run("parse empty query, should not be paged", func(t *testing.T) {
+		opts, err := datasource.ParseListOptions(url.Values{})
+		assert.NoError(t, err)
+		assert.False(t, datasource.ToListOptions(opts...).Paged())
+	})
+
+	t.Run("parse invalid options, should fail", func(t *testing.T) {
+		for _, query := range []url.Values{
+			{"offset": {"-1"}},
+			{"offset": {"x"}},
+			{"limit": {"1001"}},
+			{"order": {"x"}},
+		} {
+			_, err := datasource.ParseListOptions(query)
+			assert.Error(t, err, query.Encode())
+		}
+	})
+}
+
+func TestPageServices(t *testing.T) {
+	services := []*pb.MicroService{
+		{ServiceId: "3", ServiceName: "a", Version: "1.10.0"},
+		{ServiceId: "1", ServiceName: "b", Version: "1.9.0"},
+		{ServiceId: "2", ServiceName: "a", Version: "1.0.0"},
+	}
+	ids := func(services []*pb.MicroService) (s string) {
+		for _, service := range services {
+			s += service.ServiceId
+		}
+		return
+	}
+
+	t.Run("no option, should return as is", func(t *testing.T) {
+		page, err := datasource.PageServices(services, datasource.ToListOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, "312", ids(page))
+	})
+
+	t.Run("page by default sort key, should sorted by id", func(t *testing.T) {
+		page, err := datasource.PageServices(services, datasource.ToListOptions(datasource.WithLimit(2)))
+		assert.NoError(t, err)
+		assert.Equal(t, "12", ids(page))
+		page, err = datasource.PageServices(services, datasource.ToListOptions(datasource.WithOffset(2),
+			datasource.WithLimit(2)))
+		assert.NoError(t, err)
+		assert.Equal(t, "3", ids(page))
+		page, err = datasource.PageServices(services, datasource.ToListOptions(datasource.WithOffset(5)))
+		assert.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Equal(t, "312", ids(services))
+	})
+
+	t.Run("sort by name and version, should pass", func(t *testing.T) {
+		page, err := datasource.PageServices(services, datasource.ToListOptions(
+			datasource.WithSortBy(datasource.SortByServiceName), datasource.WithDescOrder()))
+		assert.NoError(t, err)
+		assert.Equal(t, "123", ids(page))
+		page, err = datasource.PageServices(services, datasource.ToListOptions(
+			datasource.WithSortBy(datasource.SortByVersion)))
+		assert.NoError(t, err)
+		assert.Equal(t, "213", ids(page))
+	})
+
+	t.Run("sort by invalid key, should fail", func(t *testing.T) {
+		_, err := datasource.PageServices(services, datasource.ToListOptions(datasource.WithSortBy("x")))
+		assert.Equal(t, datasource.ErrInvalidSortKey, err)
+	})
+}
+
+func TestListPaged(t *testing.T) {
+	t.Run("list services by page, should return total", func(t *testing.T) {
+		ctx := util.SetDomainProject(context.Background(), "page", "page")
+		for _, name := range []string{"page-c", "page-a", "page-b"} {
+			resp, err := datasource.GetMetadataManager().RegisterService(ctx, &pb.CreateServiceRequest{
+				Service: &pb.MicroService{AppId: "page", ServiceName: name, Version: "1.0.0"},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+			defer datasource.GetMetadataManager().UnregisterService(ctx,
+				&pb.DeleteServiceRequest{ServiceId: resp.ServiceId, Force: true})
+		}
+
+		// the services are listed from the cache
+		assert.Eventually(t, func() bool {
+			resp, err := datasource.GetMetadataManager().GetServices(ctx, &pb.GetServicesRequest{})
+			return err == nil && len(resp.Services) == 3
+		}, 3*time.Second, 10*time.Millisecond)
+
+		resp, err := datasource.GetMetadataManager().GetServices(ctx, &pb.GetServicesRequest{},
+			datasource.WithSortBy(datasource.SortByServiceName), datasource.WithOffset(1), datasource.WithLimit(1))
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+		if assert.Equal(t, 1, len(resp.Services)) {
+			assert.Equal(t, "page-b", resp.Services[0].ServiceName)
+		}
+		total, ok := util.ResponseTotal(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, int64(3), total)
+
+		resp, err = datasource.GetMetadataManager().GetServices(ctx, &pb.GetServicesRequest{},
+			datasource.WithSortBy("x"))
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ErrInvalidParams, resp.Response.GetCode())
+	})
+
+	t.Run("list roles by page, should return total", func(t *testing.T) {
+		ctx := context.Background()
+		for _, name := range []string{"page-role-b", "page-role-a"} {
+			err := datasource.GetRoleManager().CreateRole(ctx, &rbac.Role{Name: name})
+			assert.NoError(t, err)
+			defer datasource.GetRoleManager().DeleteRole(ctx, name)
+		}
+		all, n, err := datasource.GetRoleManager().ListRole(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(all)), n)
+
+		roles, total, err := datasource.GetRoleManager().ListRole(ctx, datasource.WithLimit(1), datasource.WithDescOrder())
+		assert.NoError(t, err)
+		assert.Equal(t, n, total)
+		assert.Equal(t, 1, len(roles))
+
+		_, _, err = datasource.GetRoleManager().ListRole(ctx, datasource.WithSortBy("x"))
+		assert.Equal(t, datasource.ErrInvalidSortKey, err)
+	})
+}
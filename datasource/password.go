@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrPasswordHistoryNotExist = errors.New("password history not exist")
+)
+
+// PasswordHistoryManager saves the recent passwords of the accounts, they
+// are used to prevent the password reuse and expire the old passwords
+type PasswordHistoryManager interface {
+	GetPasswordHistory(ctx context.Context, account string) (*PasswordHistory, error)
+	PutPasswordHistory(ctx context.Context, h *PasswordHistory) error
+	DeletePasswordHistory(ctx context.Context, account string) error
+}
+
+type PasswordHistory struct {
+	Account string `json:"account" bson:"account"`
+	// Hashes are the hashes of the recent passwords, the latest first
+	Hashes []string `json:"hashes,omitempty" bson:"hashes"`
+	// ChangeTime is the unix time of the last password change
+	ChangeTime int64 `json:"changeTime" bson:"change_time"`
+}
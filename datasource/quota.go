@@ -0,0 +1,37 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+)
+
+var (
+	ErrQuotaNotExist = errors.New("quota not exist")
+)
+
+// QuotaManager saves the quota overrides of the domains and projects, the
+// project is empty for the domain override
+type QuotaManager interface {
+	GetQuota(ctx context.Context, domain, project string) (*quota.Quota, error)
+	PutQuota(ctx context.Context, q *quota.Quota) error
+	DeleteQuota(ctx context.Context, domain, project string) error
+}
@@ -20,6 +20,7 @@ package datasource
 import (
 	"context"
 	"errors"
+	"sort"
 
 	"github.com/go-chassis/cari/rbac"
 )
@@ -28,14 +29,63 @@ var (
 	ErrRoleDuplicated = errors.New("role is duplicated")
 	ErrRoleCanNotEdit = errors.New("role can not be edited")
 	ErrRoleNotExist   = errors.New("role not exist")
+	ErrRoleInherited  = errors.New("role is inherited by other roles")
 )
 
 // RoleManager contains the RBAC CRUD
 type RoleManager interface {
-	CreateRole(ctx context.Context, r *rbac.Role) error
+	// CreateRole creates the role inheriting the parents in one write
+	CreateRole(ctx context.Context, r *rbac.Role, parents ...string) error
 	RoleExist(ctx context.Context, name string) (bool, error)
 	GetRole(ctx context.Context, name string) (*rbac.Role, error)
-	ListRole(ctx context.Context) ([]*rbac.Role, int64, error)
+	// ListRole returns the page of roles and the total count
+	ListRole(ctx context.Context, opts ...ListOption) ([]*rbac.Role, int64, error)
 	DeleteRole(ctx context.Context, name string) (bool, error)
 	UpdateRole(ctx context.Context, name string, role *rbac.Role) error
+	// GetRoleParents returns the roles inherited by the role
+	GetRoleParents(ctx context.Context, name string) ([]string, error)
+	// SetRoleParents replaces the roles inherited by the role
+	SetRoleParents(ctx context.Context, name string, parents []string) error
+	// ListRoleParents returns the parents of all the roles inheriting others
+	ListRoleParents(ctx context.Context) (map[string][]string, error)
+}
+
+// SortRolesByParents returns the roles after the ones they inherit and
+// otherwise in name order, the roles in a cycle are appended at last
+func SortRolesByParents(names []string, parents map[string][]string) []string {
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+	exist := make(map[string]bool, len(names))
+	for _, name := range names {
+		exist[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	visited := make(map[string]bool, len(names))
+	ready := func(name string) bool {
+		for _, parent := range parents[name] {
+			if exist[parent] && !visited[parent] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(sorted) < len(names) {
+		n := len(sorted)
+		for _, name := range names {
+			if !visited[name] && ready(name) {
+				visited[name] = true
+				sorted = append(sorted, name)
+			}
+		}
+		if len(sorted) > n {
+			continue
+		}
+		for _, name := range names {
+			if !visited[name] {
+				visited[name] = true
+				sorted = append(sorted, name)
+			}
+		}
+	}
+	return sorted
 }
@@ -19,6 +19,7 @@ package datasource_test
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"testing"
 	"time"
@@ -96,6 +97,33 @@ func TestRole(t *testing.T) {
 		assert.Equal(t, int64(2), n)
 	})
 
+	t.Run("create role with parents, should save them together", func(t *testing.T) {
+		child := rbac.Role{Name: "test-role-child", Perms: r2.Perms}
+		err := datasource.GetRoleManager().CreateRole(context.Background(), &child, "test-role2")
+		assert.NoError(t, err)
+		parents, err := datasource.GetRoleManager().GetRoleParents(context.Background(), "test-role-child")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test-role2"}, parents)
+
+		_, err = datasource.GetRoleManager().DeleteRole(context.Background(), "test-role2")
+		assert.True(t, errors.Is(err, datasource.ErrRoleInherited))
+
+		_, err = datasource.GetRoleManager().DeleteRole(context.Background(), "test-role-child")
+		assert.NoError(t, err)
+		parents, err = datasource.GetRoleManager().GetRoleParents(context.Background(), "test-role-child")
+		assert.NoError(t, err)
+		assert.Empty(t, parents)
+	})
+
+	t.Run("create role with a missing parent, should fail", func(t *testing.T) {
+		orphan := rbac.Role{Name: "test-role-orphan", Perms: r2.Perms}
+		err := datasource.GetRoleManager().CreateRole(context.Background(), &orphan, "test-role2", "test-role-missing")
+		assert.True(t, errors.Is(err, datasource.ErrRoleNotExist))
+		exist, err := datasource.GetRoleManager().RoleExist(context.Background(), "test-role-orphan")
+		assert.NoError(t, err)
+		assert.False(t, exist)
+	})
+
 	t.Run("delete role bind to user should failed", func(t *testing.T) {
 		err := datasource.GetAccountManager().CreateAccount(context.Background(), &a)
 		assert.NoError(t, err)
@@ -125,3 +153,20 @@ func TestRole(t *testing.T) {
 		assert.Equal(t, int64(0), n)
 	})
 }
+
+func TestSortRolesByParents(t *testing.T) {
+	t.Run("roles inherit others, should be after the parents", func(t *testing.T) {
+		sorted := datasource.SortRolesByParents([]string{"a", "b", "c", "d"}, map[string][]string{
+			"a": {"c"},
+			"c": {"b", "admin"},
+		})
+		assert.Equal(t, []string{"b", "c", "d", "a"}, sorted)
+	})
+	t.Run("roles in a cycle, should be appended in name order", func(t *testing.T) {
+		sorted := datasource.SortRolesByParents([]string{"c", "b", "a"}, map[string][]string{
+			"a": {"b"},
+			"b": {"a"},
+		})
+		assert.Equal(t, []string{"c", "a", "b"}, sorted)
+	})
+}
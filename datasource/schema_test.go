@@ -813,9 +813,9 @@ func genLocalDatasource(editable bool) datasource.DataSource {
 	if t == nil {
 		t = "etcd"
 	}
-	if t == "etcd" {
+	if t == "etcd" || t == "local" {
 		ds, _ := etcd.NewDataSource(datasource.Options{
-			Kind:              "etcd",
+			Kind:              datasource.Kind(t.(string)),
 			SchemaNotEditable: !editable,
 		})
 
@@ -28,4 +28,8 @@ type SystemManager interface {
 	DumpCache(ctx context.Context) *dump.Cache
 	DLock(ctx context.Context, request *DLockRequest) error
 	DUnlock(ctx context.Context, request *DUnlockRequest) error
+	// GetCheckpoint returns the saved progress of the task, nil if not exist
+	GetCheckpoint(ctx context.Context, task string) ([]byte, error)
+	PutCheckpoint(ctx context.Context, task string, data []byte) error
+	DeleteCheckpoint(ctx context.Context, task string) error
 }
@@ -19,6 +19,7 @@ package datasource_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/sd"
@@ -61,3 +62,58 @@ func TestDumpCache(t *testing.T) {
 		assert.NotNil(t, cache)
 	})
 }
+
+func TestDLock(t *testing.T) {
+	t.Run("lock and unlock, should pass", func(t *testing.T) {
+		err := datasource.GetSystemManager().DLock(getContext(), &datasource.DLockRequest{ID: "TestDLock"})
+		assert.NoError(t, err)
+
+		err = datasource.GetSystemManager().DUnlock(getContext(), &datasource.DUnlockRequest{ID: "TestDLock"})
+		assert.NoError(t, err)
+
+		err = datasource.GetSystemManager().DLock(getContext(), &datasource.DLockRequest{ID: "TestDLock"})
+		assert.NoError(t, err)
+
+		err = datasource.GetSystemManager().DUnlock(getContext(), &datasource.DUnlockRequest{ID: "TestDLock"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("wait for a held lock, the holder should unlock", func(t *testing.T) {
+		err := datasource.GetSystemManager().DLock(getContext(), &datasource.DLockRequest{ID: "TestDLockWait"})
+		assert.NoError(t, err)
+
+		locked := make(chan error, 1)
+		go func() {
+			locked <- datasource.GetSystemManager().DLock(getContext(),
+				&datasource.DLockRequest{ID: "TestDLockWait", Wait: true})
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		unlocked := make(chan error, 1)
+		go func() {
+			unlocked <- datasource.GetSystemManager().DUnlock(getContext(),
+				&datasource.DUnlockRequest{ID: "TestDLockWait"})
+		}()
+		select {
+		case err = <-unlocked:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "unlock is blocked by the waiting lock")
+			return
+		}
+		select {
+		case err = <-locked:
+			assert.NoError(t, err)
+		case <-time.After(30 * time.Second):
+			assert.Fail(t, "lock is not acquired after unlock")
+			return
+		}
+		err = datasource.GetSystemManager().DUnlock(getContext(), &datasource.DUnlockRequest{ID: "TestDLockWait"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unlock a not exist lock, should be failed", func(t *testing.T) {
+		err := datasource.GetSystemManager().DUnlock(getContext(), &datasource.DUnlockRequest{ID: "TestDLockNotExist"})
+		assert.Equal(t, datasource.ErrDLockNotFound, err)
+	})
+}
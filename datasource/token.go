@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+)
+
+// TokenManager saves the revocation of the tokens, it is shared by the
+// replicas, so a token revoked in one replica is rejected by the others
+type TokenManager interface {
+	// RevokeToken saves the revoked token until it expires
+	RevokeToken(ctx context.Context, t *RevokedToken) error
+	TokenRevoked(ctx context.Context, hash string) (bool, error)
+	// GetGeneration returns the token generation of the account, the tokens
+	// issued in the older generations are revoked
+	GetGeneration(ctx context.Context, account string) (int64, error)
+	// IncGeneration revokes all the tokens issued to the account
+	IncGeneration(ctx context.Context, account string) (int64, error)
+}
+
+type RevokedToken struct {
+	// Hash is the SHA-256 of the token
+	Hash    string `json:"hash" bson:"hash"`
+	Account string `json:"account,omitempty" bson:"account"`
+	// ExpireAt is the expiry of the token, 0 means the token never expires
+	ExpireAt int64 `json:"expireAt" bson:"expire_at"`
+}
+
+type TokenGeneration struct {
+	Account    string `json:"account" bson:"account"`
+	Generation int64  `json:"generation" bson:"generation"`
+}
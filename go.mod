@@ -9,7 +9,7 @@ require (
 	github.com/NYTimes/gziphandler v1.1.1
 	github.com/astaxie/beego v1.12.2
 	github.com/cheggaaa/pb v1.0.25
-	github.com/coreos/bbolt v1.3.3 // indirect
+	github.com/coreos/bbolt v1.3.3
 	github.com/coreos/etcd v3.3.25+incompatible
 	github.com/coreos/pkg v0.0.0-20180928190104-399ea9e2e55f // v4
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package apikey defines the long-lived API keys of the accounts, they are
+// the credentials of the machine clients, e.g. CI robots and sidecars
+package apikey
+
+const (
+	// Prefix is the prefix of the keys, it distinguishes the keys from the
+	// tokens in the Authorization header
+	Prefix = "sck_"
+
+	DefaultExpireAfter = "2160h"
+)
+
+// APIKey is the stored key, the secret of the key is only returned when it
+// is created or rotated, Hash is the SHA-256 of the secret
+type APIKey struct {
+	ID      string `json:"id" bson:"id"`
+	Account string `json:"account" bson:"account"`
+	Name    string `json:"name,omitempty" bson:"name"`
+	// Roles narrows the roles of the account, empty means all of them
+	Roles      []string `json:"roles,omitempty" bson:"roles"`
+	Hash       string   `json:"hash,omitempty" bson:"hash"`
+	CreateTime int64    `json:"createTime" bson:"create_time"`
+	UpdateTime int64    `json:"updateTime" bson:"update_time"`
+	ExpireAt   int64    `json:"expireAt" bson:"expire_at"`
+}
+
+type CreateRequest struct {
+	Name  string   `json:"name,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+	// ExpireAfter is the lifetime of the key, e.g. 720h, default is 2160h
+	ExpireAfter string `json:"expireAfter,omitempty"`
+}
+
+type RotateRequest struct {
+	// ExpireAfter is the lifetime of the new secret, default is the
+	// lifetime of the old one
+	ExpireAfter string `json:"expireAfter,omitempty"`
+}
+
+// KeyResponse returns the key with the secret, the secret can not be
+// retrieved again
+type KeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+type ListResponse struct {
+	Total int64     `json:"total"`
+	Keys  []*APIKey `json:"data,omitempty"`
+}
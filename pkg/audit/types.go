@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit defines the audit records of the mutating API calls, and
+// the audit log query API types
+package audit
+
+import (
+	"github.com/go-chassis/cari/discovery"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Record is an audit record of a mutating API call
+type Record struct {
+	ID string `json:"id" bson:"id"`
+	// Timestamp is the unix nano time when the call is completed
+	Timestamp int64  `json:"timestamp" bson:"timestamp"`
+	Actor     string `json:"actor,omitempty" bson:"actor"`
+	SourceIP  string `json:"sourceIP,omitempty" bson:"source_ip"`
+	Domain    string `json:"domain,omitempty" bson:"domain"`
+	Project   string `json:"project,omitempty" bson:"project"`
+	Method    string `json:"method" bson:"method"`
+	// Pattern is the route pattern of the API
+	Pattern string `json:"pattern" bson:"pattern"`
+	// Resource is the RBAC resource type of the API
+	Resource string `json:"resource,omitempty" bson:"resource"`
+	// ResourceIDs are the path parameters of the API, e.g. serviceId
+	ResourceIDs map[string]string `json:"resourceIds,omitempty" bson:"resource_ids"`
+	// Code is the http status code of the response
+	Code int `json:"code" bson:"code"`
+	// Summary is the request body with the sensitive fields redacted
+	Summary string `json:"summary,omitempty" bson:"summary"`
+	// ExpireAt is the unix time the record is removed, 0 means never
+	ExpireAt int64 `json:"-" bson:"-"`
+}
+
+// Request queries the records in [Start, End), they are unix nano times
+// and 0 means unbounded. Resource matches the resource type or one of the
+// resource IDs
+type Request struct {
+	Start    int64  `json:"start,omitempty"`
+	End      int64  `json:"end,omitempty"`
+	Actor    string `json:"actor,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Limit    int64  `json:"limit,omitempty"`
+}
+
+// Match returns whether the record matches the actor and resource of the
+// request, the time range is not checked
+func (req *Request) Match(r *Record) bool {
+	if len(req.Actor) > 0 && req.Actor != r.Actor {
+		return false
+	}
+	if len(req.Resource) == 0 || req.Resource == r.Resource {
+		return true
+	}
+	for _, id := range r.ResourceIDs {
+		if id == req.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+type Response struct {
+	Response *discovery.Response `json:"-"`
+	Total    int64               `json:"total"`
+	Records  []*Record           `json:"data"`
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package backup defines the archive of the registry data of a
+// domain/project and the backup and restore API types
+package backup
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+)
+
+// ArchiveVersion is increased when the archive format is changed
+// incompatibly, the archive of a newer version can not be restored
+const ArchiveVersion = 1
+
+type Archive struct {
+	Version int `json:"version"`
+	// Timestamp is the unix time the archive created
+	Timestamp    string                          `json:"timestamp"`
+	Domain       string                          `json:"domain"`
+	Project      string                          `json:"project"`
+	Services     []*Service                      `json:"services,omitempty"`
+	Dependencies []*discovery.ConsumerDependency `json:"dependencies,omitempty"`
+	// Accounts and Roles are global resources, only the archive of the
+	// default domain contains them
+	Accounts []*rbac.Account `json:"accounts,omitempty"`
+	Roles    []*rbac.Role    `json:"roles,omitempty"`
+	// RoleParents are the roles inherited by each role
+	RoleParents map[string][]string `json:"roleParents,omitempty"`
+}
+
+// Service is the microservice and the resources belong to it
+type Service struct {
+	Service   *discovery.MicroService           `json:"service"`
+	Schemas   []*discovery.Schema               `json:"schemas,omitempty"`
+	Tags      map[string]string                 `json:"tags,omitempty"`
+	Rules     []*discovery.ServiceRule          `json:"rules,omitempty"`
+	Instances []*discovery.MicroServiceInstance `json:"instances,omitempty"`
+}
+
+// Encode writes the gzip compressed json of the archive
+func Encode(w io.Writer, a *Archive) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Decode reads the archive written by Encode
+func Decode(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	a := &Archive{}
+	if err := json.NewDecoder(zr).Decode(a); err != nil {
+		return nil, err
+	}
+	if a.Version < 1 || a.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", a.Version)
+	}
+	return a, nil
+}
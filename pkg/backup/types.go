@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"github.com/go-chassis/cari/discovery"
+)
+
+const (
+	// ContentType is the http content type of the archive
+	ContentType = "application/gzip"
+
+	// ModeSkip keeps the existing items which are different from the archive
+	ModeSkip = "skip"
+	// ModeOverwrite replaces the existing items with the archive
+	ModeOverwrite = "overwrite"
+	// ModeFail restores nothing if any item conflicts
+	ModeFail = "fail"
+
+	KindService    = "service"
+	KindSchema     = "schema"
+	KindTag        = "tag"
+	KindRule       = "rule"
+	KindInstance   = "instance"
+	KindDependency = "dependency"
+	KindRole       = "role"
+	KindAccount    = "account"
+)
+
+type Request struct {
+	// Domain and Project are the target to export, the requester's
+	// domain/project is used if Domain is empty
+	Domain  string `json:"domain,omitempty"`
+	Project string `json:"project,omitempty"`
+	// WithInstances exports the instances, they are ephemeral and skipped
+	// by default
+	WithInstances bool `json:"withInstances,omitempty"`
+}
+
+type Response struct {
+	Response *discovery.Response `json:"-"`
+	Archive  *Archive            `json:"-"`
+}
+
+type RestoreRequest struct {
+	// Domain and Project are the target to restore to, the requester's
+	// domain/project is used if Domain is empty
+	Domain  string `json:"domain,omitempty"`
+	Project string `json:"project,omitempty"`
+	// Mode is one of skip, overwrite and fail, default is skip
+	Mode    string   `json:"mode,omitempty"`
+	Archive *Archive `json:"-"`
+}
+
+type RestoreResponse struct {
+	Response *discovery.Response `json:"-"`
+	Report   *Report             `json:"report,omitempty"`
+}
+
+type Report struct {
+	Mode  string        `json:"mode"`
+	Kinds []*KindReport `json:"kinds,omitempty"`
+	// Conflicts are the items exist but different from the archive
+	Conflicts []*Conflict `json:"conflicts,omitempty"`
+}
+
+type KindReport struct {
+	Kind        string `json:"kind"`
+	Total       int    `json:"total"`
+	Created     int    `json:"created"`
+	Overwritten int    `json:"overwritten"`
+	// Skipped is the number of the items identical to the archive, or
+	// conflicted items in skip mode
+	Skipped int      `json:"skipped"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type Conflict struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key"`
+}
@@ -94,6 +94,12 @@ func (m *DLock) ID() string {
 func (m *DLock) Lock(wait bool) (err error) {
 	if !IsDebug {
 		m.mutex.Lock()
+		// release it if failed, otherwise the retry blocks forever
+		defer func() {
+			if err != nil {
+				m.mutex.Unlock()
+			}
+		}()
 	}
 
 	opts := []etcdclient.PluginOpOption{
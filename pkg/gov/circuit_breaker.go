@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"encoding/json"
+)
+
+//CircuitBreaker stops calling the provider for a while if the calls fail or slow down
+type CircuitBreaker struct {
+	*GovernancePolicy
+	Spec *CircuitBreakerSpec `json:"spec,omitempty"`
+}
+
+const (
+	SlidingWindowCount = "count"
+	SlidingWindowTime  = "time"
+)
+
+//CircuitBreakerSpec opens the circuit if the failure rate or the slow call rate
+//of the calls in the sliding window reaches the threshold, the absent fields use
+//the defaults of the client
+type CircuitBreakerSpec struct {
+	//FailureRateThreshold is the percentage of the failed calls in (0, 100]
+	FailureRateThreshold *float64 `json:"failureRateThreshold,omitempty"`
+	//SlowCallRateThreshold is the percentage of the slow calls in (0, 100]
+	SlowCallRateThreshold *float64 `json:"slowCallRateThreshold,omitempty"`
+	//SlowCallDurationThreshold is the positive duration a call is slow if it exceeds
+	SlowCallDurationThreshold *Duration `json:"slowCallDurationThreshold,omitempty"`
+	//MinimumNumberOfCalls is the positive number of the calls before computing the rates
+	MinimumNumberOfCalls *int `json:"minimumNumberOfCalls,omitempty"`
+	//SlidingWindowType is count or time
+	SlidingWindowType string `json:"slidingWindowType,omitempty"`
+	//SlidingWindowSize is the positive number of calls or seconds of the sliding window
+	SlidingWindowSize *int `json:"slidingWindowSize,omitempty"`
+	//WaitDurationInOpenState is the positive duration before the circuit is half open
+	WaitDurationInOpenState *Duration `json:"waitDurationInOpenState,omitempty"`
+	//PermittedNumberOfCallsInHalfOpenState is the positive number of the calls to probe
+	PermittedNumberOfCallsInHalfOpenState *int `json:"permittedNumberOfCallsInHalfOpenState,omitempty"`
+	//RecordFailureStatus are the http status codes treated as the failures
+	RecordFailureStatus []json.Number `json:"recordFailureStatus,omitempty"`
+	//ForceOpen and ForceClosed keep the circuit open or closed, they are exclusive
+	ForceOpen   bool `json:"forceOpen,omitempty"`
+	ForceClosed bool `json:"forceClosed,omitempty"`
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//Duration is a json number in milliseconds, or a string of the milliseconds or
+//a go duration like "1m30s"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	invalid := &json.UnmarshalTypeError{Value: string(b), Type: reflect.TypeOf(d).Elem()}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return invalid
+	}
+	switch value := v.(type) {
+	case float64:
+		*d = Duration(value * float64(time.Millisecond))
+		return nil
+	case string:
+		if ms, err := strconv.ParseFloat(value, 64); err == nil {
+			*d = Duration(ms * float64(time.Millisecond))
+			return nil
+		}
+		t, err := time.ParseDuration(value)
+		if err != nil {
+			return invalid
+		}
+		*d = Duration(t)
+		return nil
+	}
+	return invalid
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
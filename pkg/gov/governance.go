@@ -17,6 +17,10 @@
 
 package gov
 
+import (
+	"github.com/go-chassis/cari/discovery"
+)
+
 //GovernancePolicy is a unified struct
 //all governance policy must extend this struct
 //Name is the policy name, for example: "rate-limit-payment-api"
@@ -58,3 +62,99 @@ type BackOffPolicy struct {
 	InitialInterval int `json:"initInterval"`
 	MaxInterval     int `json:"maxInterval"`
 }
+
+const (
+	DriftMissing     = "missing"
+	DriftUnexpected  = "unexpected"
+	DriftDifferent   = "different"
+	DriftUnavailable = "unavailable"
+)
+
+//DriftReport compares the policies of every distributor with the primary one
+type DriftReport struct {
+	Primary      string   `json:"primary"`
+	Distributors []string `json:"distributors"`
+	Drifts       []*Drift `json:"drifts"`
+}
+
+//Drift is a policy of a distributor which is different from the primary one,
+//Reason is one of missing, unexpected, different and unavailable
+type Drift struct {
+	Distributor string `json:"distributor"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name,omitempty"`
+	App         string `json:"app,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Reason      string `json:"reason"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+//DriftResponse is the response of the drift API
+type DriftResponse struct {
+	Response *discovery.Response `json:"-"`
+	*DriftReport
+}
+
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionRollback = "rollback"
+	ActionDelete   = "delete"
+)
+
+//Revision is a revision of a governance policy, Action is one of create, update and rollback
+type Revision struct {
+	Revision  int64     `json:"revision"`
+	Action    string    `json:"action"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp int64     `json:"timestamp"`
+	Policy    *Policy   `json:"policy,omitempty"`
+	Diff      []*Change `json:"diff,omitempty"`
+}
+
+//Change is a changed field of the policy since the previous revision, Path is like spec.rate
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+//Preview is the services and the operations affected by a policy
+type Preview struct {
+	Services []*AffectedService `json:"services"`
+}
+
+//AffectedService is a service selected by the policy, Operations are matched by the match group
+type AffectedService struct {
+	ServiceID   string       `json:"serviceId"`
+	ServiceName string       `json:"serviceName"`
+	AppID       string       `json:"appId"`
+	Environment string       `json:"environment,omitempty"`
+	Version     string       `json:"version"`
+	Operations  []*Operation `json:"operations,omitempty"`
+}
+
+//Operation is an operation in the schema of a service, Path includes the base path
+type Operation struct {
+	SchemaID    string `json:"schemaId"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operationId,omitempty"`
+}
+
+//Plan is the steps to reconcile the policies with a bundle, Applied is false in a dry run
+type Plan struct {
+	Steps     []*Step `json:"steps"`
+	Unchanged int     `json:"unchanged"`
+	Applied   bool    `json:"applied"`
+}
+
+//Step is a change of the plan, Action is one of create, update and delete
+type Step struct {
+	Action   string    `json:"action"`
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Selector *Selector `json:"selector,omitempty"`
+	ID       string    `json:"id,omitempty"`
+	Diff     []*Change `json:"diff,omitempty"`
+}
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+const (
+	LBRuleRoundRobin        = "RoundRobin"
+	LBRuleRandom            = "Random"
+	LBRuleLeastConn         = "LeastConn"
+	LBRuleWeightedResponse  = "WeightedResponse"
+	LBRuleSessionStickiness = "SessionStickiness"
+)
+
+//LBRules are the load balance rules supported by the clients
+var LBRules = []string{LBRuleRoundRobin, LBRuleRandom, LBRuleLeastConn, LBRuleWeightedResponse, LBRuleSessionStickiness}
+
+//LoadBalancer chooses an instance of the provider
+type LoadBalancer struct {
+	*GovernancePolicy
+	Spec *LoadBalancerSpec `json:"spec,omitempty"`
+}
+
+//LoadBalancerSpec specifies the load balance rule, it is one of LBRules
+type LoadBalancerSpec struct {
+	Rule string `json:"rule"`
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwks_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/jwks"
+)
+
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func keySet(t *testing.T) (string, *rsa.PrivateKey, *ecdsa.PrivateKey) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return fmt.Sprintf(`{"keys":[
+{"kty":"RSA","kid":"rsa","use":"sig","n":"%s","e":"%s"},
+{"kty":"EC","kid":"ec","crv":"P-256","x":"%s","y":"%s"},
+{"kty":"RSA","kid":"enc","use":"enc","n":"x","e":"x"},
+{"kty":"oct","kid":"oct","k":"c2VjcmV0"}]}`,
+		encode(rsaKey.N), encode(big.NewInt(int64(rsaKey.E))), encode(ecKey.X), encode(ecKey.Y)), rsaKey, ecKey
+}
+
+func TestParse(t *testing.T) {
+	data, rsaKey, ecKey := keySet(t)
+
+	t.Run("parse rsa and ec keys, should pass", func(t *testing.T) {
+		ks, err := jwks.Parse([]byte(data))
+		assert.NoError(t, err)
+		key, err := ks.Key("rsa")
+		assert.NoError(t, err)
+		assert.Equal(t, &rsaKey.PublicKey, key)
+		key, err = ks.Key("ec")
+		assert.NoError(t, err)
+		assert.Equal(t, ecKey.X, key.(*ecdsa.PublicKey).X)
+
+		_, err = ks.Key("enc")
+		assert.Equal(t, jwks.ErrKeyNotFound, err)
+		_, err = ks.Key("oct")
+		assert.Equal(t, jwks.ErrKeyNotFound, err)
+		_, err = ks.Key("")
+		assert.Equal(t, jwks.ErrKeyNotFound, err)
+	})
+
+	t.Run("only one key without kid, should return it", func(t *testing.T) {
+		ks, err := jwks.Parse([]byte(fmt.Sprintf(`{"keys":[{"kty":"RSA","n":"%s","e":"AQAB"}]}`, encode(rsaKey.N))))
+		assert.NoError(t, err)
+		key, err := ks.Key("")
+		assert.NoError(t, err)
+		assert.Equal(t, rsaKey.N, key.(*rsa.PublicKey).N)
+	})
+
+	t.Run("invalid key set, should fail", func(t *testing.T) {
+		_, err := jwks.Parse([]byte(`{"keys":[]}`))
+		assert.Equal(t, jwks.ErrNoKey, err)
+		_, err = jwks.Parse([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+		assert.Error(t, err)
+		_, err = jwks.Parse([]byte(`{`))
+		assert.Error(t, err)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	data, _, _ := keySet(t)
+
+	t.Run("load from file, should pass", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "jwks.json")
+		assert.NoError(t, ioutil.WriteFile(file, []byte(data), 0600))
+		ks, err := jwks.Load(context.Background(), http.DefaultClient, file)
+		assert.NoError(t, err)
+		_, err = ks.Key("rsa")
+		assert.NoError(t, err)
+		_, err = jwks.Load(context.Background(), http.DefaultClient, "file://"+file)
+		assert.NoError(t, err)
+	})
+
+	t.Run("load from url, should pass", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/jwks" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(data))
+		}))
+		defer server.Close()
+		ks, err := jwks.Load(context.Background(), server.Client(), server.URL+"/jwks")
+		assert.NoError(t, err)
+		_, err = ks.Key("ec")
+		assert.NoError(t, err)
+
+		_, err = jwks.Load(context.Background(), server.Client(), server.URL+"/none")
+		assert.Error(t, err)
+	})
+}
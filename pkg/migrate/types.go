@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate
+
+import (
+	"github.com/go-chassis/cari/discovery"
+)
+
+const (
+	StageService    = "service"
+	StageSchema     = "schema"
+	StageTag        = "tag"
+	StageRule       = "rule"
+	StageDependency = "dependency"
+	StageRole       = "role"
+	StageAccount    = "account"
+
+	DiffMissing   = "missing"
+	DiffDifferent = "different"
+)
+
+type Request struct {
+	// Target is the datasource kind to migrate to, e.g. mongo
+	Target string `json:"target"`
+	// DryRun reports what would be migrated without writing the target
+	DryRun bool `json:"dryRun,omitempty"`
+	// Resume continues from the checkpoint of the last interrupted migration
+	Resume bool `json:"resume,omitempty"`
+	// VerifyOnly compares the source and the target without migration
+	VerifyOnly bool `json:"verifyOnly,omitempty"`
+}
+
+type Response struct {
+	Response *discovery.Response `json:"-"`
+	Report   *Report             `json:"report,omitempty"`
+}
+
+type Report struct {
+	Source string         `json:"source"`
+	Target string         `json:"target"`
+	DryRun bool           `json:"dryRun,omitempty"`
+	Stages []*StageReport `json:"stages,omitempty"`
+	// Diffs is the result of verification, empty means the target is
+	// consistent with the source
+	Diffs []*Diff `json:"diffs,omitempty"`
+}
+
+type StageReport struct {
+	Stage string `json:"stage"`
+	Total int    `json:"total"`
+	// Created is the number of the items created in the target, in dry-run
+	// mode, it is the number of items would be created
+	Created int `json:"created"`
+	// Updated is the number of the items merged into the target
+	Updated int `json:"updated"`
+	// Skipped is the number of the items already exist in the target
+	Skipped int `json:"skipped"`
+	// Resumed is the number of the items finished before the checkpoint
+	Resumed int `json:"resumed"`
+	// Conflicted is the number of the items exist in the target but
+	// different from the source, they are not overwritten
+	Conflicted int      `json:"conflicted"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+type Diff struct {
+	Stage  string `json:"stage"`
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package quota defines the quota overrides of the domains and projects,
+// and the quota management API types
+package quota
+
+import (
+	"github.com/go-chassis/cari/discovery"
+)
+
+const (
+	ResourceService  = "service"
+	ResourceInstance = "instance"
+	ResourceSchema   = "schema"
+	ResourceTag      = "tag"
+	ResourceRule     = "rule"
+	ResourceAccount  = "account"
+	ResourceRole     = "role"
+
+	ScopeDefault = "default"
+	ScopeDomain  = "domain"
+	ScopeProject = "project"
+)
+
+// Resources are the resources can be limited by quota
+var Resources = []string{
+	ResourceService,
+	ResourceInstance,
+	ResourceSchema,
+	ResourceTag,
+	ResourceRule,
+	ResourceAccount,
+	ResourceRole,
+}
+
+// Quota overrides the default limits of a domain or a project, the
+// project is empty for a domain override. A project override takes
+// precedence over the domain one, the resources not in Limits fall back
+// to the next level
+type Quota struct {
+	Domain  string           `json:"domain" bson:"domain"`
+	Project string           `json:"project,omitempty" bson:"project"`
+	Limits  map[string]int64 `json:"limits" bson:"limits"`
+}
+
+// Limit is the effective limit of a resource and where it comes from
+type Limit struct {
+	Resource string `json:"resource"`
+	Limit    int64  `json:"limit"`
+	Scope    string `json:"scope"`
+}
+
+type Request struct {
+	Domain  string `json:"domain"`
+	Project string `json:"project,omitempty"`
+}
+
+type Response struct {
+	Response *discovery.Response `json:"-"`
+	// Quota is the override saved in the requested level
+	Quota *Quota `json:"quota,omitempty"`
+	// Limits are the effective limits of the requested domain/project
+	Limits []*Limit `json:"limits,omitempty"`
+}
+
+type UsageRequest struct {
+	Domain  string `json:"domain"`
+	Project string `json:"project"`
+	// ServiceID is optional, the schema, tag and rule quotas are limited
+	// per service, their usages are reported only if it is specified
+	ServiceID string `json:"serviceId,omitempty"`
+}
+
+type UsageResponse struct {
+	Response  *discovery.Response `json:"-"`
+	Domain    string              `json:"domain"`
+	Project   string              `json:"project"`
+	ServiceID string              `json:"serviceId,omitempty"`
+	Usages    []*Usage            `json:"usages"`
+}
+
+type Usage struct {
+	Limit
+	Used int64 `json:"used"`
+}
@@ -23,11 +23,15 @@ type CtxKey string
 
 const (
 	HeaderRev                  = "X-Resource-Revision"
+	HeaderTotal                = "X-Total-Count"
 	CtxGlobal           CtxKey = "global"
 	CtxNocache          CtxKey = "noCache"
 	CtxCacheOnly        CtxKey = "cacheOnly"
 	CtxRequestRevision  CtxKey = "requestRev"
 	CtxResponseRevision CtxKey = "responseRev"
+	CtxResponseTotal    CtxKey = "responseTotal"
+	CtxHashedPassword   CtxKey = "hashedPassword"
+	CtxSkipQuota        CtxKey = "skipQuota"
 )
 
 func GetAppRoot() string {
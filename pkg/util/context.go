@@ -224,3 +224,33 @@ func WithRequestRev(ctx context.Context, rev string) context.Context {
 func WithResponseRev(ctx context.Context, rev string) context.Context {
 	return SetContext(ctx, CtxResponseRevision, rev)
 }
+
+// WithResponseTotal sets the total count of the records before paging
+func WithResponseTotal(ctx context.Context, total int64) context.Context {
+	return SetContext(ctx, CtxResponseTotal, total)
+}
+
+func ResponseTotal(ctx context.Context) (int64, bool) {
+	total, ok := ctx.Value(CtxResponseTotal).(int64)
+	return total, ok
+}
+
+// WithHashedPassword marks the password of the account to create is hashed
+// already, it is only used to copy the accounts between datasources
+func WithHashedPassword(ctx context.Context) context.Context {
+	return SetContext(ctx, CtxHashedPassword, "1")
+}
+
+func HashedPassword(ctx context.Context) bool {
+	return ctx.Value(CtxHashedPassword) == "1"
+}
+
+// WithSkipQuota marks the resources to create are copied from another
+// datasource, the quotas are not checked against the global datasource
+func WithSkipQuota(ctx context.Context) context.Context {
+	return SetContext(ctx, CtxSkipQuota, "1")
+}
+
+func SkipQuota(ctx context.Context) bool {
+	return ctx.Value(CtxSkipQuota) == "1"
+}
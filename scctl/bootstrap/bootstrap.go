@@ -29,4 +29,10 @@ import (
 	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/cluster"
 
 	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/health"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/migrate"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/gov"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/canideploy"
 )
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canideploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+	"github.com/spf13/cobra"
+)
+
+// ExitUndeployable means the consumer version is not safe to deploy
+const ExitUndeployable = cmd.ExitError + 1
+
+var request brokerpb.MatrixRequest
+
+func init() {
+	NewCanIDeployCommand(cmd.RootCmd())
+}
+
+func NewCanIDeployCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "can-i-deploy [options]",
+		Short:   "Check whether a consumer version is safe to deploy by the verification results of its pacts",
+		Run:     CanIDeployCommandFunc,
+		Example: parent.CommandPath() + ` can-i-deploy --app shop --service order-ui --version 1.0.0 --to production;`,
+	}
+
+	cmd.Flags().StringVar(&request.AppId, "app", "default", "the app of the consumer")
+	cmd.Flags().StringVarP(&request.ServiceName, "service", "s", "", "the service name of the consumer")
+	cmd.Flags().StringVar(&request.Version, "version", "", "the version of the consumer")
+	cmd.Flags().StringVar(&request.Environment, "to", "",
+		"the environment to deploy to, the provider versions registered in it must verify the pacts")
+	cmd.Flags().StringVar(&request.Tag, "tag", "",
+		"the tag of the provider versions which must verify the pacts, e.g. prod")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func CanIDeployCommandFunc(_ *cobra.Command, args []string) {
+	if len(request.ServiceName) == 0 || len(request.Version) == 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("service and version are required"))
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	resp, scErr := scClient.GetBrokerMatrix(context.Background(), &request)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	if len(resp.Matrix) > 0 {
+		writer.PrintTable(&MatrixPrinter{Records: resp.Matrix})
+	}
+	summary := resp.GetSummary()
+	if summary == nil {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("no summary in the matrix"))
+	}
+	for _, reason := range summary.Reasons {
+		fmt.Println(reason)
+	}
+	if summary.Deployable {
+		fmt.Printf("computer says yes, %s %s can be deployed\n", request.ServiceName, request.Version)
+		return
+	}
+	fmt.Printf("computer says no, %d failed and %d unknown\n", summary.Failed, summary.Unknown)
+	cmd.StopAndExit(ExitUndeployable)
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canideploy
+
+import (
+	"strconv"
+
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+)
+
+var matrixTableHeader = []string{"CONSUMER", "C.VERSION", "PROVIDER", "P.VERSION", "PACT", "SUCCESS", "VERIFIED AT"}
+
+type MatrixPrinter struct {
+	Records []*brokerpb.MatrixRow
+	flags   []interface{}
+}
+
+func (mp *MatrixPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		mp.flags = flags
+	}
+	return mp.flags
+}
+
+func (mp *MatrixPrinter) PrintBody() (slice [][]string) {
+	for _, r := range mp.Records {
+		success := "?"
+		if r.Verified {
+			success = strconv.FormatBool(r.Success)
+		}
+		slice = append(slice, []string{r.ConsumerName, r.ConsumerVersion, r.ProviderName, r.ProviderVersion,
+			strconv.Itoa(int(r.PactId)), success, r.VerificationDate})
+	}
+	return
+}
+
+func (mp *MatrixPrinter) PrintTitle() []string {
+	return matrixTableHeader
+}
+
+func (mp *MatrixPrinter) Sorter() *writer.RecordsSorter {
+	return writer.NewRecordsSorter(func(a, b []string) bool {
+		return a[2] < b[2] || (a[2] == b[2] && a[3] < b[3])
+	})
+}
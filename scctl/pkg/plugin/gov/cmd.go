@@ -0,0 +1,163 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Project string
+	App     string
+	Env     string
+	File    string
+	DryRun  bool
+	Yes     bool
+)
+
+func init() {
+	NewGovCommand(cmd.RootCmd())
+}
+
+func NewGovCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gov <command> [options]",
+		Short: "Export and apply the governance policies as YAML bundles",
+	}
+	cmd.PersistentFlags().StringVar(&Project, "project", "default", "the project of the governance policies")
+	cmd.PersistentFlags().StringVar(&App, "app", "", "only the policies of the app, empty means any")
+	cmd.PersistentFlags().StringVar(&Env, "env", "", "only the policies of the environment, empty means any")
+
+	export := &cobra.Command{
+		Use:     "export [options]",
+		Short:   "Export the governance policies as a multi-document YAML bundle",
+		Run:     ExportCommandFunc,
+		Example: cmd.CommandPath() + ` export --app shop -f shop.yaml;`,
+	}
+	export.Flags().StringVarP(&File, "file", "f", "", "the file to write, default is stdout")
+
+	apply := &cobra.Command{
+		Use:     "apply [options]",
+		Short:   "Reconcile the governance policies with a YAML bundle, the plan is printed first",
+		Run:     ApplyCommandFunc,
+		Example: cmd.CommandPath() + ` apply --app shop -f shop.yaml --dry-run;`,
+	}
+	apply.Flags().StringVarP(&File, "file", "f", "", "the bundle file to apply, - means stdin")
+	apply.Flags().BoolVar(&DryRun, "dry-run", false, "only print the plan")
+	apply.Flags().BoolVarP(&Yes, "yes", "y", false, "apply the deletions without confirmation")
+
+	cmd.AddCommand(export, apply)
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func ExportCommandFunc(_ *cobra.Command, args []string) {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	bundle, scErr := scClient.ExportGov(context.Background(), Project, App, Env)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	if len(File) == 0 {
+		fmt.Print(string(bundle))
+		return
+	}
+	if err := ioutil.WriteFile(File, bundle, 0640); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+}
+
+func ApplyCommandFunc(_ *cobra.Command, args []string) {
+	if len(File) == 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("file is required"))
+	}
+	var (
+		bundle []byte
+		err    error
+	)
+	if File == "-" {
+		bundle, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		bundle, err = ioutil.ReadFile(File)
+	}
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	plan, scErr := scClient.ApplyGov(ctx, Project, App, Env, bundle, true)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	if len(plan.Steps) == 0 {
+		fmt.Printf("no changes, %d policies are up to date\n", plan.Unchanged)
+		return
+	}
+	writer.PrintTable(&PlanPrinter{Records: plan.Steps})
+	if DryRun {
+		return
+	}
+	if deletes := countDeletes(plan); deletes > 0 && !Yes && !confirm(deletes) {
+		fmt.Println("canceled")
+		return
+	}
+	// the plan is computed again, the policies may be changed since the dry run
+	plan, scErr = scClient.ApplyGov(ctx, Project, App, Env, bundle, false)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Printf("applied, %d changed, %d unchanged\n", len(plan.Steps), plan.Unchanged)
+}
+
+func countDeletes(plan *gov.Plan) int {
+	n := 0
+	for _, step := range plan.Steps {
+		if step.Action == gov.ActionDelete {
+			n++
+		}
+	}
+	return n
+}
+
+// confirm asks the user to confirm the deletions, the bundle read from stdin
+// can not be confirmed interactively, so --yes is required
+func confirm(deletes int) bool {
+	if File == "-" {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("the plan deletes %d policies, confirm with --yes", deletes))
+	}
+	fmt.Printf("the plan deletes %d policies, continue? [y/N]: ", deletes)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
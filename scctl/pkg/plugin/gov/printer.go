@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+)
+
+var planTableHeader = []string{"ACTION", "KIND", "NAME", "APP", "ENVIRONMENT", "CHANGES"}
+
+type PlanPrinter struct {
+	Records []*gov.Step
+	flags   []interface{}
+}
+
+func (pp *PlanPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		pp.flags = flags
+	}
+	return pp.flags
+}
+
+func (pp *PlanPrinter) PrintBody() (slice [][]string) {
+	for _, s := range pp.Records {
+		app, env := "", ""
+		if s.Selector != nil {
+			app, env = s.Selector.App, s.Selector.Environment
+		}
+		changes := make([]string, 0, len(s.Diff))
+		for _, c := range s.Diff {
+			changes = append(changes, c.Path)
+		}
+		slice = append(slice, []string{s.Action, s.Kind, s.Name, app, env, strings.Join(changes, "\n")})
+	}
+	return
+}
+
+func (pp *PlanPrinter) PrintTitle() []string {
+	return planTableHeader
+}
+
+// Sorter keeps the steps in the applying order
+func (pp *PlanPrinter) Sorter() *writer.RecordsSorter {
+	return writer.NewRecordsSorter(func(a, b []string) bool { return false })
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ExitInconsistent means the target is different from the source after migration
+	ExitInconsistent = cmd.ExitError + 1
+
+	defaultTimeout = 30 * time.Minute
+)
+
+var request migrate.Request
+
+func init() {
+	NewMigrateCommand(cmd.RootCmd())
+}
+
+func NewMigrateCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "migrate [options]",
+		Short:   "Migrate the registry data of service center to another datasource",
+		Run:     MigrateCommandFunc,
+		Example: parent.CommandPath() + ` migrate --target mongo --dry-run;`,
+	}
+
+	cmd.Flags().StringVar(&request.Target, "target", "", "the datasource kind to migrate to, e.g. mongo")
+	cmd.Flags().BoolVar(&request.DryRun, "dry-run", false, "report what would be migrated without writing the target")
+	cmd.Flags().BoolVar(&request.Resume, "resume", false, "continue from the checkpoint of the last interrupted migration")
+	cmd.Flags().BoolVar(&request.VerifyOnly, "verify", false, "only compare the target with the source")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func MigrateCommandFunc(c *cobra.Command, args []string) {
+	if len(request.Target) == 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("target is required"))
+	}
+	if !c.Flags().Changed("timeout") {
+		// the migration may take a long time
+		cmd.ScClientConfig.RequestTimeout = defaultTimeout
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	report, scErr := scClient.Migrate(context.Background(), &request)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	if report == nil {
+		return
+	}
+
+	if len(report.Stages) > 0 {
+		writer.PrintTable(&StagesPrinter{Records: report.Stages})
+	}
+	if request.DryRun {
+		return
+	}
+	if len(report.Diffs) == 0 {
+		fmt.Printf("verified, %s is consistent with %s\n", report.Target, report.Source)
+		return
+	}
+	writer.PrintTable(&DiffsPrinter{Records: report.Diffs})
+	cmd.StopAndExit(ExitInconsistent)
+}
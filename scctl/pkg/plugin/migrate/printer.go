@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+)
+
+var (
+	stageTableHeader = []string{"STAGE", "TOTAL", "CREATED", "UPDATED", "SKIPPED", "RESUMED", "CONFLICTED", "FAILED", "ERRORS"}
+	diffTableHeader  = []string{"STAGE", "KEY", "REASON"}
+)
+
+type StagesPrinter struct {
+	Records []*migrate.StageReport
+	flags   []interface{}
+}
+
+func (sp *StagesPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		sp.flags = flags
+	}
+	return sp.flags
+}
+
+func (sp *StagesPrinter) PrintBody() (slice [][]string) {
+	for _, s := range sp.Records {
+		slice = append(slice, []string{s.Stage,
+			strconv.Itoa(s.Total), strconv.Itoa(s.Created), strconv.Itoa(s.Updated), strconv.Itoa(s.Skipped),
+			strconv.Itoa(s.Resumed), strconv.Itoa(s.Conflicted), strconv.Itoa(s.Failed),
+			strings.Join(s.Errors, "\n")})
+	}
+	return
+}
+
+func (sp *StagesPrinter) PrintTitle() []string {
+	return stageTableHeader
+}
+
+// Sorter keeps the stages in the migration order
+func (sp *StagesPrinter) Sorter() *writer.RecordsSorter {
+	return writer.NewRecordsSorter(func(a, b []string) bool { return false })
+}
+
+type DiffsPrinter struct {
+	Records []*migrate.Diff
+	flags   []interface{}
+}
+
+func (dp *DiffsPrinter) Flags(flags ...interface{}) []interface{} {
+	if len(flags) > 0 {
+		dp.flags = flags
+	}
+	return dp.flags
+}
+
+func (dp *DiffsPrinter) PrintBody() (slice [][]string) {
+	for _, d := range dp.Records {
+		slice = append(slice, []string{d.Stage, d.Key, d.Reason})
+	}
+	return
+}
+
+func (dp *DiffsPrinter) PrintTitle() []string {
+	return diffTableHeader
+}
+
+func (dp *DiffsPrinter) Sorter() *writer.RecordsSorter {
+	return writer.NewRecordsSorter(func(a, b []string) bool {
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+}
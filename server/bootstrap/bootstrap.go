@@ -46,6 +46,9 @@ import (
 	//tracing
 	_ "github.com/apache/servicecomb-service-center/server/plugin/tracing/pzipkin"
 
+	//auditlog
+	_ "github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+
 	//tlsconf
 	_ "github.com/apache/servicecomb-service-center/server/plugin/security/tlsconf/buildin"
 
@@ -59,6 +62,8 @@ import (
 	_ "github.com/apache/servicecomb-service-center/server/rest/syncer"
 
 	//governance
+	_ "github.com/apache/servicecomb-service-center/server/service/gov/buildin"
+	_ "github.com/apache/servicecomb-service-center/server/service/gov/istio"
 	_ "github.com/apache/servicecomb-service-center/server/service/gov/kie"
 
 	//metrics
@@ -67,6 +72,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/broker"
 	"github.com/apache/servicecomb-service-center/server/handler/accesslog"
+	"github.com/apache/servicecomb-service-center/server/handler/auditlog"
 	"github.com/apache/servicecomb-service-center/server/handler/auth"
 	"github.com/apache/servicecomb-service-center/server/handler/context"
 	"github.com/apache/servicecomb-service-center/server/handler/exception"
@@ -91,6 +97,7 @@ func init() {
 	context.RegisterHandlers()
 	accesslog.RegisterHandlers()
 	maxbody.RegisterHandlers()
+	auditlog.RegisterHandlers()
 	auth.RegisterHandlers()
 	metrics.RegisterHandlers()
 	tracing.RegisterHandlers()
@@ -142,6 +142,14 @@ func GenerateBrokerTagKey(tenant string, versionID int32) string {
 	}, "/")
 }
 
+//GenerateBrokerVersionTagKey returns the key of a tag of the version
+func GenerateBrokerVersionTagKey(tenant string, versionID int32, name string) string {
+	return util.StringJoin([]string{
+		GenerateBrokerTagKey(tenant, versionID),
+		name,
+	}, "/")
+}
+
 //GetBrokerVerificationKey returns the verification root key
 func GetBrokerVerificationKey(tenant string) string {
 	return util.StringJoin([]string{
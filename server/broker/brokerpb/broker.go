@@ -55,6 +55,7 @@ type Version struct {
 	Number        string `protobuf:"bytes,2,opt,name=number" json:"number,omitempty"`
 	ParticipantId int32  `protobuf:"varint,3,opt,name=participantId" json:"participantId,omitempty"`
 	Order         int32  `protobuf:"varint,4,opt,name=order" json:"order,omitempty"`
+	Branch        string `protobuf:"bytes,5,opt,name=branch" json:"branch,omitempty"`
 }
 
 func (m *Version) Reset() { *m = Version{} }
@@ -227,8 +228,9 @@ type PublishPactResponse struct {
 func (m *PublishPactResponse) Reset() { *m = PublishPactResponse{} }
 
 type GetAllProviderPactsRequest struct {
-	ProviderId string             `protobuf:"bytes,1,opt,name=providerId" json:"providerId,omitempty"`
-	BaseUrl    *BaseBrokerRequest `protobuf:"bytes,2,opt,name=baseUrl" json:"baseUrl,omitempty"`
+	ProviderId               string                     `protobuf:"bytes,1,opt,name=providerId" json:"providerId,omitempty"`
+	BaseUrl                  *BaseBrokerRequest         `protobuf:"bytes,2,opt,name=baseUrl" json:"baseUrl,omitempty"`
+	ConsumerVersionSelectors []*ConsumerVersionSelector `protobuf:"bytes,3,rep,name=consumerVersionSelectors" json:"consumerVersionSelectors,omitempty"`
 }
 
 func (m *GetAllProviderPactsRequest) Reset() { *m = GetAllProviderPactsRequest{} }
@@ -430,10 +432,11 @@ func (m *VerificationSummary) GetUnknown() []string {
 }
 
 type VerificationDetail struct {
-	ProviderName               string `protobuf:"bytes,1,opt,name=providerName" json:"providerName,omitempty"`
-	ProviderApplicationVersion string `protobuf:"bytes,2,opt,name=providerApplicationVersion" json:"providerApplicationVersion,omitempty"`
-	Success                    bool   `protobuf:"varint,3,opt,name=success" json:"success,omitempty"`
-	VerificationDate           string `protobuf:"bytes,4,opt,name=verificationDate" json:"verificationDate,omitempty"`
+	ProviderName               string   `protobuf:"bytes,1,opt,name=providerName" json:"providerName,omitempty"`
+	ProviderApplicationVersion string   `protobuf:"bytes,2,opt,name=providerApplicationVersion" json:"providerApplicationVersion,omitempty"`
+	Success                    bool     `protobuf:"varint,3,opt,name=success" json:"success,omitempty"`
+	VerificationDate           string   `protobuf:"bytes,4,opt,name=verificationDate" json:"verificationDate,omitempty"`
+	ProviderTags               []string `protobuf:"bytes,5,rep,name=providerTags" json:"providerTags,omitempty"`
 }
 
 func (m *VerificationDetail) Reset() { *m = VerificationDetail{} }
@@ -675,3 +678,98 @@ func (m *BrokerHomeResponse) GetCuries() []*BrokerAPIInfoEntry {
 	}
 	return nil
 }
+
+type MatrixRequest struct {
+	AppId       string `protobuf:"bytes,1,opt,name=appId" json:"appId,omitempty"`
+	ServiceName string `protobuf:"bytes,2,opt,name=serviceName" json:"serviceName,omitempty"`
+	Version     string `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	Environment string `protobuf:"bytes,4,opt,name=environment" json:"environment,omitempty"`
+	Tag         string `protobuf:"bytes,5,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *MatrixRequest) Reset() { *m = MatrixRequest{} }
+
+type MatrixRow struct {
+	ConsumerName       string `protobuf:"bytes,1,opt,name=consumerName" json:"consumerName,omitempty"`
+	ConsumerVersion    string `protobuf:"bytes,2,opt,name=consumerVersion" json:"consumerVersion,omitempty"`
+	ProviderName       string `protobuf:"bytes,3,opt,name=providerName" json:"providerName,omitempty"`
+	ProviderVersion    string `protobuf:"bytes,4,opt,name=providerVersion" json:"providerVersion,omitempty"`
+	PactId             int32  `protobuf:"varint,5,opt,name=pactId" json:"pactId,omitempty"`
+	Verified           bool   `protobuf:"varint,6,opt,name=verified" json:"verified"`
+	Success            bool   `protobuf:"varint,7,opt,name=success" json:"success"`
+	VerificationNumber int32  `protobuf:"varint,8,opt,name=verificationNumber" json:"verificationNumber,omitempty"`
+	VerificationDate   string `protobuf:"bytes,9,opt,name=verificationDate" json:"verificationDate,omitempty"`
+}
+
+func (m *MatrixRow) Reset() { *m = MatrixRow{} }
+
+type MatrixSummary struct {
+	Deployable bool     `protobuf:"varint,1,opt,name=deployable" json:"deployable"`
+	Success    int32    `protobuf:"varint,2,opt,name=success" json:"success"`
+	Failed     int32    `protobuf:"varint,3,opt,name=failed" json:"failed"`
+	Unknown    int32    `protobuf:"varint,4,opt,name=unknown" json:"unknown"`
+	Reasons    []string `protobuf:"bytes,5,rep,name=reasons" json:"reasons,omitempty"`
+}
+
+func (m *MatrixSummary) Reset() { *m = MatrixSummary{} }
+
+type MatrixResponse struct {
+	Response *discovery.Response `protobuf:"bytes,1,opt,name=response" json:"-"`
+	Summary  *MatrixSummary      `protobuf:"bytes,2,opt,name=summary" json:"summary,omitempty"`
+	Matrix   []*MatrixRow        `protobuf:"bytes,3,rep,name=matrix" json:"matrix,omitempty"`
+}
+
+func (m *MatrixResponse) Reset() { *m = MatrixResponse{} }
+
+func (m *MatrixResponse) GetSummary() *MatrixSummary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+// ConsumerVersionSelector selects the consumer versions of the pacts to
+// verify, Latest selects the latest version of each consumer matched
+type ConsumerVersionSelector struct {
+	Consumer    string `protobuf:"bytes,1,opt,name=consumer" json:"consumer,omitempty"`
+	Tag         string `protobuf:"bytes,2,opt,name=tag" json:"tag,omitempty"`
+	Branch      string `protobuf:"bytes,3,opt,name=branch" json:"branch,omitempty"`
+	Latest      bool   `protobuf:"varint,4,opt,name=latest" json:"latest,omitempty"`
+	Deployed    bool   `protobuf:"varint,5,opt,name=deployed" json:"deployed,omitempty"`
+	Environment string `protobuf:"bytes,6,opt,name=environment" json:"environment,omitempty"`
+}
+
+func (m *ConsumerVersionSelector) Reset() { *m = ConsumerVersionSelector{} }
+
+type ParticipantVersionRequest struct {
+	ServiceId string `protobuf:"bytes,1,opt,name=serviceId" json:"serviceId,omitempty"`
+	Number    string `protobuf:"bytes,2,opt,name=number" json:"number,omitempty"`
+	Tag       string `protobuf:"bytes,3,opt,name=tag" json:"tag,omitempty"`
+	Branch    string `protobuf:"bytes,4,opt,name=branch" json:"branch,omitempty"`
+}
+
+func (m *ParticipantVersionRequest) Reset() { *m = ParticipantVersionRequest{} }
+
+type ParticipantVersion struct {
+	AppId       string   `protobuf:"bytes,1,opt,name=appId" json:"appId,omitempty"`
+	ServiceName string   `protobuf:"bytes,2,opt,name=serviceName" json:"serviceName,omitempty"`
+	Number      string   `protobuf:"bytes,3,opt,name=number" json:"number,omitempty"`
+	Branch      string   `protobuf:"bytes,4,opt,name=branch" json:"branch,omitempty"`
+	Tags        []string `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`
+}
+
+func (m *ParticipantVersion) Reset() { *m = ParticipantVersion{} }
+
+type ParticipantVersionResponse struct {
+	Response *discovery.Response `protobuf:"bytes,1,opt,name=response" json:"-"`
+	Version  *ParticipantVersion `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *ParticipantVersionResponse) Reset() { *m = ParticipantVersionResponse{} }
+
+func (m *ParticipantVersionResponse) GetVersion() *ParticipantVersion {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
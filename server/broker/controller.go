@@ -47,6 +47,12 @@ func (brokerService *Controller) URLPatterns() []rest.Route {
 		{Method: http.MethodGet,
 			Path: "/pacts/provider/:providerId/latest",
 			Func: brokerService.GetAllProviderPacts},
+		{Method: http.MethodGet,
+			Path: "/pacts/provider/:providerId/latest/:tag",
+			Func: brokerService.GetLatestProviderPactsWithTag},
+		{Method: http.MethodPost,
+			Path: "/pacts/provider/:providerId/for-verification",
+			Func: brokerService.GetProviderPactsForVerification},
 		{Method: http.MethodGet,
 			Path: "/pacts/provider/:providerId/consumer/:consumerId/version/:number",
 			Func: brokerService.GetPactsOfProvider},
@@ -59,6 +65,21 @@ func (brokerService *Controller) URLPatterns() []rest.Route {
 		{Method: http.MethodGet,
 			Path: "/verification-results/consumer/:consumerId/version/:consumerVersion/latest",
 			Func: brokerService.RetrieveVerificationResults},
+		{Method: http.MethodGet,
+			Path: "/matrix",
+			Func: brokerService.RetrieveMatrix},
+		{Method: http.MethodGet,
+			Path: "/participants/:serviceId/versions/:number",
+			Func: brokerService.GetParticipantVersion},
+		{Method: http.MethodPut,
+			Path: "/participants/:serviceId/versions/:number/tags/:tag",
+			Func: brokerService.AddVersionTag},
+		{Method: http.MethodDelete,
+			Path: "/participants/:serviceId/versions/:number/tags/:tag",
+			Func: brokerService.DeleteVersionTag},
+		{Method: http.MethodPut,
+			Path: "/participants/:serviceId/branches/:branch/versions/:number",
+			Func: brokerService.SetVersionBranch},
 	}
 }
 
@@ -120,6 +141,54 @@ func (*Controller) GetAllProviderPacts(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
+// GetLatestProviderPactsWithTag returns the latest pacts of the consumers
+// tagged with the tag
+func (brokerService *Controller) GetLatestProviderPactsWithTag(w http.ResponseWriter, r *http.Request) {
+	brokerService.getProviderPacts(w, r, []*brokerpb.ConsumerVersionSelector{
+		{Tag: r.URL.Query().Get(":tag"), Latest: true},
+	})
+}
+
+// GetProviderPactsForVerification returns the pacts of the consumer versions
+// chosen by the consumer version selectors in the body
+func (brokerService *Controller) GetProviderPactsForVerification(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		PactLogger.Error("body err", err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &brokerpb.GetAllProviderPactsRequest{}
+	if len(requestBody) > 0 {
+		err = json.Unmarshal(requestBody, request)
+		if err != nil {
+			PactLogger.Error("Unmarshal error", err)
+			rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+	brokerService.getProviderPacts(w, r, request.ConsumerVersionSelectors)
+}
+
+func (*Controller) getProviderPacts(w http.ResponseWriter, r *http.Request,
+	selectors []*brokerpb.ConsumerVersionSelector) {
+	request := &brokerpb.GetAllProviderPactsRequest{
+		ProviderId: r.URL.Query().Get(":providerId"),
+		BaseUrl: &brokerpb.BaseBrokerRequest{
+			HostAddress: r.Host,
+			Scheme:      getScheme(r),
+		},
+		ConsumerVersionSelectors: selectors,
+	}
+	resp, err := ServiceAPI.GetAllProviderPacts(r.Context(), request)
+	if err != nil {
+		PactLogger.Errorf(err, "can not get pacts")
+		rest.WriteError(w, pb.ErrInternal, "can not get pacts")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
 func (*Controller) GetPactsOfProvider(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	request := &brokerpb.GetProviderConsumerVersionPactRequest{
@@ -192,6 +261,77 @@ func (*Controller) RetrieveVerificationResults(w http.ResponseWriter, r *http.Re
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
+func (*Controller) RetrieveMatrix(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &brokerpb.MatrixRequest{
+		AppId:       query.Get("appId"),
+		ServiceName: query.Get("serviceName"),
+		Version:     query.Get("version"),
+		Environment: query.Get("environment"),
+		Tag:         query.Get("tag"),
+	}
+	PactLogger.Infof("Retrieve matrix for: %s, %s, %s, %s, %s\n",
+		request.AppId, request.ServiceName, request.Version, request.Environment, request.Tag)
+	resp, err := ServiceAPI.RetrieveMatrix(r.Context(), request)
+	if err != nil {
+		PactLogger.Error("retrieve matrix failed", err)
+		rest.WriteError(w, pb.ErrInternal, "retrieve matrix failed")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (*Controller) GetParticipantVersion(w http.ResponseWriter, r *http.Request) {
+	resp, _ := ServiceAPI.GetParticipantVersion(r.Context(), participantVersionRequest(r))
+	rest.WriteResponse(w, r, resp.Response, resp.Version)
+}
+
+func (*Controller) AddVersionTag(w http.ResponseWriter, r *http.Request) {
+	request := participantVersionRequest(r)
+	PactLogger.Infof("AddVersionTag: %s, %s, %s\n", request.ServiceId, request.Number, request.Tag)
+	resp, err := ServiceAPI.AddVersionTag(r.Context(), request)
+	if err != nil {
+		PactLogger.Error("add version tag failed", err)
+		rest.WriteError(w, pb.ErrInternal, "add version tag failed")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp.Version)
+}
+
+func (*Controller) DeleteVersionTag(w http.ResponseWriter, r *http.Request) {
+	request := participantVersionRequest(r)
+	PactLogger.Infof("DeleteVersionTag: %s, %s, %s\n", request.ServiceId, request.Number, request.Tag)
+	resp, err := ServiceAPI.DeleteVersionTag(r.Context(), request)
+	if err != nil {
+		PactLogger.Error("delete version tag failed", err)
+		rest.WriteError(w, pb.ErrInternal, "delete version tag failed")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp.Version)
+}
+
+func (*Controller) SetVersionBranch(w http.ResponseWriter, r *http.Request) {
+	request := participantVersionRequest(r)
+	PactLogger.Infof("SetVersionBranch: %s, %s, %s\n", request.ServiceId, request.Number, request.Branch)
+	resp, err := ServiceAPI.SetVersionBranch(r.Context(), request)
+	if err != nil {
+		PactLogger.Error("set version branch failed", err)
+		rest.WriteError(w, pb.ErrInternal, "set version branch failed")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp.Version)
+}
+
+func participantVersionRequest(r *http.Request) *brokerpb.ParticipantVersionRequest {
+	query := r.URL.Query()
+	return &brokerpb.ParticipantVersionRequest{
+		ServiceId: query.Get(":serviceId"),
+		Number:    query.Get(":number"),
+		Tag:       query.Get(":tag"),
+		Branch:    query.Get(":branch"),
+	}
+}
+
 func getScheme(r *http.Request) string {
 	if len(r.URL.Scheme) < 1 {
 		return DefaultScheme
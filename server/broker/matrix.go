@@ -0,0 +1,356 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+const defaultAppID = "default"
+
+// RetrieveMatrix answers whether the consumer version can be deployed. Every
+// pact of the version must be verified successfully by the latest
+// verification result. If the environment or the tag is specified, every
+// provider version registered in the environment or tagged with the tag must
+// verify the pact successfully
+func (*Service) RetrieveMatrix(ctx context.Context, in *brokerpb.MatrixRequest) (*brokerpb.MatrixResponse, error) {
+	if in == nil || len(in.ServiceName) == 0 || len(in.Version) == 0 {
+		PactLogger.Errorf(nil, "matrix retrieve request failed: invalid params.")
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	appID := in.AppId
+	if len(appID) == 0 {
+		appID = defaultAppID
+	}
+	tenant := GetDefaultTenantProject()
+	consumer, err := GetParticipant(ctx, tenant, appID, in.ServiceName)
+	if err != nil {
+		PactLogger.Errorf(err, "matrix retrieve request failed, participant cannot be searched.")
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "participant cannot be searched."),
+		}, err
+	}
+	if consumer == nil {
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Participant does not exist."),
+		}, nil
+	}
+	version, err := GetVersion(ctx, tenant, in.Version, consumer.Id)
+	if err != nil {
+		PactLogger.Errorf(err, "matrix retrieve request failed, version cannot be searched.")
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "version cannot be searched."),
+		}, err
+	}
+	if version == nil {
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Version does not exist."),
+		}, nil
+	}
+	pactVersions, err := searchPactVersions(ctx, tenant, version.Id)
+	if err != nil {
+		PactLogger.Errorf(err, "matrix retrieve request failed, pact versions cannot be searched.")
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "pact versions cannot be searched."),
+		}, err
+	}
+	participants, err := searchParticipants(ctx, tenant)
+	if err != nil {
+		PactLogger.Errorf(err, "matrix retrieve request failed, participants cannot be searched.")
+		return &brokerpb.MatrixResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "participants cannot be searched."),
+		}, err
+	}
+	var services []*pb.MicroService
+	if len(in.Environment) > 0 {
+		services, err = serviceUtil.GetServicesByDomainProject(ctx, tenant)
+		if err != nil {
+			PactLogger.Errorf(err, "matrix retrieve request failed, services cannot be searched.")
+			return &brokerpb.MatrixResponse{
+				Response: pb.CreateResponse(pb.ErrInternal, "services cannot be searched."),
+			}, err
+		}
+	}
+
+	summary := &brokerpb.MatrixSummary{}
+	rows := make([]*brokerpb.MatrixRow, 0, len(pactVersions))
+	for _, pactVersion := range pactVersions {
+		provider, ok := participants[pactVersion.ProviderParticipantId]
+		if !ok {
+			summary.Unknown++
+			summary.Reasons = append(summary.Reasons,
+				fmt.Sprintf("the provider participant of pact %d does not exist", pactVersion.PactId))
+			continue
+		}
+		verifications, err := searchVerifications(ctx, tenant, pactVersion.Id)
+		if err != nil {
+			PactLogger.Errorf(err, "matrix retrieve request failed, verification results cannot be searched.")
+			return &brokerpb.MatrixResponse{
+				Response: pb.CreateResponse(pb.ErrInternal, "verification results cannot be searched."),
+			}, err
+		}
+		if len(in.Environment) == 0 && len(in.Tag) == 0 {
+			rows = append(rows, newMatrixRow(consumer, version, provider, pactVersion,
+				latestVerification(verifications, nil)))
+			continue
+		}
+		candidates, reason, err := candidateVersions(ctx, tenant, services, provider, in)
+		if err != nil {
+			PactLogger.Errorf(err, "matrix retrieve request failed, provider versions cannot be searched.")
+			return &brokerpb.MatrixResponse{
+				Response: pb.CreateResponse(pb.ErrInternal, "provider versions cannot be searched."),
+			}, err
+		}
+		if len(candidates) == 0 {
+			summary.Unknown++
+			summary.Reasons = append(summary.Reasons, reason)
+			continue
+		}
+		for _, providerVersion := range candidates {
+			v := providerVersion
+			row := newMatrixRow(consumer, version, provider, pactVersion, latestVerification(verifications, &v))
+			row.ProviderVersion = providerVersion
+			rows = append(rows, row)
+		}
+	}
+	for _, row := range rows {
+		summary.Reasons = append(summary.Reasons, matrixReason(row))
+		switch {
+		case !row.Verified:
+			summary.Unknown++
+		case row.Success:
+			summary.Success++
+		default:
+			summary.Failed++
+		}
+	}
+	if len(pactVersions) == 0 {
+		summary.Reasons = append(summary.Reasons,
+			fmt.Sprintf("%s %s has no pacts to verify", consumer.ServiceName, version.Number))
+	}
+	summary.Deployable = summary.Failed == 0 && summary.Unknown == 0
+	return &brokerpb.MatrixResponse{
+		Response: pb.CreateResponse(pb.ResponseSuccess, "Matrix retrieved successfully."),
+		Summary:  summary,
+		Matrix:   rows,
+	}, nil
+}
+
+func newMatrixRow(consumer *brokerpb.Participant, version *brokerpb.Version, provider *brokerpb.Participant,
+	pactVersion *brokerpb.PactVersion, verification *brokerpb.Verification) *brokerpb.MatrixRow {
+	row := &brokerpb.MatrixRow{
+		ConsumerName:    consumer.ServiceName,
+		ConsumerVersion: version.Number,
+		ProviderName:    provider.ServiceName,
+		PactId:          pactVersion.PactId,
+	}
+	if verification != nil {
+		row.ProviderVersion = verification.ProviderVersion
+		row.Verified = true
+		row.Success = verification.Success
+		row.VerificationNumber = verification.Number
+		row.VerificationDate = verification.VerificationDate
+	}
+	return row
+}
+
+func matrixReason(row *brokerpb.MatrixRow) string {
+	pact := fmt.Sprintf("the pact between %s %s and %s", row.ConsumerName, row.ConsumerVersion, row.ProviderName)
+	if len(row.ProviderVersion) > 0 {
+		pact += " " + row.ProviderVersion
+	}
+	switch {
+	case !row.Verified:
+		return pact + " is not verified"
+	case row.Success:
+		return pact + " is verified successfully"
+	default:
+		return pact + " failed the verification"
+	}
+}
+
+// latestVerification returns the verification of the greatest number, of
+// the provider version if it is not nil
+func latestVerification(verifications []*brokerpb.Verification, providerVersion *string) *brokerpb.Verification {
+	var latest *brokerpb.Verification
+	for _, verification := range verifications {
+		if providerVersion != nil && verification.ProviderVersion != *providerVersion {
+			continue
+		}
+		if latest == nil || verification.Number > latest.Number {
+			latest = verification
+		}
+	}
+	return latest
+}
+
+// candidateVersions returns the provider versions which must verify the pact,
+// they are deployed in the environment and tagged with the tag if specified.
+// The reason is returned if there is no candidate
+func candidateVersions(ctx context.Context, tenant string, services []*pb.MicroService,
+	provider *brokerpb.Participant, in *brokerpb.MatrixRequest) ([]string, string, error) {
+	var candidates []string
+	if len(in.Environment) > 0 {
+		candidates = deployedVersions(services, provider, in.Environment)
+		if len(candidates) == 0 {
+			return nil, fmt.Sprintf("no version of %s is deployed in environment %s",
+				provider.ServiceName, in.Environment), nil
+		}
+	}
+	if len(in.Tag) == 0 {
+		return candidates, "", nil
+	}
+	tagged, err := taggedVersions(ctx, tenant, provider, in.Tag)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(in.Environment) > 0 {
+		tagged = intersectVersions(candidates, tagged)
+	}
+	if len(tagged) == 0 {
+		return nil, fmt.Sprintf("no version of %s is tagged %s", provider.ServiceName, in.Tag), nil
+	}
+	return tagged, "", nil
+}
+
+// taggedVersions returns the sorted version numbers of the participant tagged
+// with the tag
+func taggedVersions(ctx context.Context, tenant string, participant *brokerpb.Participant,
+	tag string) ([]string, error) {
+	key := util.StringJoin([]string{GetBrokerVersionKey(tenant), ""}, "/")
+	opts := append(serviceUtil.FromContext(ctx), client.WithStrKey(key), client.WithPrefix())
+	resp, err := Store().Version().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, kv := range resp.Kvs {
+		version := &brokerpb.Version{}
+		if err := json.Unmarshal(kv.Value.([]byte), version); err != nil {
+			return nil, err
+		}
+		if version.ParticipantId != participant.Id {
+			continue
+		}
+		tags, err := GetVersionTags(ctx, tenant, version.Id)
+		if err != nil {
+			return nil, err
+		}
+		if i := sort.SearchStrings(tags, tag); i < len(tags) && tags[i] == tag {
+			versions = append(versions, version.Number)
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+func intersectVersions(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	var versions []string
+	for _, v := range a {
+		if set[v] {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// deployedVersions returns the versions of the provider registered in the
+// environment
+func deployedVersions(services []*pb.MicroService, provider *brokerpb.Participant, env string) []string {
+	var versions []string
+	for _, service := range services {
+		if service.AppId == provider.AppId && service.ServiceName == provider.ServiceName &&
+			service.Environment == env {
+			versions = append(versions, service.Version)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func searchParticipants(ctx context.Context, tenant string) (map[int32]*brokerpb.Participant, error) {
+	key := util.StringJoin([]string{GetBrokerParticipantKey(tenant), ""}, "/")
+	opts := append(serviceUtil.FromContext(ctx), client.WithStrKey(key), client.WithPrefix())
+	resp, err := Store().Participant().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	participants := make(map[int32]*brokerpb.Participant, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		participant := &brokerpb.Participant{}
+		if err := json.Unmarshal(kv.Value.([]byte), participant); err != nil {
+			return nil, err
+		}
+		participants[participant.Id] = participant
+	}
+	return participants, nil
+}
+
+// searchPactVersions returns the pact versions of the version ordered by id
+func searchPactVersions(ctx context.Context, tenant string, versionID int32) ([]*brokerpb.PactVersion, error) {
+	key := util.StringJoin([]string{GetBrokerPactVersionKey(tenant), strconv.Itoa(int(versionID)), ""}, "/")
+	opts := append(serviceUtil.FromContext(ctx), client.WithStrKey(key), client.WithPrefix())
+	resp, err := Store().PactVersion().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pactVersions := make([]*brokerpb.PactVersion, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		pactVersion := &brokerpb.PactVersion{}
+		if err := json.Unmarshal(kv.Value.([]byte), pactVersion); err != nil {
+			return nil, err
+		}
+		pactVersions = append(pactVersions, pactVersion)
+	}
+	sort.Slice(pactVersions, func(i, j int) bool { return pactVersions[i].Id < pactVersions[j].Id })
+	return pactVersions, nil
+}
+
+func searchVerifications(ctx context.Context, tenant string, pactVersionID int32) ([]*brokerpb.Verification, error) {
+	key := util.StringJoin([]string{GetBrokerVerificationKey(tenant), strconv.Itoa(int(pactVersionID)), ""}, "/")
+	opts := append(serviceUtil.FromContext(ctx), client.WithStrKey(key), client.WithPrefix())
+	resp, err := Store().Verification().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	verifications := make([]*brokerpb.Verification, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		verification := &brokerpb.Verification{}
+		if err := json.Unmarshal(kv.Value.([]byte), verification); err != nil {
+			return nil, err
+		}
+		verifications = append(verifications, verification)
+	}
+	return verifications, nil
+}
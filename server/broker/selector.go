@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package broker
+
+import (
+	"context"
+	"sort"
+
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// versionSelector matches the consumer versions with the selectors, the
+// participants, tags and services are searched once when needed
+type versionSelector struct {
+	ctx          context.Context
+	tenant       string
+	participants map[int32]*brokerpb.Participant
+	tags         map[int32][]string
+	services     []*pb.MicroService
+	loaded       bool
+}
+
+// selectConsumerVersions returns the consumer versions of the pacts matched
+// by any of the selectors, grouped by the consumer participant and ordered.
+// The latest version of each consumer is selected if there are no selectors
+func selectConsumerVersions(ctx context.Context, tenant string, versions []*brokerpb.Version,
+	selectors []*brokerpb.ConsumerVersionSelector) (map[int32][]*brokerpb.Version, error) {
+	if len(selectors) == 0 {
+		selectors = []*brokerpb.ConsumerVersionSelector{{Latest: true}}
+	}
+	vs := &versionSelector{ctx: ctx, tenant: tenant, tags: make(map[int32][]string)}
+	selected := make(map[int32]*brokerpb.Version)
+	for _, selector := range selectors {
+		latest := make(map[int32]*brokerpb.Version)
+		for _, version := range versions {
+			ok, err := vs.match(selector, version)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			if !selector.Latest {
+				selected[version.Id] = version
+				continue
+			}
+			if current, ok := latest[version.ParticipantId]; !ok || version.Order > current.Order {
+				latest[version.ParticipantId] = version
+			}
+		}
+		for _, version := range latest {
+			selected[version.Id] = version
+		}
+	}
+	r := make(map[int32][]*brokerpb.Version)
+	for _, version := range selected {
+		r[version.ParticipantId] = append(r[version.ParticipantId], version)
+	}
+	for _, list := range r {
+		sort.Slice(list, func(i, j int) bool { return list[i].Order < list[j].Order })
+	}
+	return r, nil
+}
+
+func (vs *versionSelector) match(selector *brokerpb.ConsumerVersionSelector, version *brokerpb.Version) (bool, error) {
+	if len(selector.Branch) > 0 && version.Branch != selector.Branch {
+		return false, nil
+	}
+	if len(selector.Consumer) > 0 || selector.Deployed || len(selector.Environment) > 0 {
+		participant, err := vs.participant(version.ParticipantId)
+		if err != nil || participant == nil {
+			return false, err
+		}
+		if len(selector.Consumer) > 0 && participant.ServiceName != selector.Consumer {
+			return false, nil
+		}
+		if selector.Deployed || len(selector.Environment) > 0 {
+			deployed, err := vs.deployed(participant, version.Number, selector.Environment)
+			if err != nil || !deployed {
+				return false, err
+			}
+		}
+	}
+	if len(selector.Tag) > 0 {
+		tags, ok := vs.tags[version.Id]
+		if !ok {
+			var err error
+			tags, err = GetVersionTags(vs.ctx, vs.tenant, version.Id)
+			if err != nil {
+				return false, err
+			}
+			vs.tags[version.Id] = tags
+		}
+		i := sort.SearchStrings(tags, selector.Tag)
+		return i < len(tags) && tags[i] == selector.Tag, nil
+	}
+	return true, nil
+}
+
+func (vs *versionSelector) participant(id int32) (*brokerpb.Participant, error) {
+	if vs.participants == nil {
+		participants, err := searchParticipants(vs.ctx, vs.tenant)
+		if err != nil {
+			return nil, err
+		}
+		vs.participants = participants
+	}
+	return vs.participants[id], nil
+}
+
+// deployed returns whether the version of the participant is registered,
+// in the environment if it is not empty
+func (vs *versionSelector) deployed(participant *brokerpb.Participant, number, env string) (bool, error) {
+	if !vs.loaded {
+		services, err := serviceUtil.GetServicesByDomainProject(vs.ctx, vs.tenant)
+		if err != nil {
+			return false, err
+		}
+		vs.services, vs.loaded = services, true
+	}
+	for _, service := range vs.services {
+		if service.AppId == participant.AppId && service.ServiceName == participant.ServiceName &&
+			service.Version == number && (len(env) == 0 || service.Environment == env) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
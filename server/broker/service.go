@@ -142,9 +142,7 @@ func (*Service) RetrieveProviderPacts(ctx context.Context,
 	PactLogger.Infof("[RetrieveProviderPacts] Provider participant id : %d", providerParticipant.Id)
 	// Get all versions
 	versionKey := util.StringJoin([]string{GetBrokerVersionKey(tenant), ""}, "/")
-	versions, err := Store().Version().Search(ctx,
-		client.WithPrefix(),
-		client.WithStrKey(versionKey))
+	versions, err := Store().Version().Search(ctx, client.WithPrefix(), client.WithStrKey(versionKey))
 
 	if err != nil {
 		return nil, err
@@ -166,9 +164,7 @@ func (*Service) RetrieveProviderPacts(ctx context.Context,
 	}
 	// Get all pactversions and filter using the provider participant id
 	pactVersionKey := util.StringJoin([]string{GetBrokerPactVersionKey(tenant), ""}, "/")
-	pactVersions, err := Store().PactVersion().Search(ctx,
-		client.WithStrKey(pactVersionKey),
-		client.WithPrefix())
+	pactVersions, err := Store().PactVersion().Search(ctx, client.WithStrKey(pactVersionKey), client.WithPrefix())
 
 	if err != nil {
 		return nil, err
@@ -177,7 +173,7 @@ func (*Service) RetrieveProviderPacts(ctx context.Context,
 		PactLogger.Info("[RetrieveProviderPacts] No pact version found, sorry")
 		return nil, nil
 	}
-	participantToVersionObj := make(map[int32]brokerpb.Version)
+	pactVersionObjs := make([]*brokerpb.Version, 0, len(pactVersions.Kvs))
 	for i := 0; i < len(pactVersions.Kvs); i++ {
 		pactVersion := &brokerpb.PactVersion{}
 		err = json.Unmarshal(pactVersions.Kvs[i].Value.([]byte), pactVersion)
@@ -189,19 +185,16 @@ func (*Service) RetrieveProviderPacts(ctx context.Context,
 		}
 		PactLogger.Infof("[RetrieveProviderPacts] Pact version found: (%d, %d, %d, %d)", pactVersion.Id, pactVersion.VersionId, pactVersion.PactId, pactVersion.ProviderParticipantId)
 		vObj := versionObjects[pactVersion.VersionId]
-		if v1Obj, ok := participantToVersionObj[vObj.ParticipantId]; ok {
-			if vObj.Order > v1Obj.Order {
-				participantToVersionObj[vObj.ParticipantId] = vObj
-			}
-		} else {
-			participantToVersionObj[vObj.ParticipantId] = vObj
-		}
+		pactVersionObjs = append(pactVersionObjs, &vObj)
+	}
+	// Select the consumer versions, the latest one of each consumer by default
+	participantToVersionObj, err := selectConsumerVersions(ctx, tenant, pactVersionObjs, in.ConsumerVersionSelectors)
+	if err != nil {
+		return nil, err
 	}
 	// Get all participants
 	participantKey := util.StringJoin([]string{GetBrokerParticipantKey(tenant), ""}, "/")
-	participants, err := Store().Participant().Search(ctx,
-		client.WithStrKey(participantKey),
-		client.WithPrefix())
+	participants, err := Store().Participant().Search(ctx, client.WithStrKey(participantKey), client.WithPrefix())
 
 	if err != nil {
 		return nil, err
@@ -220,29 +213,31 @@ func (*Service) RetrieveProviderPacts(ctx context.Context,
 			continue
 		}
 		PactLogger.Infof("[RetrieveProviderPacts] Consumer found: (%d, %s, %s)", participant.Id, participant.AppId, participant.ServiceName)
-		consumerVersion := participantToVersionObj[participant.Id].Number
-		consumerID, err := serviceUtil.GetServiceID(ctx, &pb.MicroServiceKey{
-			Tenant:      tenant,
-			AppId:       participant.AppId,
-			ServiceName: participant.ServiceName,
-			Version:     consumerVersion,
-		})
-		if err != nil {
-			return nil, err
-		}
-		PactLogger.Infof("[RetrieveProviderPacts] Consumer microservice found: %s", consumerID)
+		for _, vObj := range participantToVersionObj[participant.Id] {
+			consumerVersion := vObj.Number
+			consumerID, err := serviceUtil.GetServiceID(ctx, &pb.MicroServiceKey{
+				Tenant:      tenant,
+				AppId:       participant.AppId,
+				ServiceName: participant.ServiceName,
+				Version:     consumerVersion,
+			})
+			if err != nil {
+				return nil, err
+			}
+			PactLogger.Infof("[RetrieveProviderPacts] Consumer microservice found: %s", consumerID)
 
-		urlValue := GenerateBrokerAPIPath(in.BaseUrl.Scheme, in.BaseUrl.HostAddress,
-			PublishURL,
-			strings.NewReplacer(":providerId", in.ProviderId,
-				":consumerID", consumerID,
-				":number", consumerVersion))
+			urlValue := GenerateBrokerAPIPath(in.BaseUrl.Scheme, in.BaseUrl.HostAddress,
+				PublishURL,
+				strings.NewReplacer(":providerId", in.ProviderId,
+					":consumerID", consumerID,
+					":number", consumerVersion))
 
-		consumerInfo := &brokerpb.ConsumerInfo{
-			Href: urlValue,
-			Name: consumerID,
+			consumerInfo := &brokerpb.ConsumerInfo{
+				Href: urlValue,
+				Name: consumerID,
+			}
+			consumerInfoArr = append(consumerInfoArr, consumerInfo)
 		}
-		consumerInfoArr = append(consumerInfoArr, consumerInfo)
 	}
 	links := &brokerpb.Links{
 		Pacts: consumerInfoArr,
@@ -295,9 +290,7 @@ func (*Service) GetAllProviderPacts(ctx context.Context,
 	PactLogger.Infof("[RetrieveProviderPacts] Provider participant id : %d", providerParticipant.Id)
 	// Get all versions
 	versionKey := util.StringJoin([]string{GetBrokerVersionKey(tenant), ""}, "/")
-	versions, err := Store().Version().Search(ctx,
-		client.WithPrefix(),
-		client.WithStrKey(versionKey))
+	versions, err := Store().Version().Search(ctx, client.WithPrefix(), client.WithStrKey(versionKey))
 
 	if err != nil {
 		return nil, err
@@ -319,9 +312,7 @@ func (*Service) GetAllProviderPacts(ctx context.Context,
 	}
 	// Get all pactversions and filter using the provider participant id
 	pactVersionKey := util.StringJoin([]string{GetBrokerPactVersionKey(tenant), ""}, "/")
-	pactVersions, err := Store().PactVersion().Search(ctx,
-		client.WithStrKey(pactVersionKey),
-		client.WithPrefix())
+	pactVersions, err := Store().PactVersion().Search(ctx, client.WithStrKey(pactVersionKey), client.WithPrefix())
 
 	if err != nil {
 		return nil, err
@@ -330,7 +321,7 @@ func (*Service) GetAllProviderPacts(ctx context.Context,
 		PactLogger.Info("[RetrieveProviderPacts] No pact version found, sorry")
 		return nil, nil
 	}
-	participantToVersionObj := make(map[int32]brokerpb.Version)
+	pactVersionObjs := make([]*brokerpb.Version, 0, len(pactVersions.Kvs))
 	for i := 0; i < len(pactVersions.Kvs); i++ {
 		pactVersion := &brokerpb.PactVersion{}
 		err = json.Unmarshal(pactVersions.Kvs[i].Value.([]byte), pactVersion)
@@ -342,19 +333,16 @@ func (*Service) GetAllProviderPacts(ctx context.Context,
 		}
 		PactLogger.Infof("[RetrieveProviderPacts] Pact version found: (%d, %d, %d, %d)", pactVersion.Id, pactVersion.VersionId, pactVersion.PactId, pactVersion.ProviderParticipantId)
 		vObj := versionObjects[pactVersion.VersionId]
-		if v1Obj, ok := participantToVersionObj[vObj.ParticipantId]; ok {
-			if vObj.Order > v1Obj.Order {
-				participantToVersionObj[vObj.ParticipantId] = vObj
-			}
-		} else {
-			participantToVersionObj[vObj.ParticipantId] = vObj
-		}
+		pactVersionObjs = append(pactVersionObjs, &vObj)
+	}
+	// Select the consumer versions, the latest one of each consumer by default
+	participantToVersionObj, err := selectConsumerVersions(ctx, tenant, pactVersionObjs, in.ConsumerVersionSelectors)
+	if err != nil {
+		return nil, err
 	}
 	// Get all participants
 	participantKey := util.StringJoin([]string{GetBrokerParticipantKey(tenant), ""}, "/")
-	participants, err := Store().Participant().Search(ctx,
-		client.WithStrKey(participantKey),
-		client.WithPrefix())
+	participants, err := Store().Participant().Search(ctx, client.WithStrKey(participantKey), client.WithPrefix())
 
 	if err != nil {
 		return nil, err
@@ -373,29 +361,31 @@ func (*Service) GetAllProviderPacts(ctx context.Context,
 			continue
 		}
 		PactLogger.Infof("[RetrieveProviderPacts] Consumer found: (%d, %s, %s)", participant.Id, participant.AppId, participant.ServiceName)
-		consumerVersion := participantToVersionObj[participant.Id].Number
-		consumerID, err := serviceUtil.GetServiceID(ctx, &pb.MicroServiceKey{
-			Tenant:      tenant,
-			AppId:       participant.AppId,
-			ServiceName: participant.ServiceName,
-			Version:     consumerVersion,
-		})
-		if err != nil {
-			return nil, err
-		}
-		PactLogger.Infof("[RetrieveProviderPacts] Consumer microservice found: %s", consumerID)
+		for _, vObj := range participantToVersionObj[participant.Id] {
+			consumerVersion := vObj.Number
+			consumerID, err := serviceUtil.GetServiceID(ctx, &pb.MicroServiceKey{
+				Tenant:      tenant,
+				AppId:       participant.AppId,
+				ServiceName: participant.ServiceName,
+				Version:     consumerVersion,
+			})
+			if err != nil {
+				return nil, err
+			}
+			PactLogger.Infof("[RetrieveProviderPacts] Consumer microservice found: %s", consumerID)
 
-		urlValue := GenerateBrokerAPIPath(in.BaseUrl.Scheme, in.BaseUrl.HostAddress,
-			PublishURL,
-			strings.NewReplacer(":providerId", in.ProviderId,
-				":consumerID", consumerID,
-				":number", consumerVersion))
+			urlValue := GenerateBrokerAPIPath(in.BaseUrl.Scheme, in.BaseUrl.HostAddress,
+				PublishURL,
+				strings.NewReplacer(":providerId", in.ProviderId,
+					":consumerID", consumerID,
+					":number", consumerVersion))
 
-		consumerInfo := &brokerpb.ConsumerInfo{
-			Href: urlValue,
-			Name: consumerID,
+			consumerInfo := &brokerpb.ConsumerInfo{
+				Href: urlValue,
+				Name: consumerID,
+			}
+			consumerInfoArr = append(consumerInfoArr, consumerInfo)
 		}
-		consumerInfoArr = append(consumerInfoArr, consumerInfo)
 	}
 	links := &brokerpb.Links{
 		Pacts: consumerInfoArr,
@@ -569,6 +559,23 @@ func (*Service) RetrieveVerificationResults(ctx context.Context, in *brokerpb.Re
 			Success:                    lastVerificationResult.Success,
 			VerificationDate:           lastVerificationResult.VerificationDate,
 		}
+		// the provider version exists only if it is tagged or branched
+		providerVersion, err := GetVersion(ctx, tenant, lastVerificationResult.ProviderVersion, providerParticipant.Id)
+		if err != nil {
+			PactLogger.Errorf(err, "verification result retrieve request failed, provider version cannot be searched.")
+			return &brokerpb.RetrieveVerificationResponse{
+				Response: pb.CreateResponse(pb.ErrInternal, "provider version cannot be searched."),
+			}, err
+		}
+		if providerVersion != nil {
+			verificationDetail.ProviderTags, err = GetVersionTags(ctx, tenant, providerVersion.Id)
+			if err != nil {
+				PactLogger.Errorf(err, "verification result retrieve request failed, provider tags cannot be searched.")
+				return &brokerpb.RetrieveVerificationResponse{
+					Response: pb.CreateResponse(pb.ErrInternal, "provider tags cannot be searched."),
+				}, err
+			}
+		}
 		verificationDetailsArr = append(verificationDetailsArr, verificationDetail)
 		if verificationDetail.Success {
 			successfuls = append(successfuls, providerName)
@@ -216,6 +216,163 @@ var _ = Describe("Controller", func() {
 				Expect(respVerification.Response.GetCode()).To(Equal(pb.ResponseSuccess))
 			})
 
+			It("RetrieveMatrix", func() {
+				fmt.Println("UT===========RetrieveMatrix")
+
+				request := &brokerpb.MatrixRequest{
+					AppId:       TEST_BROKER_CONSUMER_APP,
+					ServiceName: TEST_BROKER_CONSUMER_NAME,
+					Version:     TEST_BROKER_CONSUMER_VERSION,
+				}
+				respMatrix, err := brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respMatrix.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respMatrix.Summary.Deployable).To(BeFalse())
+				Expect(respMatrix.Summary.Failed).To(Equal(int32(1)))
+				Expect(len(respMatrix.Matrix)).To(Equal(1))
+				Expect(respMatrix.Matrix[0].ProviderVersion).To(Equal(TEST_BROKER_PROVIDER_VERSION))
+
+				id, err := broker.GetData(context.Background(), broker.GetBrokerLatestPactIDKey())
+				Expect(err).To(BeNil())
+				_, err = brokerResource.PublishVerificationResults(getContext(),
+					&brokerpb.PublishVerificationRequest{
+						ProviderId:                 providerServiceId,
+						ConsumerId:                 consumerServiceId,
+						PactId:                     int32(id),
+						Success:                    true,
+						ProviderApplicationVersion: TEST_BROKER_PROVIDER_VERSION,
+					})
+				Expect(err).To(BeNil())
+				respMatrix, err = brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respMatrix.Summary.Deployable).To(BeTrue())
+				Expect(respMatrix.Summary.Success).To(Equal(int32(1)))
+
+				request.Environment = "production"
+				respMatrix, err = brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respMatrix.Summary.Deployable).To(BeFalse())
+				Expect(respMatrix.Summary.Unknown).To(Equal(int32(1)))
+
+				request.Version = TEST_BROKER_NO_VERSION
+				respMatrix, _ = brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(respMatrix.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+			})
+
+			It("ParticipantVersionTags", func() {
+				fmt.Println("UT===========ParticipantVersionTags")
+
+				request := &brokerpb.ParticipantVersionRequest{
+					ServiceId: consumerServiceId,
+					Number:    TEST_BROKER_CONSUMER_VERSION,
+					Tag:       "prod",
+				}
+				respVersion, err := brokerResource.AddVersionTag(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respVersion.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respVersion.Version.Tags).To(Equal([]string{"prod"}))
+
+				request.Branch = "main"
+				respVersion, err = brokerResource.SetVersionBranch(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respVersion.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+
+				respVersion, err = brokerResource.GetParticipantVersion(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respVersion.Version.Branch).To(Equal("main"))
+				Expect(respVersion.Version.Tags).To(Equal([]string{"prod"}))
+
+				respVersion, _ = brokerResource.GetParticipantVersion(getContext(), &brokerpb.ParticipantVersionRequest{
+					ServiceId: consumerServiceId,
+					Number:    TEST_BROKER_NO_VERSION,
+				})
+				Expect(respVersion.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+			})
+
+			It("GetBrokerProviderPactsWithSelectors", func() {
+				fmt.Println("UT===========GetBrokerProviderPactsWithSelectors")
+
+				request := &brokerpb.GetAllProviderPactsRequest{
+					ProviderId: providerServiceId,
+					BaseUrl: &brokerpb.BaseBrokerRequest{
+						HostAddress: "localhost",
+						Scheme:      "http",
+					},
+				}
+				for _, selector := range []*brokerpb.ConsumerVersionSelector{
+					{Tag: "prod", Latest: true},
+					{Tag: "prod"},
+					{Branch: "main"},
+					{Deployed: true},
+					{Consumer: TEST_BROKER_CONSUMER_NAME, Latest: true},
+				} {
+					request.ConsumerVersionSelectors = []*brokerpb.ConsumerVersionSelector{selector}
+					respPacts, err := brokerResource.GetAllProviderPacts(getContext(), request)
+					Expect(err).To(BeNil())
+					Expect(respPacts.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+					Expect(len(respPacts.XLinks.Pacts)).To(Equal(1), "%+v", selector)
+				}
+
+				for _, selector := range []*brokerpb.ConsumerVersionSelector{
+					{Tag: "test", Latest: true},
+					{Branch: "feature"},
+					{Deployed: true, Environment: "production"},
+				} {
+					request.ConsumerVersionSelectors = []*brokerpb.ConsumerVersionSelector{selector}
+					respPacts, err := brokerResource.GetAllProviderPacts(getContext(), request)
+					Expect(err).To(BeNil())
+					Expect(respPacts.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+					Expect(len(respPacts.XLinks.Pacts)).To(Equal(0))
+				}
+			})
+
+			It("ProviderVersionTags", func() {
+				fmt.Println("UT===========ProviderVersionTags")
+
+				respVersion, err := brokerResource.AddVersionTag(getContext(), &brokerpb.ParticipantVersionRequest{
+					ServiceId: providerServiceId,
+					Number:    TEST_BROKER_PROVIDER_VERSION,
+					Tag:       "prod",
+				})
+				Expect(err).To(BeNil())
+				Expect(respVersion.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+
+				respVerification, err := brokerResource.RetrieveVerificationResults(getContext(),
+					&brokerpb.RetrieveVerificationRequest{
+						ConsumerId:      consumerServiceId,
+						ConsumerVersion: TEST_BROKER_CONSUMER_VERSION,
+					})
+				Expect(err).To(BeNil())
+				details := respVerification.Result.XEmbedded.VerificationResults
+				Expect(len(details)).To(Equal(1))
+				Expect(details[0].ProviderTags).To(Equal([]string{"prod"}))
+
+				request := &brokerpb.MatrixRequest{
+					AppId:       TEST_BROKER_CONSUMER_APP,
+					ServiceName: TEST_BROKER_CONSUMER_NAME,
+					Version:     TEST_BROKER_CONSUMER_VERSION,
+					Tag:         "prod",
+				}
+				respMatrix, err := brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respMatrix.Summary.Deployable).To(BeTrue())
+				Expect(respMatrix.Matrix[0].ProviderVersion).To(Equal(TEST_BROKER_PROVIDER_VERSION))
+
+				request.Tag = "test"
+				respMatrix, err = brokerResource.RetrieveMatrix(getContext(), request)
+				Expect(err).To(BeNil())
+				Expect(respMatrix.Summary.Deployable).To(BeFalse())
+				Expect(respMatrix.Summary.Unknown).To(Equal(int32(1)))
+
+				respVersion, err = brokerResource.DeleteVersionTag(getContext(), &brokerpb.ParticipantVersionRequest{
+					ServiceId: providerServiceId,
+					Number:    TEST_BROKER_PROVIDER_VERSION,
+					Tag:       "prod",
+				})
+				Expect(err).To(BeNil())
+				Expect(len(respVersion.Version.Tags)).To(Equal(0))
+			})
+
 			It("RetrieveProviderPacts", func() {
 				fmt.Println("UT===========RetrieveProviderPacts")
 
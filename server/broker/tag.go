@@ -0,0 +1,272 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/broker/brokerpb"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// GetParticipantVersion returns the branch and the tags of a version of the
+// participant of the service
+func (*Service) GetParticipantVersion(ctx context.Context,
+	in *brokerpb.ParticipantVersionRequest) (*brokerpb.ParticipantVersionResponse, error) {
+	if in == nil || len(in.ServiceId) == 0 || len(in.Number) == 0 {
+		PactLogger.Errorf(nil, "participant version retrieve request failed: invalid params.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	tenant := GetDefaultTenantProject()
+	resp, participant, version, err := findParticipantVersion(ctx, tenant, in)
+	if resp != nil {
+		return &brokerpb.ParticipantVersionResponse{Response: resp}, err
+	}
+	return participantVersionResponse(ctx, tenant, participant, version)
+}
+
+// AddVersionTag tags the version of the participant of the service, the
+// participant and the version are created if not exist
+func (*Service) AddVersionTag(ctx context.Context,
+	in *brokerpb.ParticipantVersionRequest) (*brokerpb.ParticipantVersionResponse, error) {
+	if in == nil || len(in.ServiceId) == 0 || len(in.Number) == 0 || len(in.Tag) == 0 {
+		PactLogger.Errorf(nil, "version tag request failed: invalid params.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	tenant := GetDefaultTenantProject()
+	resp, service, err := getParticipantService(ctx, tenant, in.ServiceId)
+	if resp != nil {
+		return &brokerpb.ParticipantVersionResponse{Response: resp}, err
+	}
+	participant, version, err := GetOrCreateParticipantVersion(ctx, tenant, service.AppId, service.ServiceName, in.Number)
+	if err != nil {
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "version cannot be created."),
+		}, err
+	}
+	data, err := json.Marshal(&brokerpb.Tag{Name: in.Tag, VersionId: version.Id})
+	if err != nil {
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "tag cannot be created."),
+		}, err
+	}
+	_, err = client.Instance().Do(ctx, client.PUT,
+		client.WithStrKey(GenerateBrokerVersionTagKey(tenant, version.Id, in.Tag)),
+		client.WithValue(data))
+	if err != nil {
+		PactLogger.Errorf(err, "version tag request failed, tag cannot be created.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "tag cannot be created."),
+		}, err
+	}
+	PactLogger.Infof("Tag created: (%s, %s, %s)", participant.ServiceName, version.Number, in.Tag)
+	return participantVersionResponse(ctx, tenant, participant, version)
+}
+
+// DeleteVersionTag removes the tag from the version of the participant of
+// the service
+func (*Service) DeleteVersionTag(ctx context.Context,
+	in *brokerpb.ParticipantVersionRequest) (*brokerpb.ParticipantVersionResponse, error) {
+	if in == nil || len(in.ServiceId) == 0 || len(in.Number) == 0 || len(in.Tag) == 0 {
+		PactLogger.Errorf(nil, "version tag request failed: invalid params.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	tenant := GetDefaultTenantProject()
+	resp, participant, version, err := findParticipantVersion(ctx, tenant, in)
+	if resp != nil {
+		return &brokerpb.ParticipantVersionResponse{Response: resp}, err
+	}
+	_, err = client.Instance().Do(ctx, client.DEL,
+		client.WithStrKey(GenerateBrokerVersionTagKey(tenant, version.Id, in.Tag)))
+	if err != nil {
+		PactLogger.Errorf(err, "version tag request failed, tag cannot be deleted.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "tag cannot be deleted."),
+		}, err
+	}
+	return participantVersionResponse(ctx, tenant, participant, version)
+}
+
+// SetVersionBranch sets the branch of the version of the participant of the
+// service, the participant and the version are created if not exist
+func (*Service) SetVersionBranch(ctx context.Context,
+	in *brokerpb.ParticipantVersionRequest) (*brokerpb.ParticipantVersionResponse, error) {
+	if in == nil || len(in.ServiceId) == 0 || len(in.Number) == 0 || len(in.Branch) == 0 {
+		PactLogger.Errorf(nil, "version branch request failed: invalid params.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request format invalid."),
+		}, nil
+	}
+	tenant := GetDefaultTenantProject()
+	resp, service, err := getParticipantService(ctx, tenant, in.ServiceId)
+	if resp != nil {
+		return &brokerpb.ParticipantVersionResponse{Response: resp}, err
+	}
+	participant, version, err := GetOrCreateParticipantVersion(ctx, tenant, service.AppId, service.ServiceName, in.Number)
+	if err != nil {
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "version cannot be created."),
+		}, err
+	}
+	version.Branch = in.Branch
+	data, err := json.Marshal(version)
+	if err != nil {
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "branch cannot be set."),
+		}, err
+	}
+	_, err = client.Instance().Do(ctx, client.PUT,
+		client.WithStrKey(GenerateBrokerVersionKey(tenant, version.Number, participant.Id)),
+		client.WithValue(data))
+	if err != nil {
+		PactLogger.Errorf(err, "version branch request failed, version cannot be updated.")
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "branch cannot be set."),
+		}, err
+	}
+	PactLogger.Infof("Branch set: (%s, %s, %s)", participant.ServiceName, version.Number, in.Branch)
+	return participantVersionResponse(ctx, tenant, participant, version)
+}
+
+// GetOrCreateParticipantVersion returns the participant of the app and the
+// service name, and its version of the number, they are created if not exist
+func GetOrCreateParticipantVersion(ctx context.Context, tenant, appID, serviceName,
+	number string) (*brokerpb.Participant, *brokerpb.Version, error) {
+	participant, err := GetParticipant(ctx, tenant, appID, serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if participant == nil {
+		id, err := GetData(ctx, GetBrokerLatestParticipantIDKey())
+		if err != nil {
+			return nil, nil, err
+		}
+		participant = &brokerpb.Participant{Id: int32(id) + 1, AppId: appID, ServiceName: serviceName}
+		_, err = CreateParticipant(ctx, GenerateBrokerParticipantKey(tenant, appID, serviceName), *participant)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	version, err := GetVersion(ctx, tenant, number, participant.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == nil {
+		order := GetLastestVersionNumberForParticipant(ctx, tenant, participant.Id) + 1
+		id, err := GetData(ctx, GetBrokerLatestVersionIDKey())
+		if err != nil {
+			return nil, nil, err
+		}
+		version = &brokerpb.Version{Id: int32(id) + 1, Number: number, ParticipantId: participant.Id, Order: order}
+		_, err = CreateVersion(ctx, GenerateBrokerVersionKey(tenant, number, participant.Id), *version)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return participant, version, nil
+}
+
+// GetVersionTags returns the sorted tag names of the version
+func GetVersionTags(ctx context.Context, tenant string, versionID int32) ([]string, error) {
+	key := util.StringJoin([]string{GenerateBrokerTagKey(tenant, versionID), ""}, "/")
+	opts := append(serviceUtil.FromContext(ctx), client.WithStrKey(key), client.WithPrefix())
+	resp, err := Store().PactTag().Search(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		tag := &brokerpb.Tag{}
+		if err := json.Unmarshal(kv.Value.([]byte), tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag.Name)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+func getParticipantService(ctx context.Context, tenant, serviceID string) (*pb.Response, *pb.MicroService, error) {
+	service, err := serviceUtil.GetService(ctx, tenant, serviceID)
+	if err != nil {
+		if errors.Is(err, datasource.ErrNoData) {
+			PactLogger.Debug(fmt.Sprintf("participant version request failed, serviceID is %s: service not exist.", serviceID))
+			return pb.CreateResponse(pb.ErrInvalidParams, "Service does not exist."), nil, nil
+		}
+		PactLogger.Error(fmt.Sprintf("participant version request failed, serviceID is %s: query service failed.", serviceID), err)
+		return pb.CreateResponse(pb.ErrInternal, "Query service failed."), nil, err
+	}
+	return nil, service, nil
+}
+
+func findParticipantVersion(ctx context.Context, tenant string,
+	in *brokerpb.ParticipantVersionRequest) (*pb.Response, *brokerpb.Participant, *brokerpb.Version, error) {
+	resp, service, err := getParticipantService(ctx, tenant, in.ServiceId)
+	if resp != nil {
+		return resp, nil, nil, err
+	}
+	participant, err := GetParticipant(ctx, tenant, service.AppId, service.ServiceName)
+	if err != nil {
+		return pb.CreateResponse(pb.ErrInternal, "participant cannot be searched."), nil, nil, err
+	}
+	var version *brokerpb.Version
+	if participant != nil {
+		version, err = GetVersion(ctx, tenant, in.Number, participant.Id)
+		if err != nil {
+			return pb.CreateResponse(pb.ErrInternal, "version cannot be searched."), nil, nil, err
+		}
+	}
+	if version == nil {
+		return pb.CreateResponse(pb.ErrInvalidParams, "Version does not exist."), nil, nil, nil
+	}
+	return nil, participant, version, nil
+}
+
+func participantVersionResponse(ctx context.Context, tenant string, participant *brokerpb.Participant,
+	version *brokerpb.Version) (*brokerpb.ParticipantVersionResponse, error) {
+	tags, err := GetVersionTags(ctx, tenant, version.Id)
+	if err != nil {
+		return &brokerpb.ParticipantVersionResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, "tags cannot be searched."),
+		}, err
+	}
+	return &brokerpb.ParticipantVersionResponse{
+		Response: pb.CreateResponse(pb.ResponseSuccess, "Success."),
+		Version: &brokerpb.ParticipantVersion{
+			AppId:       participant.AppId,
+			ServiceName: participant.ServiceName,
+			Number:      version.Number,
+			Branch:      version.Branch,
+			Tags:        tags,
+		},
+	}, nil
+}
@@ -45,6 +45,11 @@ const (
 	ProviderLatestPactsTagURL = "/pacts/provider/:providerId/latest/:tag"
 	PactsLatestURL            = "/pacts/latest"
 
+	ProviderPactsForVerificationURL = "/pacts/provider/:providerId/for-verification"
+	ParticipantVersionURL           = "/participants/:serviceId/versions/:number"
+	ParticipantVersionTagURL        = "/participants/:serviceId/versions/:number/tags/:tag"
+	ParticipantBranchVersionURL     = "/participants/:serviceId/branches/:branch/versions/:number"
+
 	PublishURL             = "/pacts/provider/:providerId/consumer/:consumerId/version/:number"
 	PublishVerificationURL = "/pacts/provider/:providerId/consumer/:consumerId/pact-version/:pact/verification-results"
 	WebhooksURL            = "/webhooks"
@@ -59,6 +64,10 @@ var brokerAPILinksValues = map[string]string{
 	"pb:pacticipants":                   ParticipantsURL,
 	"pb:latest-provider-pacts":          ProviderLatestPactsURL,
 	"pb:latest-provider-pacts-with-tag": ProviderLatestPactsTagURL,
+	"pb:pacts-for-verification":         ProviderPactsForVerificationURL,
+	"pb:version":                        ParticipantVersionURL,
+	"pb:version-tag":                    ParticipantVersionTagURL,
+	"pb:branch-version":                 ParticipantBranchVersionURL,
 	"pb:webhooks":                       WebhooksURL,
 }
 
@@ -69,6 +78,10 @@ var brokerAPILinksTempl = map[string]bool{
 	"pb:pacticipants":                   false,
 	"pb:latest-provider-pacts":          true,
 	"pb:latest-provider-pacts-with-tag": true,
+	"pb:pacts-for-verification":         true,
+	"pb:version":                        true,
+	"pb:version-tag":                    true,
+	"pb:branch-version":                 true,
 	"pb:webhooks":                       false,
 }
 
@@ -79,6 +92,10 @@ var brokerAPILinksTitles = map[string]string{
 	"pb:pacticipants":                   "Pacticipants",
 	"pb:latest-provider-pacts":          "Latest pacts by provider",
 	"pb:latest-provider-pacts-with-tag": "Latest pacts by provider with a specified tag",
+	"pb:pacts-for-verification":         "Pacts to be verified by provider selected by consumer version selectors",
+	"pb:version":                        "Participant version with its branch and tags",
+	"pb:version-tag":                    "Tag a participant version",
+	"pb:branch-version":                 "Set the branch of a participant version",
 	"pb:webhooks":                       "Webhooks",
 }
 
@@ -53,6 +53,22 @@ func GetGov() *Gov {
 	return App.Gov
 }
 
+//GetOIDC return the external identity provider configs
+func GetOIDC() *OIDC {
+	if App.RBAC == nil {
+		return nil
+	}
+	return App.RBAC.OIDC
+}
+
+//GetCertAuth return the client certificate authentication configs
+func GetCertAuth() *CertAuth {
+	if App.RBAC == nil {
+		return nil
+	}
+	return App.RBAC.CertAuth
+}
+
 //GetServer return the http server configs
 func GetServer() ServerConfigDetail {
 	return App.Server.Config
@@ -29,15 +29,72 @@ const (
 //AppConfig is yaml file struct
 type AppConfig struct {
 	Gov    *Gov          `yaml:"gov"`
+	RBAC   *RBAC         `yaml:"rbac"`
 	Server *ServerConfig `yaml:"server"`
 }
+type RBAC struct {
+	OIDC     *OIDC     `yaml:"oidc"`
+	CertAuth *CertAuth `yaml:"certAuth"`
+}
+
+// CertAuth is the options of authenticating the requests by the verified
+// client certificates, it requires ssl.verifyClient
+type CertAuth struct {
+	Enabled bool `yaml:"enabled"`
+	// Rules map the certificates to the accounts or roles, the first
+	// matched rule is applied
+	Rules []*CertRule `yaml:"rules"`
+}
+
+// CertRule matches the certificate by the patterns of path.Match, the
+// empty patterns match any certificate
+type CertRule struct {
+	// CommonName is the pattern of the subject common name
+	CommonName string `yaml:"commonName"`
+	// SAN is the pattern of the subject alternative names, including the
+	// DNS names, email addresses, IP addresses and URIs
+	SAN string `yaml:"san"`
+	// Account is the account the certificate acts as, its roles are used
+	Account string `yaml:"account"`
+	// Roles are the roles granted to the certificate if Account is empty,
+	// the subject common name is used as the user name
+	Roles []string `yaml:"roles"`
+}
+
+// OIDC is the options of the external identity provider
+type OIDC struct {
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// JWKS is the file path or the URL of the key set of the issuer
+	JWKS string `yaml:"jwks"`
+	// JWKSRefreshInterval is the max age of the loaded key set, default is 10m
+	JWKSRefreshInterval string `yaml:"jwksRefreshInterval"`
+	// UserClaim is the claim of the account name, default is sub
+	UserClaim string `yaml:"userClaim"`
+	// RolesClaim is the claim of the identity provider roles or groups,
+	// nested claims are separated by dot, e.g. realm_access.roles
+	RolesClaim string `yaml:"rolesClaim"`
+	// RoleMapping maps the claim values to the service center roles
+	RoleMapping map[string][]string `yaml:"roleMapping"`
+	// AutoProvision creates the account when it does not exist
+	AutoProvision bool `yaml:"autoProvision"`
+}
 type Gov struct {
 	DistOptions []DistributorOptions `yaml:"plugins"`
+	// Primary is the name of the distributor serving the reads, it is the
+	// first one of DistOptions if empty
+	Primary string `yaml:"primary"`
+	// Rollback reverts the succeeded creations and updates if any of the
+	// distributors failed
+	Rollback bool `yaml:"rollback"`
 }
 type DistributorOptions struct {
 	Name     string `yaml:"name"`
 	Type     string `yaml:"type"`
 	Endpoint string `yaml:"endpoint"`
+	// Namespace is the namespace of the resources created by the
+	// distributors of service mesh
+	Namespace string `yaml:"namespace"`
 }
 
 // GetImplName return the impl name
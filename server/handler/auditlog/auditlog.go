@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+)
+
+// maxBodySize limits the request body read for the audit summary
+const maxBodySize = 64 * 1024
+
+// Handler records the mutating API calls by the audit log plugin
+type Handler struct {
+	whiteListAPIs map[string]struct{} // not record audit log
+}
+
+type body struct {
+	io.Reader
+	io.Closer
+}
+
+// AddWhiteListAPIs adds APIs to white list, where the APIs will be ignored
+// in audit log.
+// Not safe for concurrent use.
+func (h *Handler) AddWhiteListAPIs(apis ...string) {
+	for _, api := range apis {
+		h.whiteListAPIs[api] = struct{}{}
+	}
+}
+
+// ShouldIgnore judges whether the request should be ignored in audit log.
+func (h *Handler) ShouldIgnore(method, api string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return true
+	}
+	_, ok := h.whiteListAPIs[api]
+	return ok
+}
+
+func (h *Handler) Handle(i *chain.Invocation) {
+	r, pattern := i.Context().Value(rest.CtxRequest).(*http.Request),
+		i.Context().Value(rest.CtxMatchPattern).(string)
+	if h.ShouldIgnore(r.Method, pattern) {
+		i.Next()
+		return
+	}
+
+	// keep the head of the body for the summary, and replay it to the API
+	head, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		log.Warnf("read request body for audit failed, %s", err.Error())
+	}
+	r.Body = &body{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+	i.WithContext(auditlog.CtxRequestBody, head)
+
+	w := i.Context().Value(rest.CtxResponse).(http.ResponseWriter)
+	i.Next(chain.WithAsyncFunc(func(_ chain.Result) {
+		auditlog.Record(r, w.Header())
+	}))
+}
+
+// NewAuditLogHandler creates a Handler
+func NewAuditLogHandler() *Handler {
+	return &Handler{whiteListAPIs: make(map[string]struct{})}
+}
+
+// RegisterHandlers registers an audit log handler to the handler chain
+func RegisterHandlers() {
+	if !config.GetBool("auditlog.enabled", false) {
+		return
+	}
+	h := NewAuditLogHandler()
+	// no audit log for heartbeat
+	h.AddWhiteListAPIs(
+		"/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat",
+		"/v4/:project/registry/heartbeats",
+		"/registry/v3/microservices/:serviceId/instances/:instanceId/heartbeat",
+		"/registry/v3/heartbeats")
+	chain.RegisterHandler(rest.ServerChainName, h)
+}
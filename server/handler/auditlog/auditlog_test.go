@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/handler/auditlog"
+	plugin "github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+)
+
+func TestHandler(t *testing.T) {
+	h := auditlog.NewAuditLogHandler()
+	testAPI := "testAPI"
+	h.AddWhiteListAPIs(testAPI)
+	assert.True(t, h.ShouldIgnore(http.MethodPost, testAPI))
+	assert.True(t, h.ShouldIgnore(http.MethodGet, "/a"))
+	assert.False(t, h.ShouldIgnore(http.MethodPut, "/a"))
+
+	body := `{"name":"a"}`
+	inv := &chain.Invocation{}
+	inv.Init(context.Background(), chain.NewChain("c", []chain.Handler{}))
+	inv.WithContext(rest.CtxMatchPattern, "/a")
+	r := httptest.NewRequest(http.MethodPut, "/a", strings.NewReader(body))
+	inv.WithContext(rest.CtxRequest, r)
+	inv.WithContext(rest.CtxResponse, httptest.NewRecorder())
+	h.Handle(inv)
+
+	assert.Equal(t, []byte(body), inv.Context().Value(plugin.CtxRequestBody))
+	b, err := ioutil.ReadAll(r.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(b))
+}
@@ -21,14 +21,24 @@ import (
 	"net/http"
 
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
-const AUDITLOG plugin.Kind = "auditlog"
+const (
+	AUDITLOG plugin.Kind = "auditlog"
+	// CtxRequestBody is the head of the request body read before the
+	// request is handled, the body is consumed after that
+	CtxRequestBody util.CtxKey = "_audit_request_body"
+)
 
 type AuditLogger interface {
 	Record(r *http.Request, responseHeaders http.Header)
 }
 
 func Record(r *http.Request, responseHeaders http.Header) {
-	plugin.Plugins().Instance(AUDITLOG).(AuditLogger).Record(r, responseHeaders)
+	l, ok := plugin.Plugins().Instance(AUDITLOG).(AuditLogger)
+	if !ok {
+		return
+	}
+	l.Record(r, responseHeaders)
 }
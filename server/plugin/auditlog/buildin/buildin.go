@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	authHandler "github.com/apache/servicecomb-service-center/server/handler/auth"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+const (
+	defaultRetention = 30 * 24 * time.Hour
+	maxSummarySize   = 1024
+	redacted         = "******"
+)
+
+// sensitiveKeys are the lower case sub strings of the redacted field names
+var sensitiveKeys = []string{"password", "secret", "token", "credential", "privatekey", "apikey"}
+
+func init() {
+	plugin.RegisterPlugin(plugin.Plugin{Kind: auditlog.AUDITLOG, Name: "buildin", New: New})
+}
+
+func New() plugin.Instance {
+	return &Logger{
+		retention: config.GetDuration("auditlog.retention", defaultRetention),
+	}
+}
+
+// Logger saves the audit records to the datasource
+type Logger struct {
+	retention time.Duration
+}
+
+func (l *Logger) Record(r *http.Request, _ http.Header) {
+	record := l.NewRecord(r)
+	// the request context is canceled after the response is written
+	err := datasource.GetAuditLogManager().AddAuditLog(context.Background(), record)
+	if err != nil {
+		log.Error(fmt.Sprintf("save audit record of %s %s failed", record.Method, record.Pattern), err)
+	}
+}
+
+// NewRecord builds the audit record from the handled request
+func (l *Logger) NewRecord(r *http.Request) *audit.Record {
+	ctx := r.Context()
+	now := time.Now()
+	record := &audit.Record{
+		ID:        util.GenerateUUID(),
+		Timestamp: now.UnixNano(),
+		Actor:     rbacsvc.UserFromContext(ctx),
+		SourceIP:  util.GetIPFromContext(ctx),
+		Domain:    util.ParseDomain(ctx),
+		Project:   util.ParseProject(ctx),
+		Method:    r.Method,
+	}
+	record.Pattern, _ = ctx.Value(rest.CtxMatchPattern).(string)
+	record.Code, _ = ctx.Value(rest.CtxResponseStatus).(int)
+	if scope, ok := ctx.Value(authHandler.CtxResourceScopes).(*auth.ResourceScope); ok && scope != nil {
+		record.Resource = scope.Type
+	}
+	for k, v := range r.URL.Query() {
+		// the path parameters, the project is recorded already
+		if !strings.HasPrefix(k, ":") || k == ":project" || len(v) == 0 {
+			continue
+		}
+		if record.ResourceIDs == nil {
+			record.ResourceIDs = make(map[string]string)
+		}
+		record.ResourceIDs[k[1:]] = v[0]
+	}
+	if body, ok := ctx.Value(auditlog.CtxRequestBody).([]byte); ok {
+		record.Summary = Summarize(body)
+	}
+	if l.retention > 0 {
+		record.ExpireAt = now.Add(l.retention).Unix()
+	}
+	return record
+}
+
+// Summarize returns the JSON body with the sensitive fields redacted, the
+// other bodies are not recorded
+func Summarize(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("[%d bytes non-JSON body]", len(body))
+	}
+	b, err := json.Marshal(redact(v))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes body]", len(body))
+	}
+	if len(b) > maxSummarySize {
+		return string(b[:maxSummarySize]) + "..."
+	}
+	return string(b)
+}
+
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range t {
+			if isSensitive(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redact(sub)
+		}
+	case []interface{}:
+		for i, sub := range t {
+			t[i] = redact(sub)
+		}
+	}
+	return v
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+	_ "github.com/apache/servicecomb-service-center/test"
+)
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, "", buildin.Summarize(nil))
+	assert.Equal(t, "[3 bytes non-JSON body]", buildin.Summarize([]byte("a=b")))
+	assert.Equal(t, `{"name":"a","password":"******","roles":["developer"]}`,
+		buildin.Summarize([]byte(`{"name":"a","password":"pwd","roles":["developer"]}`)))
+	assert.Equal(t, `{"items":[{"clientSecret":"******","id":"1"}]}`,
+		buildin.Summarize([]byte(`{"items":[{"id":"1","clientSecret":"s"}]}`)))
+}
+
+func TestLogger_NewRecord(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/v4/default/registry/microservices/s1?:project=default&:serviceId=s1", nil)
+	util.SetRequestContext(r, rest.CtxMatchPattern, "/v4/:project/registry/microservices/:serviceId")
+	util.SetRequestContext(r, rest.CtxResponseStatus, http.StatusOK)
+	util.SetRequestContext(r, rbacsvc.CtxRequestClaims, map[string]interface{}{rbac.ClaimsUser: "root"})
+	util.SetRequestContext(r, auditlog.CtxRequestBody, []byte(`{"force":true}`))
+
+	l := buildin.New().(*buildin.Logger)
+	record := l.NewRecord(r)
+	assert.NotEmpty(t, record.ID)
+	assert.Equal(t, "root", record.Actor)
+	assert.Equal(t, http.MethodDelete, record.Method)
+	assert.Equal(t, "/v4/:project/registry/microservices/:serviceId", record.Pattern)
+	assert.Equal(t, http.StatusOK, record.Code)
+	assert.Equal(t, map[string]string{"serviceId": "s1"}, record.ResourceIDs)
+	assert.Equal(t, `{"force":true}`, record.Summary)
+	assert.True(t, record.ExpireAt > 0)
+}
@@ -20,11 +20,17 @@ package buildin_test
 // initialize
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/go-chassis/cari/pkg/errsvc"
@@ -99,6 +105,12 @@ func TestTokenAuthenticator_Identify(t *testing.T) {
 		t.Log(err)
 		assert.Error(t, err)
 	})
+	t.Run("change password without auth header, should failed", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/v4/accounts/root/password", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, rbacsvc.APIAccountPassword)
+		err := ta.Identify(r)
+		assert.True(t, errsvc.IsErrEqualCode(err, carirbac.ErrNoAuthHeader))
+	})
 	t.Run("valid admin token, should be able to get account", func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, "/v4/accounts", nil)
 		to, err := authr.Login(context.TODO(), "root", "Complicated_password1")
@@ -134,6 +146,47 @@ func TestTokenAuthenticator_Identify(t *testing.T) {
 		t.Log(err)
 		assert.Error(t, err)
 	})
+	t.Run("valid normal token, should be able to inspect self permissions", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v4/self/can-i?resource=account&verb=delete", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, "/v4/self/can-i")
+		to, err := authr.Login(context.TODO(), "non-admin", "Complicated_password1")
+		assert.NoError(t, err)
+		r.Header.Set(restful.HeaderAuth, "Bear "+to)
+		assert.NoError(t, ta.Identify(r))
+
+		r = httptest.NewRequest(http.MethodGet, "/v4/self/can-i?resource=account&verb=delete", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, "/v4/self/can-i")
+		assert.Error(t, ta.Identify(r))
+	})
+	t.Run("token of an expired password, should only change the own password", func(t *testing.T) {
+		archaius.Set("rbac.passwordPolicy.maxAge", "1h")
+		defer archaius.Delete("rbac.passwordPolicy.maxAge")
+		err := datasource.GetPasswordHistoryManager().PutPasswordHistory(context.TODO(), &datasource.PasswordHistory{
+			Account:    "non-admin",
+			ChangeTime: time.Now().Add(-2 * time.Hour).Unix(),
+		})
+		assert.NoError(t, err)
+		defer datasource.GetPasswordHistoryManager().DeletePasswordHistory(context.TODO(), "non-admin")
+
+		to, err := authr.Login(context.TODO(), "non-admin", "Complicated_password1")
+		assert.True(t, errsvc.IsErrEqualCode(err, rbacsvc.ErrPasswordExpired))
+		assert.NotEmpty(t, to)
+
+		r := httptest.NewRequest(http.MethodPost, "/v4/accounts/non-admin/password?:name=non-admin", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, rbacsvc.APIAccountPassword)
+		r.Header.Set(restful.HeaderAuth, "Bear "+to)
+		assert.NoError(t, ta.Identify(r))
+
+		r = httptest.NewRequest(http.MethodPost, "/v4/accounts/root/password?:name=root", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, rbacsvc.APIAccountPassword)
+		r.Header.Set(restful.HeaderAuth, "Bear "+to)
+		assert.True(t, errsvc.IsErrEqualCode(ta.Identify(r), rbacsvc.ErrPasswordExpired))
+
+		r = httptest.NewRequest(http.MethodGet, "/v4/self/can-i?resource=account&verb=delete", nil)
+		util.SetRequestContext(r, rest.CtxMatchPattern, "/v4/self/can-i")
+		r.Header.Set(restful.HeaderAuth, "Bear "+to)
+		assert.True(t, errsvc.IsErrEqualCode(ta.Identify(r), rbacsvc.ErrPasswordExpired))
+	})
 	t.Run("valid admin token, should be able to delete account", func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodDelete, "/v4/accounts/:name", nil)
 		v := r.URL.Query()
@@ -144,6 +197,51 @@ func TestTokenAuthenticator_Identify(t *testing.T) {
 		err = ta.Identify(r)
 		assert.NoError(t, err)
 	})
+	t.Run("valid admin api key, should be able to get account", func(t *testing.T) {
+		key, err := rbacsvc.CreateAPIKey(context.TODO(), "root", &apikey.CreateRequest{Name: "ci"})
+		assert.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/v4/accounts", nil)
+		r.Header.Set(restful.HeaderAuth, "Bearer "+key.Key)
+		err = ta.Identify(r)
+		assert.NoError(t, err)
+
+		err = rbacsvc.RevokeAPIKey(context.TODO(), "root", key.ID)
+		assert.NoError(t, err)
+		r = httptest.NewRequest(http.MethodGet, "/v4/accounts", nil)
+		r.Header.Set(restful.HeaderAuth, "Bearer "+key.Key)
+		err = ta.Identify(r)
+		assert.Error(t, err)
+	})
+	t.Run("valid normal api key, should no be able to get account", func(t *testing.T) {
+		key, err := rbacsvc.CreateAPIKey(context.TODO(), "non-admin", &apikey.CreateRequest{})
+		assert.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/v4/accounts", nil)
+		r.Header.Set(restful.HeaderAuth, "Bearer "+key.Key)
+		err = ta.Identify(r)
+		assert.Error(t, err)
+		assert.NoError(t, rbacsvc.RevokeAPIKey(context.TODO(), "non-admin", key.ID))
+	})
+	t.Run("verified client certificate without auth header, should be authenticated by the mapped account", func(t *testing.T) {
+		old := config.App.RBAC
+		defer func() { config.App.RBAC = old }()
+		config.App.RBAC = &config.RBAC{CertAuth: &config.CertAuth{
+			Enabled: true,
+			Rules: []*config.CertRule{
+				{CommonName: "root-sidecar", Account: "root"},
+				{CommonName: "dev-sidecar", Account: "non-admin"},
+			},
+		}}
+		withCert := func(cn string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/v4/accounts", nil)
+			r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{
+				{{Subject: pkix.Name{CommonName: cn}}},
+			}}
+			return r
+		}
+		assert.NoError(t, ta.Identify(withCert("root-sidecar")))
+		assert.Error(t, ta.Identify(withCert("dev-sidecar")))
+		assert.Error(t, ta.Identify(withCert("unknown")))
+	})
 
 	t.Run("TestTokenAuthenticator_ResourceScopes", func(t *testing.T) {
 		url := "/v4/accounts/:name"
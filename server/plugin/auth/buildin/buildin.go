@@ -58,7 +58,6 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 	if !mustAuth(pattern) {
 		return nil
 	}
-
 	claims, err := ba.VerifyToken(req)
 	if err != nil {
 		log.Errorf(err, "verify request token failed, %s %s", req.Method, req.RequestURI)
@@ -80,6 +79,14 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 	if isChangeSelfPassword(pattern, account, req) {
 		return nil
 	}
+	// the token issued for an expired password can only change it
+	if expired, _ := m[rbacsvc.ClaimsPasswordExpired].(bool); expired {
+		return rbac.NewError(rbacsvc.ErrPasswordExpired, "")
+	}
+	// user can inspect self permissions
+	if strings.HasPrefix(pattern, rbacsvc.APISelf) {
+		return nil
+	}
 
 	if len(account.Roles) == 0 {
 		log.Error("no role found in token", nil)
@@ -122,6 +129,10 @@ func filterRoles(roleList []string) (hasAdmin bool, normalRoles []string) {
 func (ba *TokenAuthenticator) VerifyToken(req *http.Request) (interface{}, error) {
 	v := req.Header.Get(restful.HeaderAuth)
 	if v == "" {
+		// the verified client certificate acts as the token
+		if rbacsvc.CertAuthEnabled() && req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+			return rbacsvc.AuthenticateCert(req.Context(), req.TLS.VerifiedChains[0][0])
+		}
 		return nil, rbac.NewError(rbac.ErrNoAuthHeader, "")
 	}
 	s := strings.Split(v, " ")
@@ -129,11 +140,14 @@ func (ba *TokenAuthenticator) VerifyToken(req *http.Request) (interface{}, error
 		return nil, rbac.ErrInvalidHeader
 	}
 	to := s[1]
-
+	if rbacsvc.IsAPIKey(to) {
+		return rbacsvc.AuthenticateAPIKey(req.Context(), to)
+	}
 	return authr.Authenticate(req.Context(), to)
 }
 
-//this method decouple business code and perm checks
+//this method decouple business code and perm checks, the perms are checked
+//in the domain of the request and the project of the API
 func checkPerm(roleList []string, project string, req *http.Request, apiPattern, method string) (bool, []map[string]string, error) {
 	hasAdmin, normalRoles := filterRoles(roleList)
 	if hasAdmin {
@@ -144,7 +158,6 @@ func checkPerm(roleList []string, project string, req *http.Request, apiPattern,
 	if !ok || targetResource == nil {
 		return false, nil, errors.New("no valid resouce scope")
 	}
-	//TODO add project
 	return rbacsvc.Allow(req.Context(), project, normalRoles, targetResource)
 }
 
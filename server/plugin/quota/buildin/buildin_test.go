@@ -20,6 +20,7 @@ import (
 
 	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 
+	qt "github.com/apache/servicecomb-service-center/pkg/quota"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 
 	"github.com/apache/servicecomb-service-center/datasource"
@@ -29,6 +30,7 @@ import (
 
 	_ "github.com/apache/servicecomb-service-center/server/bootstrap"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	quotasvc "github.com/apache/servicecomb-service-center/server/service/quota"
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-archaius"
 	"github.com/stretchr/testify/assert"
@@ -84,3 +86,42 @@ func TestGetResourceLimit(t *testing.T) {
 	})
 
 }
+
+func TestApplyOverride(t *testing.T) {
+	ctx := util.SetDomainProject(context.TODO(), "quota_override", "quota_override")
+	defer quotasvc.DeleteQuota(ctx, "quota_override", "")
+	defer quotasvc.DeleteQuota(ctx, "quota_override", "quota_override")
+
+	t.Run("domain override, should be applied", func(t *testing.T) {
+		err := quotasvc.PutQuota(ctx, &qt.Quota{Domain: "quota_override",
+			Limits: map[string]int64{qt.ResourceService: 1, qt.ResourceInstance: 10}})
+		assert.NoError(t, err)
+
+		limit, err := quota.GetLimit(ctx, quota.TypeService)
+		assert.NoError(t, err)
+		assert.Equal(t, &qt.Limit{Resource: qt.ResourceService, Limit: 1, Scope: qt.ScopeDomain}, limit)
+		assert.Nil(t, quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeService, "quota_override/quota_override", "", 1)))
+		assert.NotNil(t, quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeService, "quota_override/quota_override", "", 2)))
+	})
+
+	t.Run("project override, should take precedence over the domain one", func(t *testing.T) {
+		err := quotasvc.PutQuota(ctx, &qt.Quota{Domain: "quota_override", Project: "quota_override",
+			Limits: map[string]int64{qt.ResourceService: 2}})
+		assert.NoError(t, err)
+
+		limit, err := quota.GetLimit(ctx, quota.TypeService)
+		assert.NoError(t, err)
+		assert.Equal(t, &qt.Limit{Resource: qt.ResourceService, Limit: 2, Scope: qt.ScopeProject}, limit)
+		assert.Nil(t, quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeService, "quota_override/quota_override", "", 2)))
+
+		limit, err = quota.GetLimit(ctx, quota.TypeInstance)
+		assert.NoError(t, err)
+		assert.Equal(t, &qt.Limit{Resource: qt.ResourceInstance, Limit: 10, Scope: qt.ScopeDomain}, limit)
+	})
+
+	t.Run("no override, should use the default", func(t *testing.T) {
+		limit, err := quota.GetLimit(ctx, quota.TypeRole)
+		assert.NoError(t, err)
+		assert.Equal(t, &qt.Limit{Resource: qt.ResourceRole, Limit: int64(quota.DefaultRoleQuota), Scope: qt.ScopeDefault}, limit)
+	})
+}
@@ -25,9 +25,10 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/config"
-	"github.com/apache/servicecomb-service-center/server/service/quota"
+	quotasvc "github.com/apache/servicecomb-service-center/server/service/quota"
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 )
@@ -54,6 +55,9 @@ const (
 	TypeRole
 )
 
+// Types are the resource types in the order of quota.Resources
+var Types = []ResourceType{TypeService, TypeInstance, TypeSchema, TypeTag, TypeRule, TypeAccount, TypeRole}
+
 var (
 	DefaultServiceQuota  = defaultServiceLimit
 	DefaultInstanceQuota = defaultInstanceLimit
@@ -118,6 +122,33 @@ func (r ResourceType) String() string {
 	}
 }
 
+// Resource returns the resource name used by the quota overrides
+func (r ResourceType) Resource() string {
+	switch r {
+	case TypeRule:
+		return quota.ResourceRule
+	case TypeSchema:
+		return quota.ResourceSchema
+	case TypeTag:
+		return quota.ResourceTag
+	case TypeService:
+		return quota.ResourceService
+	case TypeInstance:
+		return quota.ResourceInstance
+	case TypeAccount:
+		return quota.ResourceAccount
+	case TypeRole:
+		return quota.ResourceRole
+	default:
+		return ""
+	}
+}
+
+// PerService returns true if the resource is limited per service
+func (r ResourceType) PerService() bool {
+	return r == TypeRule || r == TypeSchema || r == TypeTag
+}
+
 //申请配额sourceType serviceinstance servicetype
 func Apply(ctx context.Context, res *ApplyQuotaResource) *errsvc.Error {
 	if res == nil {
@@ -125,8 +156,16 @@ func Apply(ctx context.Context, res *ApplyQuotaResource) *errsvc.Error {
 		log.Errorf(err, "quota check failed")
 		return pb.NewError(pb.ErrInternal, err.Error())
 	}
+	if util.SkipQuota(ctx) {
+		return nil
+	}
 
-	limitQuota := plugin.Plugins().Instance(QUOTA).(Manager).GetQuota(ctx, res.QuotaType)
+	limit, err := GetLimit(ctx, res.QuotaType)
+	if err != nil {
+		log.Errorf(err, "%s quota check failed", res.QuotaType)
+		return pb.NewError(pb.ErrInternal, err.Error())
+	}
+	limitQuota := limit.Limit
 	curNum, err := GetResourceUsage(ctx, res)
 	if err != nil {
 		log.Errorf(err, "%s quota check failed", res.QuotaType)
@@ -144,30 +183,85 @@ func Apply(ctx context.Context, res *ApplyQuotaResource) *errsvc.Error {
 func Remand(ctx context.Context, quotaType ResourceType) {
 	plugin.Plugins().Instance(QUOTA).(Manager).RemandQuotas(ctx, quotaType)
 }
+
+// GetLimit returns the limit of the resource in the domain/project of ctx,
+// the overrides of the project and the domain take precedence over the
+// limit of the quota plugin
+func GetLimit(ctx context.Context, t ResourceType) (*quota.Limit, error) {
+	limit, err := quotasvc.GetOverride(ctx, util.ParseDomain(ctx), util.ParseProject(ctx), t.Resource())
+	if err != nil || limit != nil {
+		return limit, err
+	}
+	return &quota.Limit{
+		Resource: t.Resource(),
+		Limit:    plugin.Plugins().Instance(QUOTA).(Manager).GetQuota(ctx, t),
+		Scope:    quota.ScopeDefault,
+	}, nil
+}
+
+// GetLimits returns the limits of all the resources in the domain/project of ctx
+func GetLimits(ctx context.Context) ([]*quota.Limit, error) {
+	limits := make([]*quota.Limit, 0, len(Types))
+	for _, t := range Types {
+		limit, err := GetLimit(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// GetUsages returns the usages against the limits in the domain/project of
+// ctx, the per service resources are counted only if serviceID is not empty
+func GetUsages(ctx context.Context, serviceID string) ([]*quota.Usage, error) {
+	usages := make([]*quota.Usage, 0, len(Types))
+	for _, t := range Types {
+		if t.PerService() && len(serviceID) == 0 {
+			continue
+		}
+		limit, err := GetLimit(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		var used int64
+		if t == TypeTag {
+			used, err = quotasvc.TagUsage(ctx, serviceID)
+		} else {
+			used, err = GetResourceUsage(ctx, NewApplyQuotaResource(t, util.ParseDomainProject(ctx), serviceID, 0))
+		}
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, &quota.Usage{Limit: *limit, Used: used})
+	}
+	return usages, nil
+}
+
 func GetResourceUsage(ctx context.Context, res *ApplyQuotaResource) (int64, error) {
 	serviceID := res.ServiceID
 	switch res.QuotaType {
 	case TypeService:
-		return quota.ServiceUsage(ctx, &pb.GetServiceCountRequest{
+		return quotasvc.ServiceUsage(ctx, &pb.GetServiceCountRequest{
 			Domain:  util.ParseDomain(ctx),
 			Project: util.ParseProject(ctx),
 		})
 	case TypeInstance:
-		return quota.InstanceUsage(ctx, &pb.GetServiceCountRequest{
+		return quotasvc.InstanceUsage(ctx, &pb.GetServiceCountRequest{
 			Domain:  util.ParseDomain(ctx),
 			Project: util.ParseProject(ctx),
 		})
 	case TypeRule:
-		return quota.RuleUsage(ctx, serviceID)
+		return quotasvc.RuleUsage(ctx, serviceID)
 	case TypeSchema:
-		return quota.SchemaUsage(ctx, serviceID)
+		return quotasvc.SchemaUsage(ctx, serviceID)
 	case TypeTag:
 		// always re-create the service old tags
 		return 0, nil
 	case TypeRole:
-		return quota.RoleUsage(ctx)
+		return quotasvc.RoleUsage(ctx)
 	case TypeAccount:
-		return quota.AccountUsage(ctx)
+		return quotasvc.AccountUsage(ctx)
 	default:
 		return 0, fmt.Errorf("not define quota type '%s'", res.QuotaType)
 	}
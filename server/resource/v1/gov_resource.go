@@ -18,12 +18,18 @@
 package v1
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	model "github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/service/gov"
 	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
 	"github.com/go-chassis/cari/discovery"
@@ -38,7 +44,11 @@ const (
 	KindKey        = ":kind"
 	ProjectKey     = ":project"
 	IDKey          = ":id"
+	RevisionKey    = ":revision"
 	DisplayKey     = "display"
+	DryRunKey      = "dryRun"
+
+	ContentTypeYAML = "application/x-yaml; charset=UTF-8"
 )
 
 //Create gov config
@@ -53,6 +63,9 @@ func (t *Governance) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, err := gov.Create(kind, project, body)
+	if gov.Applied(err) {
+		recordRevision(r, model.ActionCreate, kind, string(id), project)
+	}
 	if err != nil {
 		if _, ok := err.(*kie.ErrIllegalItem); ok {
 			log.Error("", err)
@@ -78,6 +91,9 @@ func (t *Governance) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	err = gov.Update(kind, id, project, body)
+	if gov.Applied(err) {
+		recordRevision(r, model.ActionUpdate, kind, id, project)
+	}
 	if err != nil {
 		if _, ok := err.(*kie.ErrIllegalItem); ok {
 			log.Error("", err)
@@ -139,8 +155,156 @@ func (t *Governance) Delete(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, nil, nil)
 }
 
+//ListRevisions return the revisions of the gov config
+func (t *Governance) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	revisions, err := gov.ListRevisions(r.Context(), query.Get(KindKey), query.Get(IDKey), query.Get(ProjectKey))
+	if err != nil {
+		processError(w, err, "list gov revisions err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, revisions)
+}
+
+//GetRevision return a revision of the gov config
+func (t *Governance) GetRevision(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	revision, err := strconv.ParseInt(query.Get(RevisionKey), 10, 64)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid revision")
+		return
+	}
+	result, err := gov.GetRevision(r.Context(), query.Get(KindKey), query.Get(IDKey), query.Get(ProjectKey), revision)
+	if err != nil {
+		processError(w, err, "get gov revision err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, result)
+}
+
+//Rollback update the gov config to a revision
+func (t *Governance) Rollback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	revision, err := strconv.ParseInt(query.Get(RevisionKey), 10, 64)
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid revision")
+		return
+	}
+	err = gov.Rollback(r.Context(), query.Get(KindKey), query.Get(IDKey), query.Get(ProjectKey), revision)
+	if err != nil {
+		if _, ok := err.(*kie.ErrIllegalItem); ok {
+			log.Error("", err)
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "rollback gov err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, nil)
+}
+
+//Preview return the services and the operations affected by the gov config
+func (t *Governance) Preview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	project := query.Get(ProjectKey)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		processError(w, err, "read body err")
+		return
+	}
+	p := &model.Policy{}
+	if err = json.Unmarshal(body, p); err != nil {
+		log.Error("", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	// the gov api is not under /v4, set the domain project for the datasource
+	domain := util.ParseDomain(r.Context())
+	if domain == "" {
+		domain = r.Header.Get("X-Domain-Name")
+	}
+	if domain == "" {
+		domain = "default"
+	}
+	ctx := util.SetDomainProject(r.Context(), domain, project)
+	result, err := gov.Preview(ctx, query.Get(KindKey), project, p)
+	if err != nil {
+		if errors.Is(err, gov.ErrInvalidMatches) {
+			log.Error("", err)
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "preview gov err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, result)
+}
+
+//Export return the gov configs as a YAML bundle
+func (t *Governance) Export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	b, err := gov.Export(query.Get(ProjectKey), query.Get(AppKey), query.Get(EnvironmentKey))
+	if err != nil {
+		processError(w, err, "export gov err")
+		return
+	}
+	w.Header().Set(rest.HeaderContentType, ContentTypeYAML)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(b); err != nil {
+		log.Error("write response failed", err)
+	}
+}
+
+//Apply reconcile the gov configs with a YAML bundle and return the plan
+func (t *Governance) Apply(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	project := query.Get(ProjectKey)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		processError(w, err, "read body err")
+		return
+	}
+	dryRun := query.Get(DryRunKey) == "true"
+	plan, err := gov.Apply(project, query.Get(AppKey), query.Get(EnvironmentKey), body, dryRun, func(step *model.Step) {
+		switch step.Action {
+		case model.ActionCreate, model.ActionUpdate:
+			recordRevision(r, step.Action, step.Kind, step.ID, project)
+		}
+	})
+	if err != nil {
+		var illegal *kie.ErrIllegalItem
+		if errors.Is(err, gov.ErrInvalidBundle) || errors.As(err, &illegal) {
+			log.Error("", err)
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		processError(w, err, "apply gov err")
+		return
+	}
+	rest.WriteResponse(w, r, nil, plan)
+}
+
+// recordRevision saves the revision after the change, the change is not
+// reverted if it fails
+func recordRevision(r *http.Request, action, kind, id, project string) {
+	err := gov.RecordRevision(r.Context(), action, kind, id, project)
+	if err != nil {
+		log.Error(fmt.Sprintf("record the revision of %s policy %s err", kind, id), err)
+	}
+}
+
 func processError(w http.ResponseWriter, err error, msg string) {
 	log.Error(msg, err)
+	if errors.Is(err, datasource.ErrPolicyNotExist) || errors.Is(err, datasource.ErrPolicyAlreadyExists) ||
+		errors.Is(err, datasource.ErrRevisionNotExist) {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	var de *gov.ErrDistribute
+	if errors.As(err, &de) {
+		rest.WriteError(w, discovery.ErrUnavailableBackend, err.Error())
+		return
+	}
 	rest.WriteError(w, discovery.ErrInternal, err.Error())
 }
 
@@ -149,10 +313,16 @@ func (t *Governance) URLPatterns() []rest.Route {
 		//servicecomb.marker.{name}
 		//servicecomb.rateLimiter.{name}
 		//....
+		{Method: http.MethodGet, Path: "/v1/:project/gov/export", Func: t.Export},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/apply", Func: t.Apply},
 		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey, Func: t.Create},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey, Func: t.ListOrDisPlay},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/preview", Func: t.Preview},
 		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Get},
 		{Method: http.MethodPut, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Put},
 		{Method: http.MethodDelete, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey, Func: t.Delete},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/revisions", Func: t.ListRevisions},
+		{Method: http.MethodGet, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/revisions/" + RevisionKey, Func: t.GetRevision},
+		{Method: http.MethodPost, Path: "/v1/:project/gov/" + KindKey + "/" + IDKey + "/revisions/" + RevisionKey + "/rollback", Func: t.Rollback},
 	}
 }
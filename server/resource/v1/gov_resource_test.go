@@ -21,21 +21,27 @@ package v1_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/config"
 	v1 "github.com/apache/servicecomb-service-center/server/resource/v1"
 	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-archaius"
 	"github.com/stretchr/testify/assert"
 
 	_ "github.com/apache/servicecomb-service-center/server/service/gov/mock"
+	_ "github.com/apache/servicecomb-service-center/test"
 )
 
 func init() {
@@ -70,5 +76,199 @@ func TestAuthResource_Login(t *testing.T) {
 		rest.GetRouter().ServeHTTP(w, r)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+	t.Run("update and rollback policy, should keep the revisions", func(t *testing.T) {
+		serve := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+			b, _ := json.Marshal(body)
+			r, _ := http.NewRequest(method, url, bytes.NewBuffer(b))
+			w := httptest.NewRecorder()
+			rest.GetRouter().ServeHTTP(w, r)
+			return w
+		}
+		policy := func(retryNext int) *gov.Policy {
+			return &gov.Policy{
+				GovernancePolicy: &gov.GovernancePolicy{Name: "revision", Selector: &gov.Selector{}},
+				Spec:             map[string]interface{}{"retryNext": retryNext},
+			}
+		}
+		w := serve(http.MethodPost, "/v1/default/gov/retry", policy(1))
+		assert.Equal(t, http.StatusOK, w.Code)
+		created := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), created))
+		url := "/v1/default/gov/retry/" + created.ID
+
+		w = serve(http.MethodPut, url, policy(2))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(http.MethodGet, url+"/revisions", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var revisions []*gov.Revision
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+		if assert.Equal(t, 2, len(revisions)) {
+			assert.Equal(t, gov.ActionCreate, revisions[0].Action)
+			assert.Equal(t, gov.ActionUpdate, revisions[1].Action)
+			assert.Equal(t, []*gov.Change{{Path: "spec.retryNext", Old: 1.0, New: 2.0}}, revisions[1].Diff)
+		}
 
+		w = serve(http.MethodGet, url+"/revisions/1", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		revision := &gov.Revision{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), revision))
+		assert.Equal(t, 1.0, revision.Policy.Spec.(map[string]interface{})["retryNext"])
+
+		w = serve(http.MethodGet, url+"/revisions/9", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve(http.MethodPost, url+"/revisions/1/rollback", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = serve(http.MethodGet, url, nil)
+		current := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), current))
+		assert.Equal(t, 1.0, current.Spec.(map[string]interface{})["retryNext"])
+
+		w = serve(http.MethodGet, url+"/revisions", nil)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisions))
+		if assert.Equal(t, 3, len(revisions)) {
+			assert.Equal(t, gov.ActionRollback, revisions[2].Action)
+		}
+	})
+	t.Run("rollback policy with a failed secondary, should keep the revision", func(t *testing.T) {
+		svc.InstallDistributor("broken", func(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+			return &brokenDistributor{name: opts.Name}, nil
+		})
+		old := config.App.Gov
+		config.App.Gov = &config.Gov{DistOptions: []config.DistributorOptions{
+			{Name: "mock", Type: "mock"},
+			{Name: "broken", Type: "broken"},
+		}}
+		assert.NoError(t, svc.Init())
+		defer func() {
+			config.App.Gov = old
+			assert.NoError(t, svc.Init())
+		}()
+
+		ctx := context.Background()
+		b, _ := json.Marshal(&gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{Name: "broken", Selector: &gov.Selector{}},
+			Spec:             map[string]interface{}{"retryNext": 1},
+		})
+		id, err := svc.Create("retry", "default", b)
+		assert.True(t, svc.Applied(err))
+		assert.NoError(t, svc.RecordRevision(ctx, gov.ActionCreate, "retry", string(id), "default"))
+
+		err = svc.Rollback(ctx, "retry", string(id), "default", 1)
+		var de *svc.ErrDistribute
+		assert.True(t, errors.As(err, &de))
+		assert.False(t, de.RolledBack)
+		revisions, err := svc.ListRevisions(ctx, "retry", string(id), "default")
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(revisions)) {
+			assert.Equal(t, gov.ActionRollback, revisions[1].Action)
+		}
+	})
+	t.Run("preview policy, should return the matched services and operations", func(t *testing.T) {
+		ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "preview", "default"))
+		register := func(name, env string) string {
+			resp, err := datasource.GetMetadataManager().RegisterService(ctx, &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "preview",
+					ServiceName: name,
+					Version:     "1.0.0",
+					Environment: env,
+					Schemas:     []string{"order"},
+				},
+			})
+			assert.NoError(t, err)
+			return resp.ServiceId
+		}
+		orderID := register("order", "production")
+		defer datasource.GetMetadataManager().UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: orderID, Force: true})
+		testID := register("order-test", "testing")
+		defer datasource.GetMetadataManager().UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: testID, Force: true})
+		_, err := datasource.GetMetadataManager().ModifySchema(ctx, &pb.ModifySchemaRequest{
+			ServiceId: orderID,
+			SchemaId:  "order",
+			Schema: `swagger: "2.0"
+basePath: /api
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+    post:
+      operationId: createOrder
+  /orders/{id}:
+    get:
+      operationId: getOrder
+`,
+		})
+		assert.NoError(t, err)
+
+		serve := func(url string, body interface{}) *httptest.ResponseRecorder {
+			b, _ := json.Marshal(body)
+			r, _ := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(b))
+			r.Header.Set("X-Domain-Name", "preview")
+			w := httptest.NewRecorder()
+			rest.GetRouter().ServeHTTP(w, r)
+			return w
+		}
+		group := &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{
+				Name:     "orders",
+				Selector: &gov.Selector{App: "preview", Environment: "production"},
+			},
+			Spec: map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{"apiPath": map[string]string{"exact": "/api/orders/1"}, "method": []string{"GET"}},
+				},
+			},
+		}
+		w := serve("/v1/default/gov/match-group/preview", group)
+		assert.Equal(t, http.StatusOK, w.Code)
+		preview := &gov.Preview{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), preview))
+		if assert.Equal(t, 1, len(preview.Services)) {
+			assert.Equal(t, orderID, preview.Services[0].ServiceID)
+			assert.Equal(t, []*gov.Operation{
+				{SchemaID: "order", Method: http.MethodGet, Path: "/api/orders/{id}", OperationID: "getOrder"},
+			}, preview.Services[0].Operations)
+		}
+
+		retry := &gov.Policy{GovernancePolicy: &gov.GovernancePolicy{Name: "orders", Selector: group.Selector}}
+		w = serve("/v1/default/gov/retry/preview", retry)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		group.Spec = map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{"apiPath": map[string]string{"prefix": "/api/orders"}},
+			},
+		}
+		b, _ := json.Marshal(group)
+		r, _ := http.NewRequest(http.MethodPost, "/v1/default/gov/match-group", bytes.NewBuffer(b))
+		w = httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = serve("/v1/default/gov/retry/preview", retry)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), preview))
+		if assert.Equal(t, 1, len(preview.Services)) {
+			assert.Equal(t, 3, len(preview.Services[0].Operations))
+		}
+	})
+}
+
+type brokenDistributor struct {
+	svc.ConfigDistributor
+	name string
+}
+
+func (d *brokenDistributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+func (d *brokenDistributor) Update(kind, id, project string, spec []byte) error {
+	return errors.New("broken")
+}
+func (d *brokenDistributor) List(kind, project, app, env string) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+func (d *brokenDistributor) Name() string {
+	return d.name
 }
@@ -19,17 +19,23 @@ package v4
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 	"github.com/go-chassis/cari/rbac"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/go-chassis/go-chassis/v2/server/restful"
 
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/apache/servicecomb-service-center/server/service/validator"
 )
@@ -43,12 +49,22 @@ type AuthResource struct {
 func (ar *AuthResource) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodPost, Path: "/v4/token", Func: ar.Login},
+		{Method: http.MethodDelete, Path: "/v4/token", Func: ar.Logout},
 		{Method: http.MethodPost, Path: "/v4/accounts", Func: ar.CreateAccount},
 		{Method: http.MethodGet, Path: "/v4/accounts", Func: ar.ListAccount},
 		{Method: http.MethodGet, Path: "/v4/accounts/:name", Func: ar.GetAccount},
 		{Method: http.MethodDelete, Path: "/v4/accounts/:name", Func: ar.DeleteAccount},
 		{Method: http.MethodPut, Path: "/v4/accounts/:name", Func: ar.UpdateAccount},
 		{Method: http.MethodPost, Path: "/v4/accounts/:name/password", Func: ar.ChangePassword},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/tokens", Func: ar.RevokeTokens},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/keys", Func: ar.CreateAPIKey},
+		{Method: http.MethodGet, Path: "/v4/accounts/:name/keys", Func: ar.ListAPIKeys},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/keys/:id/rotate", Func: ar.RotateAPIKey},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/keys/:id", Func: ar.RevokeAPIKey},
+		{Method: http.MethodGet, Path: "/v4/account-locks", Func: ar.ListLocks},
+		{Method: http.MethodDelete, Path: "/v4/account-locks/:name", Func: ar.Unlock},
+		{Method: http.MethodGet, Path: "/v4/self/permissions", Func: ar.SelfPermissions},
+		{Method: http.MethodGet, Path: "/v4/self/can-i", Func: ar.CanI},
 	}
 }
 
@@ -110,7 +126,16 @@ func (ar *AuthResource) UpdateAccount(w http.ResponseWriter, req *http.Request)
 }
 
 func (ar *AuthResource) ListAccount(w http.ResponseWriter, r *http.Request) {
-	as, n, err := rbacsvc.ListAccount(r.Context())
+	opts, err := datasource.ParseListOptions(r.URL.Query())
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	as, n, err := rbacsvc.ListAccount(r.Context(), opts...)
+	if errors.Is(err, datasource.ErrInvalidSortKey) {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
 	if err != nil {
 		log.Error(errorsEx.MsgGetAccountFailed, err)
 		rest.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetAccountFailed)
@@ -157,6 +182,129 @@ func (ar *AuthResource) ChangePassword(w http.ResponseWriter, req *http.Request)
 	rest.WriteSuccess(w, req)
 }
 
+func (ar *AuthResource) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	in := &apikey.CreateRequest{}
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, in); err != nil {
+			log.Error("json err", err)
+			rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+			return
+		}
+	}
+	key, err := rbacsvc.CreateAPIKey(r.Context(), r.URL.Query().Get(":name"), in)
+	if err != nil {
+		log.Error(errorsEx.MsgOperateAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, key)
+}
+
+func (ar *AuthResource) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := rbacsvc.ListAPIKeys(r.Context(), r.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error(errorsEx.MsgGetAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, &apikey.ListResponse{Total: int64(len(keys)), Keys: keys})
+}
+
+func (ar *AuthResource) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	in := &apikey.RotateRequest{}
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, in); err != nil {
+			log.Error("json err", err)
+			rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+			return
+		}
+	}
+	query := r.URL.Query()
+	key, err := rbacsvc.RotateAPIKey(r.Context(), query.Get(":name"), query.Get(":id"), in)
+	if err != nil {
+		log.Error(errorsEx.MsgOperateAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, key)
+}
+
+func (ar *AuthResource) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := rbacsvc.RevokeAPIKey(r.Context(), query.Get(":name"), query.Get(":id"))
+	if err != nil {
+		log.Error(errorsEx.MsgOperateAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) ListLocks(w http.ResponseWriter, r *http.Request) {
+	locks, err := rbacsvc.ListLocks(r.Context(), r.URL.Query().Get("account"))
+	if err != nil {
+		log.Error(errorsEx.MsgGetAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, locks)
+}
+
+func (ar *AuthResource) Unlock(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := rbacsvc.Unlock(r.Context(), query.Get(":name"), query.Get("ip"))
+	if err != nil {
+		log.Error(errorsEx.MsgOperateAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) SelfPermissions(w http.ResponseWriter, r *http.Request) {
+	perms, err := rbacsvc.SelfPermissions(r.Context(), r.URL.Query().Get("project"))
+	if err != nil {
+		log.Error(errorsEx.MsgGetRoleFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, perms)
+}
+
+func (ar *AuthResource) CanI(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	target := &auth.ResourceScope{Type: query.Get("resource"), Verb: query.Get("verb")}
+	if len(target.Type) == 0 || len(target.Verb) == 0 {
+		rest.WriteError(w, discovery.ErrInvalidParams, "resource and verb are required")
+		return
+	}
+	labels, err := rbacsvc.ParseLabels(query["labels"])
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	target.Labels = labels
+	d, err := rbacsvc.CanI(r.Context(), query.Get("project"), target)
+	if err != nil {
+		log.Error(errorsEx.MsgGetRoleFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, d)
+}
+
 func (ar *AuthResource) Login(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -180,6 +328,11 @@ func (ar *AuthResource) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	t, err := authr.Login(r.Context(), a.Name, a.Password,
 		authr.ExpireAfter(a.TokenExpirationTime))
+	if len(t) > 0 && errsvc.IsErrEqualCode(err, rbacsvc.ErrPasswordExpired) {
+		// the token is only for changing the expired password
+		writePasswordExpired(w, err.(*errsvc.Error), t)
+		return
+	}
 	if err != nil {
 		log.Error("not authorized", err)
 		writeErrsvcOrInternalErr(w, err)
@@ -188,10 +341,64 @@ func (ar *AuthResource) Login(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, nil, &rbac.Token{TokenStr: t})
 }
 
+// Logout revokes the token in the header, the API is in the white list, so
+// the token is verified here
+func (ar *AuthResource) Logout(w http.ResponseWriter, r *http.Request) {
+	v := r.Header.Get(restful.HeaderAuth)
+	if v == "" {
+		rest.WriteErrsvcError(w, rbac.NewError(rbac.ErrNoAuthHeader, ""))
+		return
+	}
+	s := strings.Split(v, " ")
+	if len(s) != 2 {
+		rest.WriteError(w, discovery.ErrInvalidParams, rbac.ErrInvalidHeader.Error())
+		return
+	}
+	err := rbacsvc.Logout(r.Context(), s[1])
+	if err != nil {
+		log.Error("logout failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) RevokeTokens(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get(":name")
+	if _, err := rbacsvc.GetAccount(r.Context(), name); err != nil {
+		log.Error(errorsEx.MsgGetAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	err := rbacsvc.RevokeAccountTokens(r.Context(), name)
+	if err != nil {
+		log.Error(errorsEx.MsgOperateAccountFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
 func MakeBanKey(name, ip string) string {
 	return name + "::" + ip
 }
 
+// passwordExpiredResponse is the error of the expired password with the
+// token to change it
+type passwordExpiredResponse struct {
+	*errsvc.Error
+	Token string `json:"token"`
+}
+
+func writePasswordExpired(w http.ResponseWriter, err *errsvc.Error, token string) {
+	w.Header().Set(rest.HeaderContentType, rest.ContentTypeJSON)
+	w.WriteHeader(err.StatusCode())
+	b, _ := json.Marshal(&passwordExpiredResponse{Error: err, Token: token})
+	if _, e := w.Write(b); e != nil {
+		log.Error("write response failed", e)
+	}
+}
+
 func writeErrsvcOrInternalErr(w http.ResponseWriter, err error) {
 	e, ok := err.(*errsvc.Error)
 	if ok {
@@ -29,6 +29,7 @@ import (
 
 	rbacmodel "github.com/go-chassis/cari/rbac"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/config"
 	v4 "github.com/apache/servicecomb-service-center/server/resource/v4"
@@ -367,6 +368,35 @@ func TestAuthResource_Login2(t *testing.T) {
 	})
 }
 
+func TestAuthResource_LoginExpiredPassword(t *testing.T) {
+	ctx := context.TODO()
+	name := "expired_account"
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, &rbacmodel.Account{Name: name, Password: pwd, Roles: []string{"developer"}}))
+	defer rbacsvc.DeleteAccount(ctx, name)
+	archaius.Set("rbac.passwordPolicy.maxAge", "1h")
+	defer archaius.Delete("rbac.passwordPolicy.maxAge")
+	err := datasource.GetPasswordHistoryManager().PutPasswordHistory(ctx, &datasource.PasswordHistory{
+		Account:    name,
+		ChangeTime: time.Now().Add(-2 * time.Hour).Unix(),
+	})
+	assert.NoError(t, err)
+
+	t.Run("login with an expired password, should return the error and the token", func(t *testing.T) {
+		b, _ := json.Marshal(&rbacmodel.Account{Name: name, Password: pwd})
+		r, _ := http.NewRequest(http.MethodPost, "/v4/token", bytes.NewBuffer(b))
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		resp := &struct {
+			Code  string `json:"errorCode"`
+			Token string `json:"token"`
+		}{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+		assert.Equal(t, "403250", resp.Code)
+		assert.NotEmpty(t, resp.Token)
+	})
+}
+
 func BenchmarkAuthResource_LoginP(b *testing.B) {
 	body, _ := json.Marshal(&rbacmodel.Account{Name: "root", Password: pwd})
 	b.RunParallel(func(pb *testing.PB) {
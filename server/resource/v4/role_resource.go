@@ -19,12 +19,13 @@ package v4
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/go-chassis/cari/discovery"
-	"github.com/go-chassis/cari/rbac"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
@@ -49,22 +50,40 @@ func (rr *RoleResource) URLPatterns() []rest.Route {
 
 //ListRoles list all roles and there's permissions
 func (rr *RoleResource) ListRoles(w http.ResponseWriter, req *http.Request) {
-	rs, num, err := rbacsvc.ListRole(req.Context())
+	opts, err := datasource.ParseListOptions(req.URL.Query())
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	rs, num, err := rbacsvc.ListRole(req.Context(), opts...)
+	if errors.Is(err, datasource.ErrInvalidSortKey) {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if err != nil {
+		log.Error(errorsEx.MsgGetRoleFailed, err)
+		rest.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetRoleFailed)
+		return
+	}
+	parents, err := rbacsvc.ListRoleParents(req.Context())
 	if err != nil {
 		log.Error(errorsEx.MsgGetRoleFailed, err)
 		rest.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetRoleFailed)
 		return
 	}
-	resp := &rbac.RoleResponse{
+	resp := &rbacsvc.RoleResponse{
 		Total: num,
-		Roles: rs,
+		Roles: make([]*rbacsvc.Role, 0, len(rs)),
+	}
+	for _, r := range rs {
+		resp.Roles = append(resp.Roles, &rbacsvc.Role{Role: *r, Parents: parents[r.Name]})
 	}
 	rest.WriteResponse(w, req, nil, resp)
 }
 
 //roleParse parse the role info from the request body
-func (rr *RoleResource) roleParse(body []byte) (*rbac.Role, error) {
-	role := &rbac.Role{}
+func (rr *RoleResource) roleParse(body []byte) (*rbacsvc.Role, error) {
+	role := &rbacsvc.Role{}
 	err := json.Unmarshal(body, role)
 	if err != nil {
 		log.Error("json err", err)
@@ -88,7 +107,7 @@ func (rr *RoleResource) CreateRole(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = rbacsvc.CreateRole(req.Context(), role)
+	err = rbacsvc.CreateRole(req.Context(), &role.Role, role.Parents...)
 	if err != nil {
 		log.Error(errorsEx.MsgOperateRoleFailed, err)
 		writeErrsvcOrInternalErr(w, err)
@@ -111,7 +130,7 @@ func (rr *RoleResource) UpdateRole(w http.ResponseWriter, req *http.Request) {
 		rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
 		return
 	}
-	err = rbacsvc.EditRole(req.Context(), name, role)
+	err = rbacsvc.EditRole(req.Context(), name, &role.Role, role.Parents)
 	if err != nil {
 		log.Error(errorsEx.MsgOperateRoleFailed, err)
 		writeErrsvcOrInternalErr(w, err)
@@ -130,7 +149,13 @@ func (rr *RoleResource) GetRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rest.WriteResponse(w, r, nil, resp)
+	parents, err := rbacsvc.GetRoleParents(r.Context(), resp.Name)
+	if err != nil {
+		log.Error(errorsEx.MsgGetRoleFailed, err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, &rbacsvc.Role{Role: *resp, Parents: parents})
 }
 
 //DeleteRole delete the role info by role name
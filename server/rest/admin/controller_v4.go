@@ -18,9 +18,21 @@
 package admin
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/go-chassis/cari/discovery"
 
 	"strings"
 
@@ -38,6 +50,15 @@ func (ctrl *ControllerV4) URLPatterns() []rest.Route {
 		{Method: http.MethodDelete, Path: "/v4/:project/admin/alarms", Func: ctrl.ClearAlarm},
 		{Method: http.MethodGet, Path: "/v4/:project/admin/dump", Func: ctrl.Dump},
 		{Method: http.MethodGet, Path: "/v4/:project/admin/clusters", Func: ctrl.Clusters},
+		{Method: http.MethodPost, Path: "/v4/:project/admin/migrate", Func: ctrl.Migrate},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/backup", Func: ctrl.Backup},
+		{Method: http.MethodPost, Path: "/v4/:project/admin/restore", Func: ctrl.Restore},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/quotas", Func: ctrl.GetQuota},
+		{Method: http.MethodPut, Path: "/v4/:project/admin/quotas", Func: ctrl.PutQuota},
+		{Method: http.MethodDelete, Path: "/v4/:project/admin/quotas", Func: ctrl.ResetQuota},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/quotas/usage", Func: ctrl.QuotaUsage},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/audit-logs", Func: ctrl.AuditLogs},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/gov/drift", Func: ctrl.GovDrift},
 	}
 }
 
@@ -75,3 +96,157 @@ func (ctrl *ControllerV4) ClearAlarm(w http.ResponseWriter, r *http.Request) {
 	resp, _ := AdminServiceAPI.ClearAlarm(ctx, request)
 	rest.WriteResponse(w, r, resp.Response, nil)
 }
+
+func (ctrl *ControllerV4) Migrate(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &migrate.Request{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("invalid json", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.Migrate(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) Backup(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &backup.Request{
+		Domain:        query.Get("domain"),
+		Project:       query.Get("project"),
+		WithInstances: query.Get("instances") == "true",
+	}
+	resp, _ := AdminServiceAPI.Backup(r.Context(), request)
+	if resp.Archive == nil {
+		rest.WriteResponse(w, r, resp.Response, nil)
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := backup.Encode(buf, resp.Archive); err != nil {
+		log.Error("encode archive failed", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	w.Header().Set(rest.HeaderContentType, backup.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"backup-%s.json.gz\"", resp.Archive.Timestamp))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Error("write archive failed", err)
+	}
+}
+
+func (ctrl *ControllerV4) Restore(w http.ResponseWriter, r *http.Request) {
+	archive, err := backup.Decode(r.Body)
+	if err != nil {
+		log.Error("invalid archive", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	request := &backup.RestoreRequest{
+		Domain:  query.Get("domain"),
+		Project: query.Get("project"),
+		Mode:    query.Get("mode"),
+		Archive: archive,
+	}
+	resp, _ := AdminServiceAPI.Restore(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) GetQuota(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &quota.Request{
+		Domain:  query.Get("domain"),
+		Project: query.Get("project"),
+	}
+	resp, _ := AdminServiceAPI.GetQuota(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) PutQuota(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &quota.Quota{}
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Error("invalid json", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.PutQuota(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) ResetQuota(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &quota.Request{
+		Domain:  query.Get("domain"),
+		Project: query.Get("project"),
+	}
+	resp, _ := AdminServiceAPI.ResetQuota(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, nil)
+}
+
+func (ctrl *ControllerV4) QuotaUsage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &quota.UsageRequest{
+		Domain:    query.Get("domain"),
+		Project:   query.Get("project"),
+		ServiceID: query.Get("serviceId"),
+	}
+	resp, _ := AdminServiceAPI.QuotaUsage(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) AuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &audit.Request{
+		Actor:    query.Get("actor"),
+		Resource: query.Get("resource"),
+	}
+	var err error
+	if request.Start, err = parseAuditTime(query.Get("start")); err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid start: "+err.Error())
+		return
+	}
+	if request.End, err = parseAuditTime(query.Get("end")); err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, "invalid end: "+err.Error())
+		return
+	}
+	if s := query.Get("limit"); len(s) > 0 {
+		request.Limit, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			rest.WriteError(w, discovery.ErrInvalidParams, "invalid limit: "+err.Error())
+			return
+		}
+	}
+	resp, _ := AdminServiceAPI.AuditLogs(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) GovDrift(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GovDrift(r.Context(), r.URL.Query().Get(":project"))
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+// parseAuditTime parses the RFC3339 time to unix nano time, empty means 0
+func parseAuditTime(s string) (int64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixNano(), nil
+}
@@ -19,12 +19,25 @@ package admin
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/alarm"
+	quotaplugin "github.com/apache/servicecomb-service-center/server/plugin/quota"
+	backupsvc "github.com/apache/servicecomb-service-center/server/service/backup"
+	govsvc "github.com/apache/servicecomb-service-center/server/service/gov"
+	migratesvc "github.com/apache/servicecomb-service-center/server/service/migrate"
+	quotasvc "github.com/apache/servicecomb-service-center/server/service/quota"
 	"github.com/apache/servicecomb-service-center/version"
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-archaius"
@@ -105,3 +118,288 @@ func (service *Service) ClearAlarm(ctx context.Context, in *dump.ClearAlarmReque
 	log.Infof("service center alarms are cleared")
 	return &dump.ClearAlarmResponse{}, nil
 }
+
+func (service *Service) Migrate(ctx context.Context, in *migrate.Request) (*migrate.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &migrate.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	report, err := migratesvc.Migrate(ctx, in)
+	if err != nil {
+		log.Errorf(err, "migrate to %s failed", in.Target)
+		code := discovery.ErrInternal
+		if err == migratesvc.ErrEmptyTarget || err == migratesvc.ErrSameStorage {
+			code = discovery.ErrInvalidParams
+		}
+		return &migrate.Response{
+			Response: discovery.CreateResponse(code, err.Error()),
+			Report:   report,
+		}, nil
+	}
+	return &migrate.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Migrate successfully"),
+		Report:   report,
+	}, nil
+}
+
+func (service *Service) Backup(ctx context.Context, in *backup.Request) (*backup.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &backup.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	ctx, err := targetContext(ctx, in.Domain, in.Project)
+	if err != nil {
+		return &backup.Response{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	archive, err := backupsvc.Backup(ctx, in)
+	if err != nil {
+		log.Error("backup failed", err)
+		return &backup.Response{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	return &backup.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Backup successfully"),
+		Archive:  archive,
+	}, nil
+}
+
+func (service *Service) Restore(ctx context.Context, in *backup.RestoreRequest) (*backup.RestoreResponse, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &backup.RestoreResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	ctx, err := targetContext(ctx, in.Domain, in.Project)
+	if err != nil {
+		return &backup.RestoreResponse{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	report, err := backupsvc.Restore(ctx, in)
+	if err != nil {
+		log.Errorf(err, "restore in %s mode failed", in.Mode)
+		code, message := discovery.ErrInternal, err.Error()
+		switch err {
+		case backupsvc.ErrInvalidMode, backupsvc.ErrEmptyArchive:
+			code = discovery.ErrInvalidParams
+		case backupsvc.ErrConflict:
+			// the error response has no body, so carry the conflicts in message
+			code, message = discovery.ErrInvalidParams, conflictsMessage(err, report)
+		}
+		return &backup.RestoreResponse{
+			Response: discovery.CreateResponse(code, message),
+			Report:   report,
+		}, nil
+	}
+	return &backup.RestoreResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Restore successfully"),
+		Report:   report,
+	}, nil
+}
+
+// targetContext returns the context of the target domain/project, ctx is
+// returned if domain is empty
+func targetContext(ctx context.Context, domain, project string) (context.Context, error) {
+	if len(domain) == 0 {
+		return ctx, nil
+	}
+	if len(project) == 0 {
+		return nil, errors.New("project is required")
+	}
+	return util.SetDomainProject(util.CloneContext(ctx), domain, project), nil
+}
+
+func conflictsMessage(err error, report *backup.Report) string {
+	const max = 20
+	keys := make([]string, 0, max)
+	for _, c := range report.Conflicts {
+		if len(keys) == max {
+			keys = append(keys, fmt.Sprintf("and %d more", len(report.Conflicts)-max))
+			break
+		}
+		keys = append(keys, c.Kind+" "+c.Key)
+	}
+	return fmt.Sprintf("%s: %s", err.Error(), strings.Join(keys, ", "))
+}
+
+// GetQuota returns the override and the effective limits of the domain or
+// the project, the requester's domain/project is used if domain is empty
+func (service *Service) GetQuota(ctx context.Context, in *quota.Request) (*quota.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &quota.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	domain, project := in.Domain, in.Project
+	if len(domain) == 0 {
+		domain, project = util.ParseDomain(ctx), util.ParseProject(ctx)
+	}
+	q, err := quotasvc.GetQuota(ctx, domain, project)
+	if err != nil && !errors.Is(err, datasource.ErrQuotaNotExist) {
+		log.Error(fmt.Sprintf("get quota of %s/%s failed", domain, project), err)
+		return &quota.Response{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	limits, err := quotaplugin.GetLimits(util.SetDomainProject(util.CloneContext(ctx), domain, project))
+	if err != nil {
+		log.Error(fmt.Sprintf("get limits of %s/%s failed", domain, project), err)
+		return &quota.Response{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	return &quota.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Get quota successfully"),
+		Quota:    q,
+		Limits:   limits,
+	}, nil
+}
+
+// PutQuota replaces the override of the domain or the project
+func (service *Service) PutQuota(ctx context.Context, in *quota.Quota) (*quota.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &quota.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	err := quotasvc.PutQuota(ctx, in)
+	if err != nil {
+		return &quota.Response{
+			Response: discovery.CreateResponse(quotaErrorCode(err), err.Error()),
+		}, nil
+	}
+	log.Info(fmt.Sprintf("quota of %s/%s is set to %v", in.Domain, in.Project, in.Limits))
+	return &quota.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Put quota successfully"),
+		Quota:    in,
+	}, nil
+}
+
+// ResetQuota removes the override of the domain or the project
+func (service *Service) ResetQuota(ctx context.Context, in *quota.Request) (*quota.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &quota.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	err := quotasvc.DeleteQuota(ctx, in.Domain, in.Project)
+	if err != nil {
+		return &quota.Response{
+			Response: discovery.CreateResponse(quotaErrorCode(err), err.Error()),
+		}, nil
+	}
+	log.Info(fmt.Sprintf("quota of %s/%s is reset", in.Domain, in.Project))
+	return &quota.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Reset quota successfully"),
+	}, nil
+}
+
+// QuotaUsage returns the usages against the limits of the project, the
+// requester's domain/project is used if domain is empty
+func (service *Service) QuotaUsage(ctx context.Context, in *quota.UsageRequest) (*quota.UsageResponse, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &quota.UsageResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+
+	domain, project := in.Domain, in.Project
+	if len(domain) == 0 {
+		domain, project = util.ParseDomain(ctx), util.ParseProject(ctx)
+	}
+	if len(project) == 0 {
+		return &quota.UsageResponse{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, "project is required"),
+		}, nil
+	}
+	usages, err := quotaplugin.GetUsages(util.SetDomainProject(util.CloneContext(ctx), domain, project), in.ServiceID)
+	if err != nil {
+		log.Error(fmt.Sprintf("get quota usages of %s/%s failed", domain, project), err)
+		return &quota.UsageResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	return &quota.UsageResponse{
+		Response:  discovery.CreateResponse(discovery.ResponseSuccess, "Get quota usages successfully"),
+		Domain:    domain,
+		Project:   project,
+		ServiceID: in.ServiceID,
+		Usages:    usages,
+	}, nil
+}
+
+func quotaErrorCode(err error) int32 {
+	if errors.Is(err, quotasvc.ErrEmptyDomain) || errors.Is(err, quotasvc.ErrUnknownResource) ||
+		errors.Is(err, quotasvc.ErrNegativeLimit) {
+		return discovery.ErrInvalidParams
+	}
+	log.Error("operate quota failed", err)
+	return discovery.ErrInternal
+}
+
+// AuditLogs returns the latest audit records matching the request
+func (service *Service) AuditLogs(ctx context.Context, in *audit.Request) (*audit.Response, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &audit.Response{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if in.Start > 0 && in.End > 0 && in.Start >= in.End {
+		return &audit.Response{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, "start must be earlier than end"),
+		}, nil
+	}
+	if in.Limit < 0 || in.Limit > audit.MaxLimit {
+		return &audit.Response{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams,
+				fmt.Sprintf("limit must be in [1, %d]", audit.MaxLimit)),
+		}, nil
+	}
+	if in.Limit == 0 {
+		in.Limit = audit.DefaultLimit
+	}
+	records, total, err := datasource.GetAuditLogManager().ListAuditLog(ctx, in)
+	if err != nil {
+		log.Error("list audit records failed", err)
+		return &audit.Response{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	return &audit.Response{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "List audit records successfully"),
+		Total:    total,
+		Records:  records,
+	}, nil
+}
+
+// GovDrift compares the governance policies of project in every config
+// distributor with the primary one
+func (service *Service) GovDrift(ctx context.Context, project string) (*gov.DriftResponse, error) {
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return &gov.DriftResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	report, err := govsvc.Drift(project)
+	if err != nil {
+		log.Error("compare the governance policies failed", err)
+		return &gov.DriftResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, nil
+	}
+	return &gov.DriftResponse{
+		Response:    discovery.CreateResponse(discovery.ResponseSuccess, "Compare the governance policies successfully"),
+		DriftReport: report,
+	}, nil
+}
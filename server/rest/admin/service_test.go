@@ -20,8 +20,14 @@ package admin_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/audit"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/rest/admin"
 	_ "github.com/apache/servicecomb-service-center/test"
@@ -46,6 +52,180 @@ func TestAdminService_Dump(t *testing.T) {
 	assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
 }
 
+func TestAdminService_Migrate(t *testing.T) {
+	t.Run("migrate by a non-admin domain, should be forbidden", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Migrate(
+			util.SetDomainProject(context.Background(), "x", "x"),
+			&migrate.Request{Target: "mongo", DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
+	})
+
+	t.Run("migrate without target, should be failed", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Migrate(getContext(), &migrate.Request{DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+	})
+}
+
+func TestAdminService_Backup(t *testing.T) {
+	t.Run("backup by a non-admin domain, should be forbidden", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Backup(
+			util.SetDomainProject(context.Background(), "x", "x"), &backup.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
+	})
+
+	t.Run("backup by the admin domain, should be successful", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Backup(getContext(), &backup.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+		assert.Equal(t, backup.ArchiveVersion, resp.Archive.Version)
+	})
+
+	t.Run("backup another domain project, should export the target", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Backup(getContext(), &backup.Request{Domain: "x", Project: "y"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+		assert.Equal(t, "x", resp.Archive.Domain)
+		assert.Equal(t, "y", resp.Archive.Project)
+		assert.Empty(t, resp.Archive.Roles)
+	})
+
+	t.Run("backup another domain without project, should be failed", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Backup(getContext(), &backup.Request{Domain: "x"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+	})
+
+	t.Run("restore in invalid mode, should be failed", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.Restore(getContext(), &backup.RestoreRequest{
+			Mode:    "x",
+			Archive: &backup.Archive{Version: backup.ArchiveVersion},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+	})
+}
+
+func TestAdminService_Quota(t *testing.T) {
+	t.Run("put quota by a non-admin domain, should be forbidden", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.PutQuota(
+			util.SetDomainProject(context.Background(), "x", "x"), &quota.Quota{Domain: "x"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
+	})
+
+	t.Run("put invalid quota, should be failed", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.PutQuota(getContext(), &quota.Quota{
+			Domain: "admin_quota", Limits: map[string]int64{"x": 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+	})
+
+	t.Run("put, get, usage and reset quota, should be successful", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.PutQuota(getContext(), &quota.Quota{
+			Domain: "admin_quota", Project: "admin_quota", Limits: map[string]int64{quota.ResourceService: 5}})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+
+		resp, err = admin.AdminServiceAPI.GetQuota(getContext(), &quota.Request{Domain: "admin_quota", Project: "admin_quota"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+		assert.Equal(t, int64(5), resp.Quota.Limits[quota.ResourceService])
+		assert.Equal(t, len(quota.Resources), len(resp.Limits))
+		assert.Equal(t, &quota.Limit{Resource: quota.ResourceService, Limit: 5, Scope: quota.ScopeProject}, resp.Limits[0])
+
+		usageResp, err := admin.AdminServiceAPI.QuotaUsage(getContext(), &quota.UsageRequest{Domain: "admin_quota", Project: "admin_quota"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, usageResp.Response.GetCode())
+		assert.Equal(t, 4, len(usageResp.Usages))
+		assert.Equal(t, quota.ResourceService, usageResp.Usages[0].Resource)
+		assert.Equal(t, int64(5), usageResp.Usages[0].Limit.Limit)
+		assert.Equal(t, int64(0), usageResp.Usages[0].Used)
+
+		resp, err = admin.AdminServiceAPI.ResetQuota(getContext(), &quota.Request{Domain: "admin_quota", Project: "admin_quota"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+
+		resp, err = admin.AdminServiceAPI.GetQuota(getContext(), &quota.Request{Domain: "admin_quota", Project: "admin_quota"})
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Quota)
+		assert.Equal(t, quota.ScopeDefault, resp.Limits[0].Scope)
+	})
+}
+
+func TestAdminService_AuditLogs(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	records := []*audit.Record{
+		{ID: "audit1", Timestamp: base, Actor: "admin_audit", Method: "POST", Resource: "service"},
+		{ID: "audit2", Timestamp: base + 1, Actor: "admin_audit", Method: "DELETE", Resource: "service",
+			ResourceIDs: map[string]string{"serviceId": "admin_audit_service"}},
+		{ID: "audit3", Timestamp: base + 2, Actor: "admin_audit", Method: "PUT", Resource: "account"},
+		{ID: "audit4", Timestamp: base + 3, Actor: "admin_audit_other", Method: "PUT", Resource: "account"},
+	}
+	for _, r := range records {
+		assert.NoError(t, datasource.GetAuditLogManager().AddAuditLog(getContext(), r))
+	}
+
+	t.Run("list by a non-admin domain, should be forbidden", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.AuditLogs(
+			util.SetDomainProject(context.Background(), "x", "x"), &audit.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
+	})
+	t.Run("list with invalid params, should be failed", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{Start: base + 1, End: base})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+
+		resp, err = admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{Limit: audit.MaxLimit + 1})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, resp.Response.GetCode())
+	})
+	t.Run("list by actor and time range, should return the latest first", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{
+			Start: base, End: base + 4, Actor: "admin_audit"})
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+		assert.Equal(t, int64(3), resp.Total)
+		assert.Equal(t, "audit3", resp.Records[0].ID)
+
+		resp, err = admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{
+			Start: base, End: base + 2, Actor: "admin_audit", Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), resp.Total)
+		assert.Equal(t, 1, len(resp.Records))
+		assert.Equal(t, "audit2", resp.Records[0].ID)
+	})
+	t.Run("list by resource, should match the type or the id", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{
+			Start: base, End: base + 4, Resource: "account"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Total)
+
+		resp, err = admin.AdminServiceAPI.AuditLogs(getContext(), &audit.Request{
+			Start: base, End: base + 4, Resource: "admin_audit_service"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), resp.Total)
+		assert.Equal(t, "audit2", resp.Records[0].ID)
+	})
+}
+
 func getContext() context.Context {
 	return util.WithNoCache(util.SetDomainProject(context.Background(), "default", "default"))
 }
+
+func TestAdminService_GovDrift(t *testing.T) {
+	t.Run("compare by a non-admin domain, should be forbidden", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.GovDrift(util.SetDomainProject(context.Background(), "x", "x"), "default")
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ErrForbidden, resp.Response.GetCode())
+	})
+	t.Run("compare without distributors, should return an empty report", func(t *testing.T) {
+		resp, err := admin.AdminServiceAPI.GovDrift(getContext(), "default")
+		assert.NoError(t, err)
+		assert.Equal(t, discovery.ResponseSuccess, resp.Response.GetCode())
+		assert.Empty(t, resp.Drifts)
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -216,18 +217,24 @@ func (s *MicroServiceInstanceService) GetInstances(w http.ResponseWriter, r *htt
 	if len(keys) > 0 {
 		ids = strings.Split(keys, ",")
 	}
+	opts, err := datasource.ParseListOptions(query)
+	if err != nil {
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
 	request := &pb.GetInstancesRequest{
 		ConsumerServiceId: r.Header.Get("X-ConsumerId"),
 		ProviderServiceId: query.Get(":serviceId"),
 		Tags:              ids,
 	}
-	resp, _ := discosvc.GetInstances(r.Context(), request)
+	resp, _ := discosvc.GetInstances(r.Context(), request, opts...)
 	respInternal := resp.Response
 	resp.Response = nil
 
 	iv, _ := r.Context().Value(util.CtxRequestRevision).(string)
 	ov, _ := r.Context().Value(util.CtxResponseRevision).(string)
 	w.Header().Set(util.HeaderRev, ov)
+	setTotalHeader(w, r.Context())
 	if len(iv) > 0 && iv == ov {
 		w.WriteHeader(http.StatusNotModified)
 		return
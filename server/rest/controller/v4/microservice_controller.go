@@ -18,19 +18,30 @@
 package v4
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
 var trueOrFalse = map[string]bool{"true": true, "false": false, "1": true, "0": false}
 
+// setTotalHeader writes the total count before paging to the header
+func setTotalHeader(w http.ResponseWriter, ctx context.Context) {
+	if total, ok := util.ResponseTotal(ctx); ok {
+		w.Header().Set(util.HeaderTotal, strconv.FormatInt(total, 10))
+	}
+}
+
 type MicroServiceService struct {
 	//
 }
@@ -120,13 +131,19 @@ func (s *MicroServiceService) Unregister(w http.ResponseWriter, r *http.Request)
 }
 
 func (s *MicroServiceService) GetServices(w http.ResponseWriter, r *http.Request) {
+	opts, err := datasource.ParseListOptions(r.URL.Query())
+	if err != nil {
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
 	request := &pb.GetServicesRequest{}
-	resp, err := core.ServiceAPI.GetServices(r.Context(), request)
+	resp, err := discosvc.ListServices(r.Context(), request, opts...)
 	if err != nil {
 		log.Errorf(err, "get services failed")
 		rest.WriteError(w, pb.ErrInternal, err.Error())
 		return
 	}
+	setTotalHeader(w, r.Context())
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
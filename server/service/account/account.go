@@ -35,3 +35,23 @@ func IsBanned(ctx context.Context, key string) (bool, error) {
 func Ban(ctx context.Context, key string) error {
 	return datasource.GetAccountLockManager().Ban(ctx, key)
 }
+
+// ListBanned returns the locks not released yet
+func ListBanned(ctx context.Context) ([]*datasource.AccountLock, error) {
+	locks, _, err := datasource.GetAccountLockManager().ListLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	banned := make([]*datasource.AccountLock, 0, len(locks))
+	for _, lock := range locks {
+		if lock.Status == datasource.StatusBanned && lock.ReleaseAt >= now {
+			banned = append(banned, lock)
+		}
+	}
+	return banned, nil
+}
+
+func Unban(ctx context.Context, key string) error {
+	return datasource.GetAccountLockManager().DeleteLock(ctx, key)
+}
@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package backup exports the registry data of a domain/project to an
+// archive and restores the archive to the running datasource
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// Backup exports the resources of the domain/project in ctx, the data is
+// read from the backend instead of the cache
+func Backup(ctx context.Context, in *backup.Request) (*backup.Archive, error) {
+	ctx = util.WithNoCache(ctx)
+	a := &backup.Archive{
+		Version:   backup.ArchiveVersion,
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Domain:    util.ParseDomain(ctx),
+		Project:   util.ParseProject(ctx),
+	}
+
+	services, err := getServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var instances map[string][]*discovery.MicroServiceInstance
+	if in.WithInstances {
+		instances, err = getInstances(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, service := range services {
+		s, err := backupService(ctx, service)
+		if err != nil {
+			return nil, fmt.Errorf("backup service %s failed, %s", service.ServiceId, err.Error())
+		}
+		s.Instances = instances[service.ServiceId]
+		a.Services = append(a.Services, s)
+
+		providers, err := getProviders(ctx, service.ServiceId)
+		if err != nil {
+			return nil, fmt.Errorf("backup dependency of %s failed, %s", service.ServiceId, err.Error())
+		}
+		if len(providers) > 0 {
+			a.Dependencies = append(a.Dependencies, &discovery.ConsumerDependency{
+				Consumer:  toServiceKey(service),
+				Providers: providers,
+			})
+		}
+	}
+
+	// the roles and accounts are global, export them with the default
+	// domain/project only
+	if !datasource.IsDefaultDomainProject(util.ParseDomainProject(ctx)) {
+		return a, nil
+	}
+	a.Roles, err = backupRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.RoleParents, err = datasource.GetRoleManager().ListRoleParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.Accounts, err = backupAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func backupService(ctx context.Context, service *discovery.MicroService) (*backup.Service, error) {
+	s := &backup.Service{Service: service}
+	var err error
+	s.Schemas, err = getSchemas(ctx, service.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	s.Tags, err = getTags(ctx, service.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	s.Rules, err = getRules(ctx, service.ServiceId)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func backupRoles(ctx context.Context) ([]*rbac.Role, error) {
+	roles, _, err := datasource.GetRoleManager().ListRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func backupAccounts(ctx context.Context) ([]*rbac.Account, error) {
+	list, _, err := datasource.GetAccountManager().ListAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*rbac.Account, 0, len(list))
+	for _, a := range list {
+		// the list result does not contain the password
+		account, err := datasource.GetAccountManager().GetAccount(ctx, a.Name)
+		if errors.Is(err, datasource.ErrAccountNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup_test
+
+import (
+	_ "github.com/apache/servicecomb-service-center/test"
+
+	"bytes"
+	"context"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	backupsvc "github.com/apache/servicecomb-service-center/server/service/backup"
+	"github.com/apache/servicecomb-service-center/server/service/disco"
+)
+
+func registerService(t *testing.T, ctx context.Context, name string) string {
+	resp, err := disco.RegisterService(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       "backup",
+			ServiceName: name,
+			Version:     "1.0.0",
+			Schemas:     []string{"schema1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	return resp.ServiceId
+}
+
+func TestBackup(t *testing.T) {
+	ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "backup", "backup"))
+	providerID := registerService(t, ctx, "backup_provider")
+	defer disco.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: providerID, Force: true})
+	consumerID := registerService(t, ctx, "backup_consumer")
+	defer disco.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: consumerID, Force: true})
+
+	_, err := datasource.GetMetadataManager().ModifySchema(ctx, &pb.ModifySchemaRequest{
+		ServiceId: providerID,
+		SchemaId:  "schema1",
+		Schema:    "schema1",
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetMetadataManager().AddTags(ctx, &pb.AddServiceTagsRequest{
+		ServiceId: providerID,
+		Tags:      map[string]string{"a": "b"},
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetMetadataManager().AddRule(ctx, &pb.AddServiceRulesRequest{
+		ServiceId: providerID,
+		Rules: []*pb.AddOrUpdateServiceRule{
+			{RuleType: "BLACK", Attribute: "ServiceName", Pattern: "test"},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetMetadataManager().RegisterInstance(ctx, &pb.RegisterInstanceRequest{
+		Instance: &pb.MicroServiceInstance{
+			ServiceId: providerID,
+			HostName:  "backup",
+			Endpoints: []string{"rest://127.0.0.1:8080"},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetDependencyManager().AddOrUpdateDependencies(ctx, []*pb.ConsumerDependency{
+		{
+			Consumer: &pb.MicroServiceKey{AppId: "backup", ServiceName: "backup_consumer", Version: "1.0.0"},
+			Providers: []*pb.MicroServiceKey{
+				{AppId: "backup", ServiceName: "backup_provider", Version: "1.0.0"},
+			},
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, datasource.GetDependencyManager().DependencyHandle(ctx))
+
+	var archive *backup.Archive
+	t.Run("backup without instances, should contain the resources", func(t *testing.T) {
+		a, err := backupsvc.Backup(ctx, &backup.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, backup.ArchiveVersion, a.Version)
+		assert.Equal(t, "backup", a.Domain)
+		assert.Empty(t, a.Accounts)
+		assert.Empty(t, a.Roles)
+
+		var provider *backup.Service
+		for _, s := range a.Services {
+			assert.Empty(t, s.Instances)
+			if s.Service.ServiceId == providerID {
+				provider = s
+			}
+		}
+		if assert.NotNil(t, provider) {
+			assert.Equal(t, 1, len(provider.Schemas))
+			assert.Equal(t, map[string]string{"a": "b"}, provider.Tags)
+			assert.Equal(t, 1, len(provider.Rules))
+		}
+		if assert.Equal(t, 1, len(a.Dependencies)) {
+			assert.Equal(t, "backup_consumer", a.Dependencies[0].Consumer.ServiceName)
+			assert.Equal(t, "backup_provider", a.Dependencies[0].Providers[0].ServiceName)
+		}
+	})
+
+	t.Run("backup with instances, should contain the instances", func(t *testing.T) {
+		var err error
+		archive, err = backupsvc.Backup(ctx, &backup.Request{WithInstances: true})
+		assert.NoError(t, err)
+		count := 0
+		for _, s := range archive.Services {
+			count += len(s.Instances)
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("encode and decode, should be the same", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		assert.NoError(t, backup.Encode(buf, archive))
+		a, err := backup.Decode(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, len(archive.Services), len(a.Services))
+		assert.Equal(t, archive.Timestamp, a.Timestamp)
+
+		buf.Reset()
+		assert.NoError(t, backup.Encode(buf, &backup.Archive{Version: backup.ArchiveVersion + 1}))
+		_, err = backup.Decode(buf)
+		assert.Error(t, err)
+	})
+
+	t.Run("restore to itself, should skip all", func(t *testing.T) {
+		report, err := backupsvc.Restore(ctx, &backup.RestoreRequest{Archive: archive})
+		assert.NoError(t, err)
+		assert.Empty(t, report.Conflicts)
+		for _, kr := range report.Kinds {
+			assert.Equal(t, kr.Total, kr.Skipped, kr.Kind)
+		}
+	})
+
+	t.Run("restore with invalid mode, should fail", func(t *testing.T) {
+		_, err := backupsvc.Restore(ctx, &backup.RestoreRequest{Mode: "x", Archive: archive})
+		assert.Equal(t, backupsvc.ErrInvalidMode, err)
+	})
+
+	restoreCtx := util.WithNoCache(util.SetDomainProject(context.Background(), "backup", "restore"))
+	defer func() {
+		for _, id := range []string{consumerID, providerID} {
+			disco.UnregisterService(restoreCtx, &pb.DeleteServiceRequest{ServiceId: id, Force: true})
+		}
+	}()
+
+	t.Run("restore to another project, should create all", func(t *testing.T) {
+		report, err := backupsvc.Restore(restoreCtx, &backup.RestoreRequest{Archive: archive})
+		assert.NoError(t, err)
+		for _, kr := range report.Kinds {
+			assert.Equal(t, kr.Total, kr.Created, kr.Kind)
+			assert.Empty(t, kr.Errors, kr.Kind)
+		}
+
+		a, err := backupsvc.Backup(restoreCtx, &backup.Request{WithInstances: true})
+		assert.NoError(t, err)
+		assert.Equal(t, len(archive.Services), len(a.Services))
+		for i, s := range a.Services {
+			assert.Equal(t, archive.Services[i].Service.ServiceId, s.Service.ServiceId)
+			assert.Equal(t, archive.Services[i].Schemas, s.Schemas)
+			assert.Equal(t, archive.Services[i].Tags, s.Tags)
+			assert.Equal(t, len(archive.Services[i].Rules), len(s.Rules))
+			assert.Equal(t, len(archive.Services[i].Instances), len(s.Instances))
+		}
+		assert.Equal(t, archive.Dependencies, a.Dependencies)
+	})
+
+	_, err = datasource.GetMetadataManager().AddTags(restoreCtx, &pb.AddServiceTagsRequest{
+		ServiceId: providerID,
+		Tags:      map[string]string{"a": "c"},
+	})
+	assert.NoError(t, err)
+	getTag := func() string {
+		resp, err := datasource.GetMetadataManager().GetTags(restoreCtx, &pb.GetServiceTagsRequest{ServiceId: providerID})
+		assert.NoError(t, err)
+		return resp.Tags["a"]
+	}
+
+	t.Run("restore conflicted archive in fail mode, should restore nothing", func(t *testing.T) {
+		report, err := backupsvc.Restore(restoreCtx, &backup.RestoreRequest{Mode: backup.ModeFail, Archive: archive})
+		assert.Equal(t, backupsvc.ErrConflict, err)
+		assert.Equal(t, []*backup.Conflict{{Kind: backup.KindTag, Key: providerID + "/a"}}, report.Conflicts)
+		assert.Equal(t, "c", getTag())
+	})
+
+	t.Run("restore conflicted archive in skip mode, should keep the existing", func(t *testing.T) {
+		report, err := backupsvc.Restore(restoreCtx, &backup.RestoreRequest{Mode: backup.ModeSkip, Archive: archive})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(report.Conflicts))
+		assert.Equal(t, "c", getTag())
+	})
+
+	t.Run("restore conflicted archive in overwrite mode, should replace the existing", func(t *testing.T) {
+		report, err := backupsvc.Restore(restoreCtx, &backup.RestoreRequest{Mode: backup.ModeOverwrite, Archive: archive})
+		assert.NoError(t, err)
+		for _, kr := range report.Kinds {
+			if kr.Kind == backup.KindTag {
+				assert.Equal(t, 1, kr.Overwritten)
+			}
+		}
+		assert.Equal(t, "b", getTag())
+	})
+}
+
+func TestBackup_Roles(t *testing.T) {
+	ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "default", "default"))
+	archive := &backup.Archive{
+		Version: backup.ArchiveVersion,
+		Roles: []*rbac.Role{
+			{Name: "backup_child"},
+			{Name: "backup_parent"},
+		},
+		RoleParents: map[string][]string{"backup_child": {"backup_parent"}},
+	}
+	defer func() {
+		datasource.GetRoleManager().SetRoleParents(ctx, "backup_child", nil)
+		datasource.GetRoleManager().DeleteRole(ctx, "backup_child")
+		datasource.GetRoleManager().DeleteRole(ctx, "backup_parent")
+	}()
+
+	t.Run("restore the roles, should create them with the parents", func(t *testing.T) {
+		report, err := backupsvc.Restore(ctx, &backup.RestoreRequest{Archive: archive})
+		assert.NoError(t, err)
+		for _, kr := range report.Kinds {
+			assert.Equal(t, kr.Total, kr.Created, kr.Kind)
+		}
+		parents, err := datasource.GetRoleManager().GetRoleParents(ctx, "backup_child")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backup_parent"}, parents)
+	})
+
+	t.Run("backup the default project, should contain the parents", func(t *testing.T) {
+		a, err := backupsvc.Backup(ctx, &backup.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backup_parent"}, a.RoleParents["backup_child"])
+	})
+
+	t.Run("restore the roles with other parents, should conflict", func(t *testing.T) {
+		changed := *archive
+		changed.RoleParents = nil
+		report, err := backupsvc.Restore(ctx, &backup.RestoreRequest{Archive: &changed})
+		assert.NoError(t, err)
+		assert.Equal(t, []*backup.Conflict{{Kind: backup.KindRole, Key: "backup_child"}}, report.Conflicts)
+	})
+}
@@ -0,0 +1,591 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/backup"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	LockID = "/cse-sr/lock/restore"
+
+	maxKindErrors = 100
+)
+
+var (
+	ErrInvalidMode     = errors.New("invalid restore mode, must be one of skip, overwrite and fail")
+	ErrEmptyArchive    = errors.New("archive is required")
+	ErrConflict        = errors.New("archive conflicts with the existing resources")
+	ErrRestoring       = errors.New("another restoration is running")
+	ErrCanNotOverwrite = errors.New("can not be overwritten")
+)
+
+// item is a resource in the archive, it is planned before restoration so
+// that the conflicts are known before writing anything
+type item struct {
+	kind string
+	key  string
+	// exist means the item exists in the datasource, equal means it is
+	// identical to the archive one, otherwise it conflicts
+	exist bool
+	equal bool
+
+	create func(ctx context.Context) error
+	// overwrite replaces the existing item, nil means the item can not be
+	// overwritten, e.g. the service id is used by another service
+	overwrite func(ctx context.Context) error
+}
+
+func (i *item) conflicted() bool {
+	return i.exist && !i.equal
+}
+
+// Restore replays the archive to the domain/project in ctx, the existing
+// resources different from the archive are handled by the mode, only one
+// restoration can run in the cluster at the same time
+func Restore(ctx context.Context, in *backup.RestoreRequest) (*backup.Report, error) {
+	mode := in.Mode
+	if len(mode) == 0 {
+		mode = backup.ModeSkip
+	}
+	if mode != backup.ModeSkip && mode != backup.ModeOverwrite && mode != backup.ModeFail {
+		return nil, ErrInvalidMode
+	}
+	if in.Archive == nil {
+		return nil, ErrEmptyArchive
+	}
+
+	err := datasource.GetSystemManager().DLock(ctx, &datasource.DLockRequest{ID: LockID})
+	if err != nil {
+		log.Error("lock restoration failed", err)
+		return nil, ErrRestoring
+	}
+	defer func() {
+		if err := datasource.GetSystemManager().DUnlock(ctx, &datasource.DUnlockRequest{ID: LockID}); err != nil {
+			log.Error("unlock restoration failed", err)
+		}
+	}()
+
+	ctx = util.WithNoCache(ctx)
+	items, err := plan(ctx, in.Archive)
+	if err != nil {
+		return nil, err
+	}
+	report := &backup.Report{Mode: mode}
+	for _, i := range items {
+		if i.conflicted() {
+			report.Conflicts = append(report.Conflicts, &backup.Conflict{Kind: i.kind, Key: i.key})
+		}
+	}
+	if mode == backup.ModeFail && len(report.Conflicts) > 0 {
+		return report, ErrConflict
+	}
+
+	kinds := make(map[string]*backup.KindReport)
+	for _, i := range items {
+		kr, ok := kinds[i.kind]
+		if !ok {
+			kr = &backup.KindReport{Kind: i.kind}
+			kinds[i.kind] = kr
+			report.Kinds = append(report.Kinds, kr)
+		}
+		kr.Total++
+		switch {
+		case i.exist && (i.equal || mode == backup.ModeSkip):
+			kr.Skipped++
+		case i.exist:
+			if err := apply(ctx, i, i.overwrite, kr); err == nil {
+				kr.Overwritten++
+			}
+		default:
+			if err := apply(ctx, i, i.create, kr); err == nil {
+				kr.Created++
+			}
+		}
+	}
+	if kr, ok := kinds[backup.KindDependency]; ok && kr.Created+kr.Overwritten > 0 {
+		// the dependencies are queued, handle them before returning
+		if err := datasource.GetDependencyManager().DependencyHandle(ctx); err != nil {
+			log.Error("handle the restored dependencies failed", err)
+		}
+	}
+	for _, kr := range report.Kinds {
+		log.Info(fmt.Sprintf("restore %s: total %d, created %d, overwritten %d, skipped %d, failed %d",
+			kr.Kind, kr.Total, kr.Created, kr.Overwritten, kr.Skipped, kr.Failed))
+	}
+	return report, nil
+}
+
+func apply(ctx context.Context, i *item, f func(ctx context.Context) error, kr *backup.KindReport) error {
+	err := ErrCanNotOverwrite
+	if f != nil {
+		err = f(ctx)
+	}
+	if err == nil {
+		return nil
+	}
+	log.Error(fmt.Sprintf("restore %s %s failed", i.kind, i.key), err)
+	kr.Failed++
+	if len(kr.Errors) < maxKindErrors {
+		kr.Errors = append(kr.Errors, fmt.Sprintf("%s: %s", i.key, err.Error()))
+	}
+	return err
+}
+
+// plan returns the items in the restoration order, services must be
+// restored before their schemas, tags, rules, instances and dependencies,
+// roles before accounts
+func plan(ctx context.Context, a *backup.Archive) ([]*item, error) {
+	p, err := newPlanner(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range a.Services {
+		if s.Service == nil {
+			continue
+		}
+		serviceID, exist, ok := p.service(s.Service)
+		if !ok {
+			continue
+		}
+		if err := p.schemas(ctx, serviceID, exist, s); err != nil {
+			return nil, err
+		}
+		if err := p.tags(ctx, serviceID, exist, s); err != nil {
+			return nil, err
+		}
+		if err := p.rules(ctx, serviceID, exist, s); err != nil {
+			return nil, err
+		}
+		p.instances(serviceID, s)
+	}
+	for _, dep := range a.Dependencies {
+		if err := p.dependency(ctx, dep); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.roles(ctx, a); err != nil {
+		return nil, err
+	}
+	for _, account := range a.Accounts {
+		if err := p.account(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+	return p.sorted(), nil
+}
+
+type planner struct {
+	items map[string][]*item
+
+	servicesByID  map[string]*discovery.MicroService
+	servicesByKey map[string]*discovery.MicroService
+	instancesByID map[string]*discovery.MicroServiceInstance
+}
+
+func newPlanner(ctx context.Context, a *backup.Archive) (*planner, error) {
+	p := &planner{
+		items:         make(map[string][]*item),
+		servicesByID:  make(map[string]*discovery.MicroService),
+		servicesByKey: make(map[string]*discovery.MicroService),
+		instancesByID: make(map[string]*discovery.MicroServiceInstance),
+	}
+	services, err := getServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		p.servicesByID[service.ServiceId] = service
+		p.servicesByKey[serviceKey(toServiceKey(service))] = service
+	}
+	for _, s := range a.Services {
+		if len(s.Instances) == 0 {
+			continue
+		}
+		instances, err := getInstances(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range instances {
+			for _, instance := range list {
+				p.instancesByID[instance.InstanceId] = instance
+			}
+		}
+		break
+	}
+	return p, nil
+}
+
+func (p *planner) add(i *item) {
+	p.items[i.kind] = append(p.items[i.kind], i)
+}
+
+func (p *planner) sorted() []*item {
+	var items []*item
+	for _, kind := range []string{backup.KindService, backup.KindSchema, backup.KindTag, backup.KindRule,
+		backup.KindInstance, backup.KindDependency, backup.KindRole, backup.KindAccount} {
+		items = append(items, p.items[kind]...)
+	}
+	return items
+}
+
+// service plans the service and returns the id of it in the datasource,
+// the service with the same id or the same key is regarded as existing,
+// ok is false if the id is used by another service
+func (p *planner) service(src *discovery.MicroService) (serviceID string, exist bool, ok bool) {
+	key := serviceKey(toServiceKey(src))
+	i := &item{kind: backup.KindService, key: src.ServiceId}
+	p.add(i)
+
+	dst, exist := p.servicesByID[src.ServiceId]
+	if exist && serviceKey(toServiceKey(dst)) != key {
+		i.exist = true
+		return "", false, false
+	}
+	if !exist {
+		dst, exist = p.servicesByKey[key]
+	}
+	if !exist {
+		i.create = func(ctx context.Context) error {
+			service := *src
+			resp, err := datasource.GetMetadataManager().RegisterService(ctx, &discovery.CreateServiceRequest{
+				Service: &service,
+			})
+			if err != nil {
+				return err
+			}
+			return checkResponse(resp.Response)
+		}
+		return src.ServiceId, false, true
+	}
+
+	i.exist = true
+	i.equal = len(src.Properties) == 0 && len(dst.Properties) == 0 || reflect.DeepEqual(src.Properties, dst.Properties)
+	i.overwrite = func(ctx context.Context) error {
+		resp, err := datasource.GetMetadataManager().UpdateService(ctx, &discovery.UpdateServicePropsRequest{
+			ServiceId:  dst.ServiceId,
+			Properties: src.Properties,
+		})
+		if err != nil {
+			return err
+		}
+		return checkResponse(resp.Response)
+	}
+	return dst.ServiceId, true, true
+}
+
+func (p *planner) schemas(ctx context.Context, serviceID string, exist bool, s *backup.Service) error {
+	existing := make(map[string]*discovery.Schema)
+	if exist && len(s.Schemas) > 0 {
+		schemas, err := getSchemas(ctx, serviceID)
+		if err != nil {
+			return err
+		}
+		for _, schema := range schemas {
+			existing[schema.SchemaId] = schema
+		}
+	}
+	for _, schema := range s.Schemas {
+		src := schema
+		modify := func(ctx context.Context) error {
+			resp, err := datasource.GetMetadataManager().ModifySchema(ctx, &discovery.ModifySchemaRequest{
+				ServiceId: serviceID,
+				SchemaId:  src.SchemaId,
+				Schema:    src.Schema,
+				Summary:   src.Summary,
+			})
+			if err != nil {
+				return err
+			}
+			return checkResponse(resp.Response)
+		}
+		dst, ok := existing[src.SchemaId]
+		p.add(&item{
+			kind:      backup.KindSchema,
+			key:       util.StringJoin([]string{s.Service.ServiceId, src.SchemaId}, datasource.SPLIT),
+			exist:     ok,
+			equal:     ok && dst.Schema == src.Schema && dst.Summary == src.Summary,
+			create:    modify,
+			overwrite: modify,
+		})
+	}
+	return nil
+}
+
+func (p *planner) tags(ctx context.Context, serviceID string, exist bool, s *backup.Service) error {
+	var existing map[string]string
+	if exist && len(s.Tags) > 0 {
+		var err error
+		existing, err = getTags(ctx, serviceID)
+		if err != nil {
+			return err
+		}
+	}
+	for k, v := range s.Tags {
+		tags := map[string]string{k: v}
+		add := func(ctx context.Context) error {
+			resp, err := datasource.GetMetadataManager().AddTags(ctx, &discovery.AddServiceTagsRequest{
+				ServiceId: serviceID,
+				Tags:      tags,
+			})
+			if err != nil {
+				return err
+			}
+			return checkResponse(resp.Response)
+		}
+		value, ok := existing[k]
+		p.add(&item{
+			kind:      backup.KindTag,
+			key:       util.StringJoin([]string{s.Service.ServiceId, k}, datasource.SPLIT),
+			exist:     ok,
+			equal:     ok && value == v,
+			create:    add,
+			overwrite: add,
+		})
+	}
+	return nil
+}
+
+func (p *planner) rules(ctx context.Context, serviceID string, exist bool, s *backup.Service) error {
+	existing := make(map[string]*discovery.ServiceRule)
+	if exist && len(s.Rules) > 0 {
+		rules, err := getRules(ctx, serviceID)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			existing[ruleKey(rule)] = rule
+		}
+	}
+	for _, rule := range s.Rules {
+		src := &discovery.AddOrUpdateServiceRule{
+			RuleType:    rule.RuleType,
+			Attribute:   rule.Attribute,
+			Pattern:     rule.Pattern,
+			Description: rule.Description,
+		}
+		i := &item{
+			kind: backup.KindRule,
+			key:  util.StringJoin([]string{s.Service.ServiceId, ruleKey(rule)}, datasource.SPLIT),
+			create: func(ctx context.Context) error {
+				resp, err := datasource.GetMetadataManager().AddRule(ctx, &discovery.AddServiceRulesRequest{
+					ServiceId: serviceID,
+					Rules:     []*discovery.AddOrUpdateServiceRule{src},
+				})
+				if err != nil {
+					return err
+				}
+				return checkResponse(resp.Response)
+			},
+		}
+		if dst, ok := existing[ruleKey(rule)]; ok {
+			i.exist = true
+			i.equal = dst.Description == src.Description
+			i.overwrite = func(ctx context.Context) error {
+				resp, err := datasource.GetMetadataManager().UpdateRule(ctx, &discovery.UpdateServiceRuleRequest{
+					ServiceId: serviceID,
+					RuleId:    dst.RuleId,
+					Rule:      src,
+				})
+				if err != nil {
+					return err
+				}
+				return checkResponse(resp.Response)
+			}
+		}
+		p.add(i)
+	}
+	return nil
+}
+
+func (p *planner) instances(serviceID string, s *backup.Service) {
+	for _, instance := range s.Instances {
+		src := *instance
+		src.ServiceId = serviceID
+		register := func(ctx context.Context) error {
+			instance := src
+			resp, err := datasource.GetMetadataManager().RegisterInstance(ctx, &discovery.RegisterInstanceRequest{
+				Instance: &instance,
+			})
+			if err != nil {
+				return err
+			}
+			return checkResponse(resp.Response)
+		}
+		dst, ok := p.instancesByID[src.InstanceId]
+		p.add(&item{
+			kind:  backup.KindInstance,
+			key:   util.StringJoin([]string{s.Service.ServiceId, src.InstanceId}, datasource.SPLIT),
+			exist: ok,
+			equal: ok && dst.ServiceId == src.ServiceId && dst.HostName == src.HostName &&
+				reflect.DeepEqual(dst.Endpoints, src.Endpoints) && reflect.DeepEqual(dst.Properties, src.Properties),
+			create:    register,
+			overwrite: register,
+		})
+	}
+}
+
+func (p *planner) dependency(ctx context.Context, dep *discovery.ConsumerDependency) error {
+	if dep.Consumer == nil {
+		return nil
+	}
+	i := &item{kind: backup.KindDependency, key: serviceKey(dep.Consumer)}
+	if consumer, ok := p.servicesByKey[i.key]; ok {
+		providers, err := getProviders(ctx, consumer.ServiceId)
+		if err != nil {
+			return err
+		}
+		if len(providers) > 0 {
+			i.exist = true
+			i.equal = equalServiceKeys(dep.Providers, providers)
+		}
+	}
+	update := func(override bool) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			// the keys are changed by the datasource, do not share them
+			// with the archive
+			consumer := *dep.Consumer
+			providers := make([]*discovery.MicroServiceKey, 0, len(dep.Providers))
+			for _, provider := range dep.Providers {
+				key := *provider
+				providers = append(providers, &key)
+			}
+			resp, err := datasource.GetDependencyManager().AddOrUpdateDependencies(ctx,
+				[]*discovery.ConsumerDependency{{Consumer: &consumer, Providers: providers}}, override)
+			if err != nil {
+				return err
+			}
+			return checkResponse(resp)
+		}
+	}
+	i.create, i.overwrite = update(false), update(true)
+	p.add(i)
+	return nil
+}
+
+// roles plans the roles after the ones they inherit
+func (p *planner) roles(ctx context.Context, a *backup.Archive) error {
+	roles := make(map[string]*rbac.Role, len(a.Roles))
+	names := make([]string, 0, len(a.Roles))
+	for _, role := range a.Roles {
+		roles[role.Name] = role
+		names = append(names, role.Name)
+	}
+	for _, name := range datasource.SortRolesByParents(names, a.RoleParents) {
+		if err := p.role(ctx, roles[name], a.RoleParents[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *planner) role(ctx context.Context, src *rbac.Role, parents []string) error {
+	i := &item{
+		kind: backup.KindRole,
+		key:  src.Name,
+		create: func(ctx context.Context) error {
+			role := *src
+			return datasource.GetRoleManager().CreateRole(ctx, &role, parents...)
+		},
+		overwrite: func(ctx context.Context) error {
+			role := *src
+			if err := datasource.GetRoleManager().UpdateRole(ctx, role.Name, &role); err != nil {
+				return err
+			}
+			return datasource.GetRoleManager().SetRoleParents(ctx, role.Name, parents)
+		},
+	}
+	dst, err := datasource.GetRoleManager().GetRole(ctx, src.Name)
+	if err != nil && !errors.Is(err, datasource.ErrRoleNotExist) {
+		return err
+	}
+	if err == nil {
+		exist, err := datasource.GetRoleManager().GetRoleParents(ctx, src.Name)
+		if err != nil {
+			return err
+		}
+		i.exist = true
+		i.equal = reflect.DeepEqual(src.Perms, dst.Perms) && equalStrings(parents, exist)
+	}
+	p.add(i)
+	return nil
+}
+
+func (p *planner) account(ctx context.Context, src *rbac.Account) error {
+	i := &item{
+		kind: backup.KindAccount,
+		key:  src.Name,
+		create: func(ctx context.Context) error {
+			// the password in the archive is hashed, create it as is in one step
+			account := *src
+			return datasource.GetAccountManager().CreateAccount(util.WithHashedPassword(ctx), &account)
+		},
+		overwrite: func(ctx context.Context) error {
+			account := *src
+			return datasource.GetAccountManager().UpdateAccount(ctx, account.Name, &account)
+		},
+	}
+	dst, err := datasource.GetAccountManager().GetAccount(ctx, src.Name)
+	if err != nil && !errors.Is(err, datasource.ErrAccountNotExist) {
+		return err
+	}
+	if err == nil {
+		i.exist = true
+		i.equal = dst.Password == src.Password && dst.Status == src.Status && reflect.DeepEqual(dst.Roles, src.Roles)
+	}
+	p.add(i)
+	return nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+func equalServiceKeys(a, b []*discovery.MicroServiceKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keys := make(map[string]struct{}, len(a))
+	for _, key := range a {
+		keys[serviceKey(key)] = struct{}{}
+	}
+	for _, key := range b {
+		if _, ok := keys[serviceKey(key)]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func getServices(ctx context.Context) ([]*discovery.MicroService, error) {
+	resp, err := datasource.GetMetadataManager().GetServices(ctx, &discovery.GetServicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	sort.Slice(resp.Services, func(i, j int) bool { return resp.Services[i].ServiceId < resp.Services[j].ServiceId })
+	return resp.Services, nil
+}
+
+// getInstances returns the instances of the domain/project grouped by the
+// service id
+func getInstances(ctx context.Context) (map[string][]*discovery.MicroServiceInstance, error) {
+	resp, err := datasource.GetMetadataManager().GetAllInstances(ctx, &discovery.GetAllInstancesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	instances := make(map[string][]*discovery.MicroServiceInstance)
+	for _, instance := range resp.Instances {
+		instances[instance.ServiceId] = append(instances[instance.ServiceId], instance)
+	}
+	for _, list := range instances {
+		sort.Slice(list, func(i, j int) bool { return list[i].InstanceId < list[j].InstanceId })
+	}
+	return instances, nil
+}
+
+// getSchemas returns the schemas with content, the schema ids declared by
+// the service without content are restored with the service
+func getSchemas(ctx context.Context, serviceID string) ([]*discovery.Schema, error) {
+	resp, err := datasource.GetMetadataManager().GetAllSchemas(ctx, &discovery.GetAllSchemaRequest{
+		ServiceId:  serviceID,
+		WithSchema: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	var schemas []*discovery.Schema
+	for _, schema := range resp.Schemas {
+		if len(schema.Schema) > 0 {
+			schemas = append(schemas, schema)
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].SchemaId < schemas[j].SchemaId })
+	return schemas, nil
+}
+
+func getTags(ctx context.Context, serviceID string) (map[string]string, error) {
+	resp, err := datasource.GetMetadataManager().GetTags(ctx, &discovery.GetServiceTagsRequest{
+		ServiceId: serviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+func getRules(ctx context.Context, serviceID string) ([]*discovery.ServiceRule, error) {
+	resp, err := datasource.GetMetadataManager().GetRules(ctx, &discovery.GetServiceRulesRequest{
+		ServiceId: serviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	sort.Slice(resp.Rules, func(i, j int) bool { return ruleKey(resp.Rules[i]) < ruleKey(resp.Rules[j]) })
+	return resp.Rules, nil
+}
+
+// getProviders returns the providers of the consumer, the version rules are
+// resolved to the exact versions of the providers
+func getProviders(ctx context.Context, consumerID string) ([]*discovery.MicroServiceKey, error) {
+	resp, err := datasource.GetDependencyManager().SearchConsumerDependency(ctx, &discovery.GetDependenciesRequest{
+		ServiceId: consumerID,
+		NoSelf:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp.Response); err != nil {
+		return nil, err
+	}
+	providers := make([]*discovery.MicroServiceKey, 0, len(resp.Providers))
+	for _, provider := range resp.Providers {
+		providers = append(providers, toServiceKey(provider))
+	}
+	sort.Slice(providers, func(i, j int) bool { return serviceKey(providers[i]) < serviceKey(providers[j]) })
+	return providers, nil
+}
+
+func ruleKey(rule *discovery.ServiceRule) string {
+	return util.StringJoin([]string{rule.RuleType, rule.Attribute, rule.Pattern}, datasource.SPLIT)
+}
+
+func serviceKey(key *discovery.MicroServiceKey) string {
+	return util.StringJoin([]string{key.Environment, key.AppId, key.ServiceName, key.Version}, datasource.SPLIT)
+}
+
+func toServiceKey(service *discovery.MicroService) *discovery.MicroServiceKey {
+	return &discovery.MicroServiceKey{
+		Environment: service.Environment,
+		AppId:       service.AppId,
+		ServiceName: service.ServiceName,
+		Version:     service.Version,
+	}
+}
+
+func checkResponse(resp *discovery.Response) error {
+	if resp == nil || resp.GetCode() == discovery.ResponseSuccess {
+		return nil
+	}
+	return fmt.Errorf("%d: %s", resp.GetCode(), resp.GetMessage())
+}
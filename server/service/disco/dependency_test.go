@@ -891,7 +891,7 @@ func DependencyHandle() {
 	if t == nil {
 		t = "etcd"
 	}
-	if t == "etcd" {
+	if t == "etcd" || t == "local" {
 		for {
 			Expect(deh.Handle()).To(BeNil())
 
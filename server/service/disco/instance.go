@@ -111,7 +111,7 @@ func GetOneInstance(ctx context.Context,
 	return datasource.GetMetadataManager().GetInstance(ctx, in)
 }
 
-func GetInstances(ctx context.Context, in *pb.GetInstancesRequest) (*pb.GetInstancesResponse, error) {
+func GetInstances(ctx context.Context, in *pb.GetInstancesRequest, opts ...datasource.ListOption) (*pb.GetInstancesResponse, error) {
 	err := validator.Validate(in)
 	if err != nil {
 		log.Errorf(err, "get instances failed: invalid parameters")
@@ -120,7 +120,7 @@ func GetInstances(ctx context.Context, in *pb.GetInstancesRequest) (*pb.GetInsta
 		}, nil
 	}
 
-	return datasource.GetMetadataManager().GetInstances(ctx, in)
+	return datasource.GetMetadataManager().GetInstances(ctx, in, opts...)
 }
 
 func FindInstances(ctx context.Context, in *pb.FindInstancesRequest) (*pb.FindInstancesResponse, error) {
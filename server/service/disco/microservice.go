@@ -210,7 +210,13 @@ func (s *MicroServiceService) GetOne(ctx context.Context, in *pb.GetServiceReque
 }
 
 func (s *MicroServiceService) GetServices(ctx context.Context, in *pb.GetServicesRequest) (*pb.GetServicesResponse, error) {
-	return datasource.GetMetadataManager().GetServices(ctx, in)
+	return ListServices(ctx, in)
+}
+
+// ListServices returns the page of the services, the total count is set in
+// ctx by util.WithResponseTotal
+func ListServices(ctx context.Context, in *pb.GetServicesRequest, opts ...datasource.ListOption) (*pb.GetServicesResponse, error) {
+	return datasource.GetMetadataManager().GetServices(ctx, in, opts...)
 }
 
 func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.UpdateServicePropsRequest) (*pb.UpdateServicePropsResponse, error) {
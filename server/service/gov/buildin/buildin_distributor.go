@@ -0,0 +1,352 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+)
+
+// Distributor saves the policies in the datasource of service center, it
+// needs no config server
+type Distributor struct {
+	name string
+}
+
+const (
+	KindMatchGroup  = "match-group"
+	GroupNamePrefix = "scene-"
+	StatusEnabled   = "enabled"
+	EnvAll          = "all"
+	Alias           = "alias"
+)
+
+// PolicyKinds are the kinds of the policies bound to the match groups by name
+var PolicyKinds = []string{"retry", "rate-limiting", "circuit-breaker", "bulkhead", "loadbalancer"}
+
+var rule = kie.Validator{}
+
+func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	p := &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{Selector: &gov.Selector{}},
+	}
+	err := json.Unmarshal(spec, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.Selector == nil {
+		p.Selector = &gov.Selector{}
+	}
+	ctx := context.TODO()
+	if kind == KindMatchGroup && p.Name == "" {
+		policies, err := d.listPolicy(ctx, kind, project, p.Selector.App, p.Selector.Environment, true)
+		if err != nil {
+			return nil, err
+		}
+		p.Name = generateName(policies)
+	}
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if kind == KindMatchGroup {
+		setAliasIfEmpty(p.Spec, p.Name)
+	}
+	specJSON, err := json.Marshal(p.Spec)
+	if err != nil {
+		return nil, err
+	}
+	status := p.Status
+	if status == "" {
+		status = StatusEnabled
+	}
+	now := time.Now().Unix()
+	item := &datasource.GovPolicy{
+		Project:     project,
+		Kind:        kind,
+		ID:          util.GenerateUUID(),
+		Name:        p.Name,
+		Status:      status,
+		App:         p.Selector.App,
+		Environment: p.Selector.Environment,
+		Spec:        string(specJSON),
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	// the name is unique in the kind and the selector, checked by the datasource
+	err = datasource.GetGovPolicyManager().CreatePolicy(ctx, item)
+	if err != nil {
+		if errors.Is(err, datasource.ErrPolicyAlreadyExists) {
+			return nil, fmt.Errorf("%s policy %s: %w", kind, p.Name, err)
+		}
+		log.Error("buildin create failed", err)
+		return nil, err
+	}
+	log.Infof("create %s policy %s, name: %s", kind, item.ID, item.Name)
+	return []byte(item.ID), nil
+}
+
+func (d *Distributor) Update(kind, id, project string, spec []byte) error {
+	p := &gov.Policy{}
+	err := json.Unmarshal(spec, p)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	item, err := datasource.GetGovPolicyManager().GetPolicy(ctx, project, kind, id)
+	if err != nil {
+		return err
+	}
+	log.Infof("update %s policy %s, name: %s", kind, id, item.Name)
+	err = rule.Validate(kind, p.Spec)
+	if err != nil {
+		return err
+	}
+	if kind == KindMatchGroup {
+		setAliasIfEmpty(p.Spec, item.Name)
+	}
+	specJSON, err := json.Marshal(p.Spec)
+	if err != nil {
+		return err
+	}
+	item.Spec = string(specJSON)
+	if p.GovernancePolicy != nil && p.Status != "" {
+		item.Status = p.Status
+	}
+	item.UpdateTime = time.Now().Unix()
+	err = datasource.GetGovPolicyManager().UpdatePolicy(ctx, item)
+	if err != nil {
+		log.Error("buildin update failed", err)
+		return err
+	}
+	return nil
+}
+
+func (d *Distributor) Delete(kind, id, project string) error {
+	ctx := context.TODO()
+	if kind != KindMatchGroup {
+		return datasource.GetGovPolicyManager().DeletePolicy(ctx, project, kind, id)
+	}
+	// should remove all policies of this group
+	group, err := datasource.GetGovPolicyManager().GetPolicy(ctx, project, kind, id)
+	if err != nil {
+		if err == datasource.ErrPolicyNotExist {
+			return nil
+		}
+		return err
+	}
+	policies, err := datasource.GetGovPolicyManager().ListPolicy(ctx, project, "")
+	if err != nil {
+		log.Error("buildin list failed", err)
+		return err
+	}
+	for _, item := range policies {
+		if item.Name != group.Name || item.App != group.App || item.Environment != group.Environment {
+			continue
+		}
+		err = datasource.GetGovPolicyManager().DeletePolicy(ctx, project, item.Kind, item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Distributor) Display(project, app, env string) ([]byte, error) {
+	ctx := context.TODO()
+	groups, err := d.listPolicy(ctx, KindMatchGroup, project, app, env, false)
+	if err != nil {
+		return nil, err
+	}
+	policyMap := make(map[string]*gov.Policy)
+	for _, kind := range PolicyKinds {
+		policies, err := d.listPolicy(ctx, kind, project, app, env, false)
+		if err != nil {
+			continue
+		}
+		for _, item := range policies {
+			policy, err := transform(item)
+			if err != nil {
+				continue
+			}
+			policyMap[groupKey(item)+kind] = policy
+		}
+	}
+	r := make([]*gov.DisplayData, 0, len(groups))
+	for _, item := range groups {
+		match, err := transform(item)
+		if err != nil {
+			continue
+		}
+		var policies []*gov.Policy
+		for _, kind := range PolicyKinds {
+			if policyMap[groupKey(item)+kind] != nil {
+				policies = append(policies, policyMap[groupKey(item)+kind])
+			}
+		}
+		r = append(r, &gov.DisplayData{
+			Policies:   policies,
+			MatchGroup: match,
+		})
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) List(kind, project, app, env string) ([]byte, error) {
+	policies, err := d.listPolicy(context.TODO(), kind, project, app, env, false)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*gov.Policy, 0, len(policies))
+	for _, item := range policies {
+		policy, err := transform(item)
+		if err != nil {
+			continue
+		}
+		r = append(r, policy)
+	}
+	b, _ := json.MarshalIndent(r, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Get(kind, id, project string) ([]byte, error) {
+	item, err := datasource.GetGovPolicyManager().GetPolicy(context.TODO(), project, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := transform(item)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.MarshalIndent(policy, "", "  ")
+	return b, nil
+}
+
+func (d *Distributor) Type() string {
+	return svc.ConfigDistributorBuildin
+}
+func (d *Distributor) Name() string {
+	return d.name
+}
+
+// listPolicy returns the policies matching the selector, the environment
+// matches any one if it is "all", and the app matches any one if it is
+// empty, unless exact is true
+func (d *Distributor) listPolicy(ctx context.Context, kind, project, app, env string, exact bool) ([]*datasource.GovPolicy, error) {
+	policies, err := datasource.GetGovPolicyManager().ListPolicy(ctx, project, kind)
+	if err != nil {
+		log.Error("buildin list failed", err)
+		return nil, err
+	}
+	r := make([]*datasource.GovPolicy, 0, len(policies))
+	for _, item := range policies {
+		if (exact || env != EnvAll) && item.Environment != env {
+			continue
+		}
+		if (exact || app != "") && item.App != app {
+			continue
+		}
+		r = append(r, item)
+	}
+	return r, nil
+}
+
+func groupKey(item *datasource.GovPolicy) string {
+	return item.App + "/" + item.Environment + "/" + item.Name + "/"
+}
+
+func setAliasIfEmpty(val interface{}, name string) {
+	spec, ok := val.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if alias, _ := spec[Alias].(string); alias == "" {
+		spec[Alias] = name
+	}
+}
+
+func generateName(policies []*datasource.GovPolicy) string {
+	exist := make(map[string]bool, len(policies))
+	for _, item := range policies {
+		exist[item.Name] = true
+	}
+	str := "0123456789abcdefghijklmnopqrstuvwxyz"
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for {
+		result := make([]byte, 4)
+		for i := range result {
+			result[i] = str[r.Intn(len(str))]
+		}
+		name := GroupNamePrefix + string(result)
+		if !exist[name] {
+			return name
+		}
+	}
+}
+
+func transform(item *datasource.GovPolicy) (*gov.Policy, error) {
+	spec := make(map[string]interface{})
+	err := json.Unmarshal([]byte(item.Spec), &spec)
+	if err != nil {
+		log.Warn(fmt.Sprintf("transform policy failed: kind is [%s], id is [%s], spec is [%s]",
+			item.Kind, item.ID, item.Spec))
+		return nil, err
+	}
+	return &gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:       item.Name,
+			ID:         item.ID,
+			Status:     item.Status,
+			CreatTime:  item.CreateTime,
+			UpdateTime: item.UpdateTime,
+			Selector: &gov.Selector{
+				App:         item.App,
+				Environment: item.Environment,
+			},
+		},
+		Kind: item.Kind,
+		Spec: spec,
+	}, nil
+}
+
+// NewDistributor returns a Distributor named name, it can be embedded by
+// the distributors which translate the persisted policies
+func NewDistributor(name string) *Distributor {
+	return &Distributor{name: name}
+}
+
+func newDistributor(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+	return NewDistributor(opts.Name), nil
+}
+
+func init() {
+	svc.InstallDistributor(svc.ConfigDistributorBuildin, newDistributor)
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/config"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	_ "github.com/apache/servicecomb-service-center/server/service/gov/buildin"
+	_ "github.com/apache/servicecomb-service-center/test"
+)
+
+const (
+	project = "TestBuildinDistributor"
+	app     = "app"
+	env     = "production"
+)
+
+func init() {
+	config.App.Gov = &config.Gov{
+		DistOptions: []config.DistributorOptions{{Name: "buildin", Type: svc.ConfigDistributorBuildin}},
+	}
+	if err := svc.Init(); err != nil {
+		panic(err)
+	}
+}
+
+func marshal(t *testing.T, name string, spec interface{}) []byte {
+	b, err := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: &gov.Selector{App: app, Environment: env},
+		},
+		Spec: spec,
+	})
+	assert.NoError(t, err)
+	return b
+}
+
+func TestDistributor(t *testing.T) {
+	matchSpec := map[string]interface{}{
+		"alias":   "",
+		"matches": []interface{}{map[string]interface{}{"name": "get", "method": []interface{}{"GET"}}},
+	}
+	retrySpec := map[string]interface{}{"maxAttempts": 3}
+
+	var groupID, retryID string
+	t.Run("create policies, should succeed", func(t *testing.T) {
+		id, err := svc.Create("match-group", project, marshal(t, "", matchSpec))
+		assert.NoError(t, err)
+		groupID = string(id)
+
+		b, err := svc.Get("match-group", groupID, project)
+		assert.NoError(t, err)
+		group := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(b, group))
+		assert.Regexp(t, "^scene-", group.Name)
+		assert.Equal(t, group.Name, group.Spec.(map[string]interface{})["alias"])
+		assert.Equal(t, "enabled", group.Status)
+
+		id, err = svc.Create("retry", project, marshal(t, group.Name, retrySpec))
+		assert.NoError(t, err)
+		retryID = string(id)
+
+		_, err = svc.Create("retry", project, marshal(t, group.Name, retrySpec))
+		assert.True(t, errors.Is(err, datasource.ErrPolicyAlreadyExists))
+	})
+	t.Run("create invalid policy, should fail", func(t *testing.T) {
+		_, err := svc.Create("unknown", project, marshal(t, "x", retrySpec))
+		assert.Error(t, err)
+		_, err = svc.Create("match-group", project, marshal(t, "x", map[string]interface{}{
+			"alias": "x", "matches": []interface{}{map[string]interface{}{"name": "x"}}}))
+		assert.Error(t, err)
+	})
+	t.Run("update policy, should succeed", func(t *testing.T) {
+		b, err := json.Marshal(&gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{Status: "disabled"},
+			Spec:             map[string]interface{}{"maxAttempts": 5},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, svc.Update("retry", retryID, project, b))
+		b, err = svc.Get("retry", retryID, project)
+		assert.NoError(t, err)
+		retry := &gov.Policy{}
+		assert.NoError(t, json.Unmarshal(b, retry))
+		assert.Equal(t, "disabled", retry.Status)
+		assert.Equal(t, float64(5), retry.Spec.(map[string]interface{})["maxAttempts"])
+
+		err = svc.Update("retry", "not-exist", project, b)
+		assert.True(t, errors.Is(err, datasource.ErrPolicyNotExist))
+	})
+	t.Run("list and display by selector, should return the matched", func(t *testing.T) {
+		var policies []*gov.Policy
+		b, err := svc.List("retry", project, app, env)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		assert.Equal(t, 1, len(policies))
+
+		b, err = svc.List("retry", project, app, "all")
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		assert.Equal(t, 1, len(policies))
+
+		b, err = svc.List("retry", project, app, "testing")
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &policies))
+		assert.Equal(t, 0, len(policies))
+
+		var display []*gov.DisplayData
+		b, err = svc.Display(project, app, env)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(b, &display))
+		assert.Equal(t, 1, len(display))
+		assert.Equal(t, groupID, display[0].MatchGroup.ID)
+		assert.Equal(t, 1, len(display[0].Policies))
+		assert.Equal(t, retryID, display[0].Policies[0].ID)
+	})
+	t.Run("delete match group, should delete its policies", func(t *testing.T) {
+		assert.NoError(t, svc.Delete("match-group", groupID, project))
+		_, err := svc.Get("match-group", groupID, project)
+		assert.True(t, errors.Is(err, datasource.ErrPolicyNotExist))
+		_, err = svc.Get("retry", retryID, project)
+		assert.True(t, errors.Is(err, datasource.ErrPolicyNotExist))
+	})
+	t.Run("create policies of the same name concurrently, should only create one", func(t *testing.T) {
+		var wg sync.WaitGroup
+		ids := make(chan string, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id, err := svc.Create("retry", project, marshal(t, "concurrent", retrySpec))
+				if err == nil {
+					ids <- string(id)
+					return
+				}
+				assert.True(t, errors.Is(err, datasource.ErrPolicyAlreadyExists))
+			}()
+		}
+		wg.Wait()
+		close(ids)
+		assert.Equal(t, 1, len(ids))
+		for id := range ids {
+			assert.NoError(t, svc.Delete("retry", id, project))
+		}
+
+		id, err := svc.Create("retry", project, marshal(t, "concurrent", retrySpec))
+		assert.NoError(t, err)
+		assert.NoError(t, svc.Delete("retry", string(id), project))
+	})
+}
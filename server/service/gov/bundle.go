@@ -0,0 +1,256 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+// ErrInvalidBundle means the bundle can not be parsed or has illegal policies
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+var docSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// Export returns the policies of the project as a multi-document YAML
+// bundle, an empty app or environment matches any
+func Export(project, app, env string) ([]byte, error) {
+	policies, err := scopedPolicies(project, app, env)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, p := range policies {
+		b, err := yaml.JSONToYAML(marshalPolicy(p))
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseBundle returns the policies of a multi-document YAML bundle, each
+// document is a policy with its kind
+func ParseBundle(bundle []byte) ([]*gov.Policy, error) {
+	var policies []*gov.Policy
+	keys := make(map[string]bool)
+	for _, doc := range docSeparator.Split(string(bundle), -1) {
+		b, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidBundle, len(policies)+1, err)
+		}
+		if string(b) == "null" {
+			continue
+		}
+		p := &gov.Policy{}
+		if err = json.Unmarshal(b, p); err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", ErrInvalidBundle, len(policies)+1, err)
+		}
+		if p.GovernancePolicy == nil || p.Name == "" {
+			return nil, fmt.Errorf("%w: document %d: name is required", ErrInvalidBundle, len(policies)+1)
+		}
+		if kindIndex(p.Kind) < 0 {
+			return nil, fmt.Errorf("%w: %s: unknown kind %q", ErrInvalidBundle, p.Name, p.Kind)
+		}
+		p.ID, p.CreatTime, p.UpdateTime = "", 0, 0
+		if p.Selector == nil {
+			p.Selector = &gov.Selector{}
+		}
+		key := p.Kind + "/" + policyKey(p)
+		if keys[key] {
+			return nil, fmt.Errorf("%w: duplicate %s policy %s", ErrInvalidBundle, p.Kind, p.Name)
+		}
+		keys[key] = true
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// Apply reconciles the policies of the project in the app and environment
+// with the bundle, the policies not in the bundle are deleted. If neither
+// app nor environment is set, the scope is the selectors found in the
+// bundle, so the policies of the other apps are kept. The match
+// groups are created before and deleted after the other policies. The plan
+// is only computed in a dry run, otherwise applied is called after each
+// step kept by the primary distributor, and Apply stops at the first
+// failed step
+func Apply(project, app, env string, bundle []byte, dryRun bool, applied func(*gov.Step)) (*gov.Plan, error) {
+	desired, err := ParseBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range desired {
+		if !inScope(p, app, env) {
+			return nil, fmt.Errorf("%w: %s policy %s is out of app %q and environment %q",
+				ErrInvalidBundle, p.Kind, p.Name, app, env)
+		}
+	}
+	sort.SliceStable(desired, func(i, j int) bool {
+		return kindIndex(desired[i].Kind) < kindIndex(desired[j].Kind)
+	})
+	current, err := scopedPolicies(project, app, env)
+	if err != nil {
+		return nil, err
+	}
+	if app == "" && env == "" {
+		current = bundleScoped(current, desired)
+	}
+	existing := make(map[string]*gov.Policy, len(current))
+	for _, p := range current {
+		existing[p.Kind+"/"+policyKey(p)] = p
+	}
+
+	plan := &gov.Plan{Steps: []*gov.Step{}}
+	var policies []*gov.Policy
+	for _, p := range desired {
+		key := p.Kind + "/" + policyKey(p)
+		old, ok := existing[key]
+		if !ok {
+			plan.Steps = append(plan.Steps, newStep(gov.ActionCreate, p))
+			policies = append(policies, p)
+			continue
+		}
+		delete(existing, key)
+		if p.Status == "" {
+			p.Status = old.Status
+		}
+		diff := Diff(old, p)
+		if len(diff) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		step := newStep(gov.ActionUpdate, p)
+		step.ID, step.Diff = old.ID, diff
+		plan.Steps = append(plan.Steps, step)
+		policies = append(policies, p)
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		p := current[i]
+		if _, ok := existing[p.Kind+"/"+policyKey(p)]; !ok {
+			continue
+		}
+		step := newStep(gov.ActionDelete, p)
+		step.ID = p.ID
+		plan.Steps = append(plan.Steps, step)
+		policies = append(policies, p)
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	for i, step := range plan.Steps {
+		switch step.Action {
+		case gov.ActionCreate:
+			var id []byte
+			id, err = Create(step.Kind, project, marshalPolicy(policies[i]))
+			step.ID = string(id)
+		case gov.ActionUpdate:
+			err = Update(step.Kind, step.ID, project, marshalPolicy(policies[i]))
+		case gov.ActionDelete:
+			err = Delete(step.Kind, step.ID, project)
+			// the policies of a deleted match group may be deleted together
+			if errors.Is(err, datasource.ErrPolicyNotExist) {
+				err = nil
+			}
+		}
+		if applied != nil && Applied(err) {
+			applied(step)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("%s %s policy %s: %w", step.Action, step.Kind, step.Name, err)
+		}
+	}
+	plan.Applied = true
+	return plan, nil
+}
+
+// scopedPolicies returns the policies of the project in the app and
+// environment ordered by kind and key, the kinds of the policies are set
+func scopedPolicies(project, app, env string) ([]*gov.Policy, error) {
+	var r []*gov.Policy
+	if primary == nil {
+		return r, nil
+	}
+	for _, kind := range Kinds {
+		policies, err := listPolicies(primary, kind, project)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(policies) {
+			p := policies[key]
+			if !inScope(p, app, env) {
+				continue
+			}
+			p.Kind = kind
+			r = append(r, p)
+		}
+	}
+	return r, nil
+}
+
+// bundleScoped returns the policies having the same app and environment as
+// any policy of the bundle
+func bundleScoped(policies, desired []*gov.Policy) []*gov.Policy {
+	selectors := make(map[gov.Selector]bool, len(desired))
+	for _, p := range desired {
+		selectors[*p.Selector] = true
+	}
+	var r []*gov.Policy
+	for _, p := range policies {
+		selector := gov.Selector{}
+		if p.Selector != nil {
+			selector = *p.Selector
+		}
+		if selectors[selector] {
+			r = append(r, p)
+		}
+	}
+	return r
+}
+
+func inScope(p *gov.Policy, app, env string) bool {
+	selector := p.Selector
+	if selector == nil {
+		selector = &gov.Selector{}
+	}
+	return (app == "" || selector.App == app) && (env == "" || selector.Environment == env)
+}
+
+func kindIndex(kind string) int {
+	for i, k := range Kinds {
+		if k == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+func newStep(action string, p *gov.Policy) *gov.Step {
+	return &gov.Step{Action: action, Kind: p.Kind, Name: p.Name, Selector: p.Selector}
+}
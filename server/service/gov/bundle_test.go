@@ -0,0 +1,141 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/stretchr/testify/assert"
+)
+
+const bundle = `
+kind: match-group
+name: orders
+selector:
+  app: bundle
+  environment: production
+spec:
+  matches:
+  - apiPath:
+      prefix: /orders
+---
+kind: retry
+name: orders
+selector:
+  app: bundle
+  environment: production
+spec:
+  retryNext: 1
+`
+
+func TestApply(t *testing.T) {
+	t.Run("apply a bundle in dry run, should not change the policies", func(t *testing.T) {
+		plan, err := svc.Apply(Project, "bundle", "", []byte(bundle), true, nil)
+		assert.NoError(t, err)
+		assert.False(t, plan.Applied)
+		assert.Equal(t, 2, len(plan.Steps))
+		b, err := svc.Export(Project, "bundle", "")
+		assert.NoError(t, err)
+		assert.Empty(t, b)
+	})
+	t.Run("apply a bundle, should create the policies", func(t *testing.T) {
+		var applied []string
+		plan, err := svc.Apply(Project, "bundle", "", []byte(bundle), false, func(step *gov.Step) {
+			applied = append(applied, step.Action+" "+step.Kind)
+		})
+		assert.NoError(t, err)
+		assert.True(t, plan.Applied)
+		assert.Equal(t, []string{"create match-group", "create retry"}, applied)
+		for _, step := range plan.Steps {
+			assert.NotEmpty(t, step.ID)
+		}
+	})
+	t.Run("apply the exported bundle, should change nothing", func(t *testing.T) {
+		b, err := svc.Export(Project, "bundle", "")
+		assert.NoError(t, err)
+		policies, err := svc.ParseBundle(b)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(policies))
+
+		plan, err := svc.Apply(Project, "bundle", "", b, false, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, plan.Steps)
+		assert.Equal(t, 2, plan.Unchanged)
+	})
+	t.Run("apply a changed bundle, should update and delete the policies", func(t *testing.T) {
+		changed := `
+kind: match-group
+name: orders
+selector:
+  app: bundle
+  environment: production
+spec:
+  matches:
+  - apiPath:
+      prefix: /api/orders
+`
+		plan, err := svc.Apply(Project, "bundle", "", []byte(changed), false, nil)
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(plan.Steps)) {
+			assert.Equal(t, gov.ActionUpdate, plan.Steps[0].Action)
+			assert.Equal(t, gov.ActionDelete, plan.Steps[1].Action)
+			assert.Equal(t, RetryKind, plan.Steps[1].Kind)
+		}
+		b, err := svc.Export(Project, "bundle", "")
+		assert.NoError(t, err)
+		policies, err := svc.ParseBundle(b)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(policies))
+
+		plan, err = svc.Apply(Project, "bundle", "", nil, false, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(plan.Steps))
+	})
+	t.Run("apply without app and environment, should keep the policies of the other selectors", func(t *testing.T) {
+		_, err := svc.Apply(Project, "bundle", "production", []byte(bundle), false, nil)
+		assert.NoError(t, err)
+		other := strings.Replace(bundle, "environment: production", "environment: testing", -1)
+		plan, err := svc.Apply(Project, "", "", []byte(other), false, nil)
+		assert.NoError(t, err)
+		for _, step := range plan.Steps {
+			assert.Equal(t, gov.ActionCreate, step.Action)
+		}
+		b, err := svc.Export(Project, "bundle", "production")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, b)
+
+		plan, err = svc.Apply(Project, "bundle", "", nil, false, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(plan.Steps))
+	})
+	t.Run("apply an invalid bundle, should be rejected", func(t *testing.T) {
+		for _, b := range []string{
+			"kind: unknown\nname: a\n",
+			"kind: retry\n",
+			"kind: retry\nname: a\n---\nkind: retry\nname: a\n",
+			"kind: retry\nname: a\nselector:\n  app: other\n",
+		} {
+			_, err := svc.Apply(Project, "bundle", "", []byte(b), true, nil)
+			assert.True(t, errors.Is(err, svc.ErrInvalidBundle), b)
+		}
+	})
+}
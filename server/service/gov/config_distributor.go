@@ -18,21 +18,45 @@
 package gov
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/config"
 )
 
 const (
-	ConfigDistributorKie   = "kie"
-	ConfigDistributorIstio = "istio"
-	ConfigDistributorMock  = "mock"
+	ConfigDistributorKie     = "kie"
+	ConfigDistributorIstio   = "istio"
+	ConfigDistributorMock    = "mock"
+	ConfigDistributorBuildin = "buildin"
+
+	EnvAll = "all"
 )
 
+// Kinds are the kinds of the policies compared by Drift
+var Kinds = []string{"match-group", "retry", "rate-limiting", "circuit-breaker", "bulkhead", "loadbalancer"}
+
 type NewDistributors func(opts config.DistributorOptions) (ConfigDistributor, error)
 
-var distributors = map[string]ConfigDistributor{}
+// distributors are ordered as configured, the primary one serves the reads
+// and applies the writes first
+var distributors []ConfigDistributor
+var primary ConfigDistributor
 var distributorPlugins = map[string]NewDistributors{}
 
+// datasourceTypes are the distributors saving the policies in the datasource
+// of service center, they share the same keys so at most one is allowed
+var datasourceTypes = map[string]bool{
+	ConfigDistributorBuildin: true,
+	ConfigDistributorIstio:   true,
+}
+
 //ConfigDistributor persist and distribute Governance policy
 //typically, a ConfigDistributor interact with a config server, like ctrip apollo, kie.
 //or service mesh system like istio, linkerd.
@@ -48,6 +72,45 @@ type ConfigDistributor interface {
 	Name() string
 }
 
+// ErrDistribute means the primary distributor applied the change, but some
+// of the others failed
+type ErrDistribute struct {
+	Op string
+	// ID is the policy id in the primary distributor
+	ID string
+	// Failures are the errors of the distributors by name
+	Failures   map[string]error
+	RolledBack bool
+}
+
+// Applied returns whether the change is kept by the primary distributor, it
+// is true if err is nil or an ErrDistribute not rolled back
+func Applied(err error) bool {
+	if err == nil {
+		return true
+	}
+	var de *ErrDistribute
+	return errors.As(err, &de) && !de.RolledBack
+}
+
+func (e *ErrDistribute) Error() string {
+	names := make([]string, 0, len(e.Failures))
+	for name := range e.Failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failures := make([]string, 0, len(names))
+	for _, name := range names {
+		failures = append(failures, fmt.Sprintf("%s: %v", name, e.Failures[name]))
+	}
+	result := "kept by the primary distributor"
+	if e.RolledBack {
+		result = "rolled back"
+	}
+	return fmt.Sprintf("%s policy %s failed on distributors [%s], %s",
+		e.Op, e.ID, strings.Join(failures, "; "), result)
+}
+
 //InstallDistributor install a plugin to distribute and persist config
 func InstallDistributor(t string, newDistributors NewDistributors) {
 	distributorPlugins[t] = newDistributors
@@ -56,63 +119,407 @@ func InstallDistributor(t string, newDistributors NewDistributors) {
 //Init create distributors according to gov config.
 //it may creates multiple distributors. and distribute policy one by one
 func Init() error {
-	distOptions := config.GetGov().DistOptions
-	for _, opts := range distOptions {
+	distributors, primary = nil, nil
+	govConfig := config.GetGov()
+	datasourceDistributor := ""
+	for _, opts := range govConfig.DistOptions {
 		if opts.Type == "" {
 			log.Warn("empty plugin, skip")
+			continue
+		}
+		if datasourceTypes[opts.Type] {
+			if datasourceDistributor != "" {
+				return fmt.Errorf("distributors %s and %s both save the policies in the datasource, configure only one of them",
+					datasourceDistributor, opts.Name)
+			}
+			datasourceDistributor = opts.Name
 		}
 		f, ok := distributorPlugins[opts.Type]
 		if !ok {
 			log.Warn("unsupported plugin " + opts.Type)
+			continue
 		}
 		cd, err := f(opts)
 		if err != nil {
 			log.Error("can not init config distributor", err)
 			return err
 		}
-		distributors[opts.Name+"::"+opts.Type] = cd
+		distributors = append(distributors, cd)
+		if opts.Name == govConfig.Primary {
+			primary = cd
+		}
+	}
+	if primary != nil || len(distributors) == 0 {
+		return nil
 	}
+	if govConfig.Primary != "" {
+		return fmt.Errorf("primary distributor %s is not configured", govConfig.Primary)
+	}
+	primary = distributors[0]
 	return nil
 }
 
-func Create(kind, project string, spec []byte) ([]byte, error) {
+// Primary returns the distributor serving the reads
+func Primary() ConfigDistributor {
+	return primary
+}
+
+// Distributors returns all the distributors, the primary one is the first
+func Distributors() []ConfigDistributor {
+	if primary == nil {
+		return nil
+	}
+	r := []ConfigDistributor{primary}
 	for _, cd := range distributors {
-		return cd.Create(kind, project, spec)
+		if cd != primary {
+			r = append(r, cd)
+		}
+	}
+	return r
+}
+
+// Create creates the policy by the primary distributor, then the others
+// create the same policy, including the name generated by the primary one
+func Create(kind, project string, spec []byte) ([]byte, error) {
+	if primary == nil {
+		return nil, nil
+	}
+	id, err := primary.Create(kind, project, spec)
+	if err != nil {
+		return nil, err
+	}
+	others := secondaries()
+	if len(others) == 0 {
+		return id, nil
+	}
+	created, err := getPolicy(primary, kind, string(id), project)
+	if err != nil {
+		return id, &ErrDistribute{Op: "create", ID: string(id), Failures: map[string]error{primary.Name(): err}}
+	}
+	body := marshalPolicy(created)
+	failures := make(map[string]error)
+	succeeded := map[ConfigDistributor]string{primary: string(id)}
+	for _, cd := range others {
+		sid, err := cd.Create(kind, project, body)
+		if err != nil {
+			failures[cd.Name()] = err
+			continue
+		}
+		succeeded[cd] = string(sid)
+	}
+	if len(failures) == 0 {
+		return id, nil
+	}
+	e := &ErrDistribute{Op: "create", ID: string(id), Failures: failures}
+	if config.GetGov().Rollback {
+		for cd, sid := range succeeded {
+			if err := cd.Delete(kind, sid, project); err != nil {
+				log.Error(fmt.Sprintf("rollback creating %s policy %s in distributor %s failed", kind, sid, cd.Name()), err)
+			}
+		}
+		e.RolledBack = true
+		return nil, e
+	}
+	return id, e
+}
+
+// Update updates the policy by the primary distributor, then the others
+// update the policy of the same kind, name and selector, or create it if
+// not exist
+func Update(kind, id, project string, spec []byte) error {
+	if primary == nil {
+		return nil
+	}
+	others := secondaries()
+	var old *gov.Policy
+	if len(others) > 0 {
+		var err error
+		old, err = getPolicy(primary, kind, id, project)
+		if err != nil {
+			return err
+		}
+	}
+	err := primary.Update(kind, id, project, spec)
+	if err != nil || len(others) == 0 {
+		return err
+	}
+	updated, err := getPolicy(primary, kind, id, project)
+	if err != nil {
+		return &ErrDistribute{Op: "update", ID: id, Failures: map[string]error{primary.Name(): err}}
+	}
+	body := marshalPolicy(updated)
+	failures := make(map[string]error)
+	succeeded := map[ConfigDistributor]string{primary: id}
+	created := make(map[ConfigDistributor]string)
+	for _, cd := range others {
+		sid, err := lookup(cd, kind, project, old)
+		switch {
+		case err != nil:
+		case sid == "":
+			sid, err = createPolicy(cd, kind, project, updated)
+			if err == nil {
+				created[cd] = sid
+				continue
+			}
+		default:
+			err = cd.Update(kind, sid, project, body)
+		}
+		if err != nil {
+			failures[cd.Name()] = err
+			continue
+		}
+		succeeded[cd] = sid
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	e := &ErrDistribute{Op: "update", ID: id, Failures: failures}
+	if config.GetGov().Rollback {
+		body = marshalPolicy(old)
+		for cd, sid := range succeeded {
+			if err := cd.Update(kind, sid, project, body); err != nil {
+				log.Error(fmt.Sprintf("rollback updating %s policy %s in distributor %s failed", kind, sid, cd.Name()), err)
+			}
+		}
+		for cd, sid := range created {
+			if err := cd.Delete(kind, sid, project); err != nil {
+				log.Error(fmt.Sprintf("rollback creating %s policy %s in distributor %s failed", kind, sid, cd.Name()), err)
+			}
+		}
+		e.RolledBack = true
+	}
+	return e
+}
+
+// Delete deletes the policy by the primary distributor, then the others
+// delete the policy of the same kind, name and selector. Deletions are not
+// rolled back, it is safe to retry them
+func Delete(kind, id, project string) error {
+	if primary == nil {
+		return nil
+	}
+	others := secondaries()
+	var old *gov.Policy
+	if len(others) > 0 {
+		var err error
+		old, err = getPolicy(primary, kind, id, project)
+		if err != nil {
+			log.Warn(fmt.Sprintf("get %s policy %s failed, only delete it in the primary distributor: %v", kind, id, err))
+			others = nil
+		}
+	}
+	err := primary.Delete(kind, id, project)
+	if err != nil || len(others) == 0 {
+		return err
 	}
-	return nil, nil
+	failures := make(map[string]error)
+	for _, cd := range others {
+		sid, err := lookup(cd, kind, project, old)
+		if err == nil && sid != "" {
+			err = cd.Delete(kind, sid, project)
+		}
+		if err != nil {
+			failures[cd.Name()] = err
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &ErrDistribute{Op: "delete", ID: id, Failures: failures}
 }
 
 func List(kind, project, app, env string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.List(kind, project, app, env)
+	if primary == nil {
+		return nil, nil
 	}
-	return nil, nil
+	return primary.List(kind, project, app, env)
 }
 
 func Display(project, app, env string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.Display(project, app, env)
+	if primary == nil {
+		return nil, nil
 	}
-	return nil, nil
+	return primary.Display(project, app, env)
 }
 
 func Get(kind, id, project string) ([]byte, error) {
-	for _, cd := range distributors {
-		return cd.Get(kind, id, project)
+	if primary == nil {
+		return nil, nil
 	}
-	return nil, nil
+	return primary.Get(kind, id, project)
 }
 
-func Delete(kind, id, project string) error {
-	for _, cd := range distributors {
-		return cd.Delete(kind, id, project)
+// Drift compares the policies of the project in every distributor with the
+// primary one
+func Drift(project string) (*gov.DriftReport, error) {
+	report := &gov.DriftReport{Distributors: []string{}, Drifts: []*gov.Drift{}}
+	if primary == nil {
+		return report, nil
 	}
-	return nil
+	report.Primary = primary.Name()
+	for _, cd := range Distributors() {
+		report.Distributors = append(report.Distributors, cd.Name())
+	}
+	for _, kind := range Kinds {
+		expected, err := listPolicies(primary, kind, project)
+		if err != nil {
+			log.Error(fmt.Sprintf("list %s policies of the primary distributor failed", kind), err)
+			return nil, err
+		}
+		for _, cd := range secondaries() {
+			actual, err := listPolicies(cd, kind, project)
+			if err != nil {
+				report.Drifts = append(report.Drifts, &gov.Drift{
+					Distributor: cd.Name(),
+					Kind:        kind,
+					Reason:      gov.DriftUnavailable,
+					Detail:      err.Error(),
+				})
+				continue
+			}
+			report.Drifts = append(report.Drifts, compare(cd.Name(), kind, expected, actual)...)
+		}
+	}
+	return report, nil
 }
 
-func Update(kind, id, project string, spec []byte) error {
+func compare(name, kind string, expected, actual map[string]*gov.Policy) []*gov.Drift {
+	var r []*gov.Drift
+	for _, key := range sortedKeys(expected) {
+		p, ok := actual[key]
+		switch {
+		case !ok:
+			r = append(r, newDrift(name, kind, expected[key], gov.DriftMissing))
+		case p.Status != expected[key].Status || !reflect.DeepEqual(p.Spec, expected[key].Spec):
+			r = append(r, newDrift(name, kind, expected[key], gov.DriftDifferent))
+		}
+	}
+	for _, key := range sortedKeys(actual) {
+		if _, ok := expected[key]; !ok {
+			r = append(r, newDrift(name, kind, actual[key], gov.DriftUnexpected))
+		}
+	}
+	return r
+}
+
+func newDrift(name, kind string, p *gov.Policy, reason string) *gov.Drift {
+	d := &gov.Drift{Distributor: name, Kind: kind, Reason: reason}
+	if p.GovernancePolicy != nil {
+		d.Name = p.Name
+		if p.Selector != nil {
+			d.App, d.Environment = p.Selector.App, p.Selector.Environment
+		}
+	}
+	return d
+}
+
+func secondaries() []ConfigDistributor {
+	r := make([]ConfigDistributor, 0, len(distributors))
 	for _, cd := range distributors {
-		return cd.Update(kind, id, project, spec)
+		if cd != primary {
+			r = append(r, cd)
+		}
 	}
-	return nil
+	return r
+}
+
+func getPolicy(cd ConfigDistributor, kind, id, project string) (*gov.Policy, error) {
+	b, err := cd.Get(kind, id, project)
+	if err != nil {
+		return nil, err
+	}
+	p := &gov.Policy{}
+	if len(b) > 0 {
+		err = json.Unmarshal(b, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.GovernancePolicy == nil {
+		return nil, fmt.Errorf("%s policy %s does not exist in distributor %s", kind, id, cd.Name())
+	}
+	return p, nil
+}
+
+func createPolicy(cd ConfigDistributor, kind, project string, p *gov.Policy) (string, error) {
+	id, err := cd.Create(kind, project, marshalPolicy(p))
+	return string(id), err
+}
+
+// lookup returns the id of the policy of the same name and selector as p
+// in cd, it is empty if not exist
+func lookup(cd ConfigDistributor, kind, project string, p *gov.Policy) (string, error) {
+	app, env := "", ""
+	if p.Selector != nil {
+		app, env = p.Selector.App, p.Selector.Environment
+	}
+	b, err := cd.List(kind, project, app, env)
+	if err != nil {
+		return "", err
+	}
+	var policies []*gov.Policy
+	if len(b) > 0 {
+		err = json.Unmarshal(b, &policies)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, item := range policies {
+		if item.GovernancePolicy != nil && policyKey(item) == policyKey(p) {
+			return item.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func listPolicies(cd ConfigDistributor, kind, project string) (map[string]*gov.Policy, error) {
+	b, err := cd.List(kind, project, "", EnvAll)
+	if err != nil {
+		return nil, err
+	}
+	var policies []*gov.Policy
+	if len(b) > 0 {
+		err = json.Unmarshal(b, &policies)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r := make(map[string]*gov.Policy, len(policies))
+	for _, p := range policies {
+		if p.GovernancePolicy != nil {
+			r[policyKey(p)] = p
+		}
+	}
+	return r, nil
+}
+
+// marshalPolicy drops the id and the times of p which are generated by
+// the distributors
+func marshalPolicy(p *gov.Policy) []byte {
+	b, _ := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     p.Name,
+			Status:   p.Status,
+			Selector: p.Selector,
+		},
+		Kind: p.Kind,
+		Spec: p.Spec,
+	})
+	return b
+}
+
+func policyKey(p *gov.Policy) string {
+	if p.Selector == nil {
+		return "//" + p.Name
+	}
+	return p.Selector.App + "/" + p.Selector.Environment + "/" + p.Name
+}
+
+func sortedKeys(m map[string]*gov.Policy) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
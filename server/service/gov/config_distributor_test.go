@@ -19,6 +19,7 @@ package gov_test
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/apache/servicecomb-service-center/pkg/gov"
@@ -33,6 +34,7 @@ const MockKind = "default"
 const MatchGroup = "match-group"
 const MockEnv = ""
 const MockApp = ""
+const RetryKind = "retry"
 
 var id = ""
 
@@ -130,3 +132,173 @@ func TestDelete(t *testing.T) {
 	res, _ := svc.Get(MockKind, id, Project)
 	assert.Nil(t, res)
 }
+
+type brokenDistributor struct {
+	svc.ConfigDistributor
+	name string
+}
+
+func (d *brokenDistributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+func (d *brokenDistributor) Update(kind, id, project string, spec []byte) error {
+	return errors.New("broken")
+}
+func (d *brokenDistributor) List(kind, project, app, env string) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+func (d *brokenDistributor) Name() string {
+	return d.name
+}
+
+func initDistributors(t *testing.T, primary string, rollback bool, opts ...config.DistributorOptions) {
+	old := config.App.Gov
+	config.App.Gov = &config.Gov{DistOptions: opts, Primary: primary, Rollback: rollback}
+	assert.NoError(t, svc.Init())
+	t.Cleanup(func() {
+		config.App.Gov = old
+		assert.NoError(t, svc.Init())
+	})
+}
+
+func policy(t *testing.T, name string, retryNext int) []byte {
+	b, err := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: &gov.Selector{App: MockApp, Environment: MockEnv},
+		},
+		Spec: &gov.LBSpec{RetryNext: retryNext, MarkerName: name},
+	})
+	assert.NoError(t, err)
+	return b
+}
+
+func listNames(t *testing.T, cd svc.ConfigDistributor) []string {
+	b, err := cd.List(RetryKind, Project, MockApp, MockEnv)
+	assert.NoError(t, err)
+	var policies []*gov.Policy
+	assert.NoError(t, json.Unmarshal(b, &policies))
+	names := make([]string, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestFanOut(t *testing.T) {
+	svc.InstallDistributor("broken", func(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+		return &brokenDistributor{name: opts.Name}, nil
+	})
+
+	t.Run("configure an unknown primary, should fail", func(t *testing.T) {
+		old := config.App.Gov
+		defer func() {
+			config.App.Gov = old
+			assert.NoError(t, svc.Init())
+		}()
+		config.App.Gov = &config.Gov{
+			DistOptions: []config.DistributorOptions{{Name: "a", Type: "mock"}},
+			Primary:     "unknown",
+		}
+		assert.Error(t, svc.Init())
+	})
+	t.Run("configure two distributors saving in the datasource, should fail", func(t *testing.T) {
+		old := config.App.Gov
+		defer func() {
+			config.App.Gov = old
+			assert.NoError(t, svc.Init())
+		}()
+		config.App.Gov = &config.Gov{
+			DistOptions: []config.DistributorOptions{
+				{Name: "a", Type: svc.ConfigDistributorBuildin},
+				{Name: "b", Type: svc.ConfigDistributorIstio},
+			},
+		}
+		assert.Error(t, svc.Init())
+	})
+	t.Run("write to all distributors, should be consistent", func(t *testing.T) {
+		initDistributors(t, "b", false,
+			config.DistributorOptions{Name: "a", Type: "mock"},
+			config.DistributorOptions{Name: "b", Type: "mock"})
+		assert.Equal(t, "b", svc.Primary().Name())
+		others := svc.Distributors()[1]
+		assert.Equal(t, "a", others.Name())
+
+		id, err := svc.Create(RetryKind, Project, policy(t, "fan-out", 1))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fan-out"}, listNames(t, others))
+
+		err = svc.Update(RetryKind, string(id), Project, policy(t, "fan-out", 2))
+		assert.NoError(t, err)
+		report, err := svc.Drift(Project)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", report.Primary)
+		assert.Empty(t, report.Drifts)
+
+		err = svc.Delete(RetryKind, string(id), Project)
+		assert.NoError(t, err)
+		assert.Empty(t, listNames(t, others))
+	})
+	t.Run("a distributor failed, should report it", func(t *testing.T) {
+		initDistributors(t, "", false,
+			config.DistributorOptions{Name: "a", Type: "mock"},
+			config.DistributorOptions{Name: "c", Type: "broken"})
+
+		id, err := svc.Create(RetryKind, Project, policy(t, "partial", 1))
+		assert.NotEmpty(t, id)
+		var de *svc.ErrDistribute
+		assert.True(t, errors.As(err, &de))
+		assert.False(t, de.RolledBack)
+		assert.True(t, svc.Applied(err))
+		assert.Error(t, de.Failures["c"])
+		assert.Equal(t, []string{"partial"}, listNames(t, svc.Primary()))
+
+		report, err := svc.Drift(Project)
+		assert.NoError(t, err)
+		assert.Equal(t, gov.DriftUnavailable, report.Drifts[0].Reason)
+		assert.Equal(t, "c", report.Drifts[0].Distributor)
+	})
+	t.Run("a distributor failed with rollback, should revert the others", func(t *testing.T) {
+		initDistributors(t, "", true,
+			config.DistributorOptions{Name: "a", Type: "mock"},
+			config.DistributorOptions{Name: "b", Type: "mock"},
+			config.DistributorOptions{Name: "c", Type: "broken"})
+
+		id, err := svc.Create(RetryKind, Project, policy(t, "rollback", 1))
+		assert.Empty(t, id)
+		var de *svc.ErrDistribute
+		assert.True(t, errors.As(err, &de))
+		assert.True(t, de.RolledBack)
+		assert.False(t, svc.Applied(err))
+		for _, cd := range svc.Distributors()[:2] {
+			assert.Empty(t, listNames(t, cd), cd.Name())
+		}
+	})
+	t.Run("the secondary differs from the primary, should report drifts", func(t *testing.T) {
+		initDistributors(t, "", false,
+			config.DistributorOptions{Name: "a", Type: "mock"},
+			config.DistributorOptions{Name: "b", Type: "mock"})
+		secondary := svc.Distributors()[1]
+
+		_, err := svc.Primary().Create(RetryKind, Project, policy(t, "missing", 1))
+		assert.NoError(t, err)
+		_, err = secondary.Create(RetryKind, Project, policy(t, "unexpected", 1))
+		assert.NoError(t, err)
+		_, err = svc.Primary().Create(RetryKind, Project, policy(t, "different", 1))
+		assert.NoError(t, err)
+		_, err = secondary.Create(RetryKind, Project, policy(t, "different", 2))
+		assert.NoError(t, err)
+
+		report, err := svc.Drift(Project)
+		assert.NoError(t, err)
+		reasons := make(map[string]string)
+		for _, d := range report.Drifts {
+			reasons[d.Name] = d.Reason
+		}
+		assert.Equal(t, map[string]string{
+			"missing":    gov.DriftMissing,
+			"unexpected": gov.DriftUnexpected,
+			"different":  gov.DriftDifferent,
+		}, reasons)
+	})
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+	svc "github.com/apache/servicecomb-service-center/server/service/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/buildin"
+)
+
+const (
+	// ManifestScheme is the prefix of the endpoint to write the manifests
+	// to a directory, other endpoints are the kubeconfig path, and an empty
+	// one means in cluster config
+	ManifestScheme   = "file://"
+	DefaultNamespace = "default"
+)
+
+// Distributor persists the policies in the datasource like the buildin one,
+// and translates all the policies of the project to the istio resources
+// after every change
+type Distributor struct {
+	*buildin.Distributor
+	target Target
+	lock   sync.Mutex
+}
+
+func NewDistributor(name string, target Target) *Distributor {
+	return &Distributor{Distributor: buildin.NewDistributor(name), target: target}
+}
+
+func (d *Distributor) Create(kind, project string, spec []byte) ([]byte, error) {
+	id, err := d.Distributor.Create(kind, project, spec)
+	if err != nil {
+		return nil, err
+	}
+	return id, d.Sync(context.TODO(), project)
+}
+
+func (d *Distributor) Update(kind, id, project string, spec []byte) error {
+	err := d.Distributor.Update(kind, id, project, spec)
+	if err != nil {
+		return err
+	}
+	return d.Sync(context.TODO(), project)
+}
+
+func (d *Distributor) Delete(kind, id, project string) error {
+	err := d.Distributor.Delete(kind, id, project)
+	if err != nil {
+		return err
+	}
+	return d.Sync(context.TODO(), project)
+}
+
+func (d *Distributor) Type() string {
+	return svc.ConfigDistributorIstio
+}
+
+// Sync translates all the policies of project and applies the resources
+// to the target
+func (d *Distributor) Sync(ctx context.Context, project string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	b, err := d.Distributor.List("", project, "", buildin.EnvAll)
+	if err != nil {
+		return err
+	}
+	var policies []*gov.Policy
+	err = json.Unmarshal(b, &policies)
+	if err != nil {
+		return err
+	}
+	err = d.target.Apply(ctx, project, Translate(project, policies))
+	if err != nil {
+		log.Error(fmt.Sprintf("distribute policies of project %s to istio failed", project), err)
+		return err
+	}
+	return nil
+}
+
+func newTarget(opts config.DistributorOptions) (Target, error) {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	if strings.HasPrefix(opts.Endpoint, ManifestScheme) {
+		return NewManifestTarget(strings.TrimPrefix(opts.Endpoint, ManifestScheme), namespace), nil
+	}
+	cfg, err := clientcmd.BuildConfigFromFlags("", opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewKubeTarget(client, namespace), nil
+}
+
+func new(opts config.DistributorOptions) (svc.ConfigDistributor, error) {
+	target, err := newTarget(opts)
+	if err != nil {
+		log.Error("can not create istio target", err)
+		return nil, err
+	}
+	return NewDistributor(opts.Name, target), nil
+}
+
+func init() {
+	svc.InstallDistributor(svc.ConfigDistributorIstio, new)
+}
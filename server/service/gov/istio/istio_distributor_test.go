@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/server/service/gov/istio"
+	_ "github.com/apache/servicecomb-service-center/test"
+)
+
+const (
+	project   = "TestIstioDistributor"
+	namespace = "servicecomb"
+	host      = "payment"
+)
+
+func marshal(t *testing.T, name string, spec interface{}) []byte {
+	b, err := json.Marshal(&gov.Policy{
+		GovernancePolicy: &gov.GovernancePolicy{
+			Name:     name,
+			Selector: &gov.Selector{App: "app", Environment: "production"},
+		},
+		Spec: spec,
+	})
+	assert.NoError(t, err)
+	return b
+}
+
+func get(t *testing.T, client *fake.FakeDynamicClient, kind string) *unstructured.Unstructured {
+	obj, err := client.Resource(istio.Resources[kind]).Namespace(namespace).
+		Get(context.TODO(), istio.ObjectName(project, host), metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+func TestKubeTarget(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	d := istio.NewDistributor("istio", istio.NewKubeTarget(client, namespace))
+
+	var groupID string
+	t.Run("create policies, should apply the resources", func(t *testing.T) {
+		id, err := d.Create("match-group", project, marshal(t, "pay", map[string]interface{}{
+			"alias": "",
+			"matches": []interface{}{map[string]interface{}{
+				"name":        "orders",
+				"serviceName": host,
+				"apiPath":     map[string]interface{}{"prefix": "/orders"},
+				"method":      []interface{}{"GET", "POST"},
+			}},
+		}))
+		assert.NoError(t, err)
+		groupID = string(id)
+		_, err = d.Create("retry", project, marshal(t, "pay", map[string]interface{}{
+			"maxAttempts": 2, "retryOnResponseStatus": []interface{}{502, 503},
+		}))
+		assert.NoError(t, err)
+		_, err = d.Create("circuit-breaker", project, marshal(t, "pay", map[string]interface{}{
+			"minimumNumberOfCalls": 10, "waitDurationInOpenState": 30000,
+		}))
+		assert.NoError(t, err)
+		_, err = d.Create("loadbalancer", project, marshal(t, "pay", map[string]interface{}{"rule": "Random"}))
+		assert.NoError(t, err)
+		_, err = d.Create("rate-limiting", project, marshal(t, "pay", map[string]interface{}{
+			"rate": 100, "limitRefreshPeriod": "500ms",
+		}))
+		assert.NoError(t, err)
+
+		vs := get(t, client, istio.KindVirtualService)
+		if assert.NotNil(t, vs) {
+			routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+			assert.Equal(t, 2, len(routes))
+			route := routes[0].(map[string]interface{})
+			assert.Equal(t, "pay.orders", route["name"])
+			match := route["match"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{"prefix": "/orders"}, match["uri"])
+			assert.Equal(t, map[string]interface{}{"regex": "GET|POST"}, match["method"])
+			attempts, _, _ := unstructured.NestedInt64(route, "retries", "attempts")
+			assert.Equal(t, int64(2), attempts)
+			retryOn, _, _ := unstructured.NestedString(route, "retries", "retryOn")
+			assert.Equal(t, "connect-failure,refused-stream,502,503", retryOn)
+			assert.Nil(t, routes[1].(map[string]interface{})["match"])
+		}
+		dr := get(t, client, istio.KindDestinationRule)
+		if assert.NotNil(t, dr) {
+			simple, _, _ := unstructured.NestedString(dr.Object, "spec", "trafficPolicy", "loadBalancer", "simple")
+			assert.Equal(t, "RANDOM", simple)
+			_, found, _ := unstructured.NestedFieldNoCopy(dr.Object, "spec", "trafficPolicy", "outlierDetection", "consecutive5xxErrors")
+			assert.False(t, found)
+			ejection, _, _ := unstructured.NestedString(dr.Object, "spec", "trafficPolicy", "outlierDetection", "baseEjectionTime")
+			assert.Equal(t, "30s", ejection)
+		}
+		ef := get(t, client, istio.KindEnvoyFilter)
+		if assert.NotNil(t, ef) {
+			patches, _, _ := unstructured.NestedSlice(ef.Object, "spec", "configPatches")
+			interval, _, _ := unstructured.NestedString(patches[0].(map[string]interface{}),
+				"patch", "value", "typed_config", "value", "token_bucket", "fill_interval")
+			assert.Equal(t, "0.5s", interval)
+			assert.Equal(t, istio.ManagedBy, ef.GetLabels()[istio.LabelManagedBy])
+		}
+	})
+	t.Run("delete match group, should remove the resources", func(t *testing.T) {
+		err := d.Delete("match-group", groupID, project)
+		assert.NoError(t, err)
+		for _, kind := range istio.Kinds {
+			assert.Nil(t, get(t, client, kind), kind)
+		}
+	})
+}
+
+func TestManifestTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "istio")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	target := istio.NewManifestTarget(dir, namespace)
+	d := istio.NewDistributor("istio", target)
+	manifest := filepath.Join(target.Dir(project),
+		"virtualservice-"+istio.ObjectName(project, host)+istio.ManifestExt)
+
+	id, err := d.Create("match-group", project, marshal(t, "manifest", map[string]interface{}{
+		"alias": "",
+		"matches": []interface{}{map[string]interface{}{
+			"name":        "health",
+			"serviceName": host,
+			"headers":     map[string]interface{}{"user": map[string]interface{}{"contains": "ja"}},
+		}},
+	}))
+	assert.NoError(t, err)
+	b, err := ioutil.ReadFile(manifest)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "kind: VirtualService")
+	assert.Contains(t, string(b), "namespace: "+namespace)
+	assert.Contains(t, string(b), "regex: .*ja.*")
+
+	assert.NoError(t, d.Delete("match-group", string(id), project))
+	_, err = os.Stat(manifest)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("match without serviceName, should be ignored", func(t *testing.T) {
+		objs := istio.Translate(project, []*gov.Policy{{
+			GovernancePolicy: &gov.GovernancePolicy{Name: "g", Status: "enabled"},
+			Kind:             "match-group",
+			Spec: map[string]interface{}{"matches": []interface{}{
+				map[string]interface{}{"name": "m", "method": []interface{}{"GET"}},
+			}},
+		}})
+		assert.Empty(t, objs)
+	})
+	t.Run("disabled policies, should be ignored", func(t *testing.T) {
+		objs := istio.Translate(project, []*gov.Policy{{
+			GovernancePolicy: &gov.GovernancePolicy{Name: "g", Status: "disabled"},
+			Kind:             "match-group",
+			Spec: map[string]interface{}{"matches": []interface{}{
+				map[string]interface{}{"name": "m", "serviceName": host, "method": []interface{}{"GET"}},
+			}},
+		}})
+		assert.Empty(t, objs)
+	})
+}
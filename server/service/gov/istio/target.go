@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const ManifestExt = ".yaml"
+
+// Target receives the istio resources translated from the policies of a
+// project, the resources of the project which are not in objs should be
+// removed
+type Target interface {
+	Apply(ctx context.Context, project string, objs []*unstructured.Unstructured) error
+}
+
+// KubeTarget applies the resources to kubernetes
+type KubeTarget struct {
+	client    dynamic.Interface
+	namespace string
+}
+
+func NewKubeTarget(client dynamic.Interface, namespace string) *KubeTarget {
+	return &KubeTarget{client: client, namespace: namespace}
+}
+
+func (t *KubeTarget) Apply(ctx context.Context, project string, objs []*unstructured.Unstructured) error {
+	desired := make(map[string][]*unstructured.Unstructured, len(Kinds))
+	for _, obj := range objs {
+		obj.SetNamespace(t.namespace)
+		desired[obj.GetKind()] = append(desired[obj.GetKind()], obj)
+	}
+	selector := labels.SelectorFromSet(Labels(project)).String()
+	for _, kind := range Kinds {
+		client := t.client.Resource(Resources[kind]).Namespace(t.namespace)
+		list, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			log.Error(fmt.Sprintf("list %s of project %s failed", kind, project), err)
+			return err
+		}
+		existing := make(map[string]string, len(list.Items))
+		for _, item := range list.Items {
+			existing[item.GetName()] = item.GetResourceVersion()
+		}
+		for _, obj := range desired[kind] {
+			version, ok := existing[obj.GetName()]
+			delete(existing, obj.GetName())
+			if ok {
+				obj.SetResourceVersion(version)
+				_, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+			} else {
+				_, err = client.Create(ctx, obj, metav1.CreateOptions{})
+			}
+			if err != nil {
+				log.Error(fmt.Sprintf("apply %s %s/%s failed", kind, t.namespace, obj.GetName()), err)
+				return err
+			}
+		}
+		for name := range existing {
+			err = client.Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				log.Error(fmt.Sprintf("delete %s %s/%s failed", kind, t.namespace, name), err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ManifestTarget writes the resources as yaml manifests to the
+// sub directory of the project, they can be applied by kubectl or GitOps
+// tools
+type ManifestTarget struct {
+	dir       string
+	namespace string
+}
+
+func NewManifestTarget(dir, namespace string) *ManifestTarget {
+	return &ManifestTarget{dir: dir, namespace: namespace}
+}
+
+func (t *ManifestTarget) Apply(_ context.Context, project string, objs []*unstructured.Unstructured) error {
+	dir := t.Dir(project)
+	err := os.MkdirAll(dir, 0750)
+	if err != nil {
+		log.Error(fmt.Sprintf("create manifest dir %s failed", dir), err)
+		return err
+	}
+	desired := make(map[string]bool, len(objs))
+	for _, obj := range objs {
+		if t.namespace != "" {
+			obj.SetNamespace(t.namespace)
+		}
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		file := strings.ToLower(obj.GetKind()) + "-" + obj.GetName() + ManifestExt
+		desired[file] = true
+		err = ioutil.WriteFile(filepath.Join(dir, file), b, 0640)
+		if err != nil {
+			log.Error(fmt.Sprintf("write manifest %s failed", file), err)
+			return err
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ManifestExt || desired[f.Name()] {
+			continue
+		}
+		err = os.Remove(filepath.Join(dir, f.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			log.Error(fmt.Sprintf("remove manifest %s failed", f.Name()), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Dir returns the directory of the manifests of project
+func (t *ManifestTarget) Dir(project string) string {
+	return filepath.Join(t.dir, Labels(project)[LabelProject])
+}
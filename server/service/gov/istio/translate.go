@@ -0,0 +1,478 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/service/gov/buildin"
+)
+
+const (
+	Group   = "networking.istio.io"
+	Version = "v1alpha3"
+
+	KindVirtualService  = "VirtualService"
+	KindDestinationRule = "DestinationRule"
+	KindEnvoyFilter     = "EnvoyFilter"
+
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	LabelProject   = "servicecomb.apache.org/project"
+	ManagedBy      = "servicecomb-service-center"
+
+	ServiceName = "serviceName"
+)
+
+// Kinds are the kinds of the istio resources translated from the policies
+var Kinds = []string{KindVirtualService, KindDestinationRule, KindEnvoyFilter}
+
+// Resources maps the Kinds to the resources of kubernetes api
+var Resources = map[string]schema.GroupVersionResource{
+	KindVirtualService:  {Group: Group, Version: Version, Resource: "virtualservices"},
+	KindDestinationRule: {Group: Group, Version: Version, Resource: "destinationrules"},
+	KindEnvoyFilter:     {Group: Group, Version: Version, Resource: "envoyfilters"},
+}
+
+var lbRules = map[string]string{
+	gov.LBRuleRoundRobin: "ROUND_ROBIN",
+	gov.LBRuleRandom:     "RANDOM",
+	gov.LBRuleLeastConn:  "LEAST_CONN",
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// host collects the policies of the match groups targeting the same
+// service, istio applies the traffic policy to the whole host
+type host struct {
+	name          string
+	routes        []interface{}
+	trafficPolicy map[string]interface{}
+	rateLimit     map[string]interface{}
+}
+
+// Translate converts the enabled policies of project to the istio resources.
+// The serviceName of the matches is the host of the resources, the matches
+// without serviceName are ignored
+func Translate(project string, policies []*gov.Policy) []*unstructured.Unstructured {
+	groups := make([]*gov.Policy, 0, len(policies))
+	bound := make(map[string]map[string]*gov.Policy)
+	for _, policy := range policies {
+		if policy.GovernancePolicy == nil || policy.Status != buildin.StatusEnabled {
+			continue
+		}
+		if policy.Kind == buildin.KindMatchGroup {
+			groups = append(groups, policy)
+			continue
+		}
+		key := groupKey(policy)
+		if bound[key] == nil {
+			bound[key] = make(map[string]*gov.Policy)
+		}
+		bound[key][policy.Kind] = policy
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groupKey(groups[i]) < groupKey(groups[j])
+	})
+
+	hosts := make(map[string]*host)
+	var names []string
+	for _, group := range groups {
+		kinds := bound[groupKey(group)]
+		for _, match := range matchesOf(group) {
+			name, _ := match[ServiceName].(string)
+			if name == "" {
+				log.Warn(fmt.Sprintf("match group %s has a match without %s, skip it", group.Name, ServiceName))
+				continue
+			}
+			h, ok := hosts[name]
+			if !ok {
+				h = &host{name: name, trafficPolicy: make(map[string]interface{})}
+				hosts[name] = h
+				names = append(names, name)
+			}
+			h.routes = append(h.routes, httpRoute(group, match, name, kinds["retry"]))
+			h.merge(kinds)
+		}
+	}
+	sort.Strings(names)
+
+	var objs []*unstructured.Unstructured
+	for _, name := range names {
+		objs = append(objs, hosts[name].objects(project)...)
+	}
+	return objs
+}
+
+// merge sets the traffic policy of host, the first match group wins if
+// more than one group set the same field
+func (h *host) merge(kinds map[string]*gov.Policy) {
+	if p := kinds["circuit-breaker"]; p != nil && h.trafficPolicy["outlierDetection"] == nil {
+		if od := outlierDetection(p.Name, specOf(p)); od != nil {
+			h.trafficPolicy["outlierDetection"] = od
+		}
+	}
+	if p := kinds["bulkhead"]; p != nil && h.trafficPolicy["connectionPool"] == nil {
+		if pool := connectionPool(specOf(p)); pool != nil {
+			h.trafficPolicy["connectionPool"] = pool
+		}
+	}
+	if p := kinds["loadbalancer"]; p != nil && h.trafficPolicy["loadBalancer"] == nil {
+		if lb := loadBalancer(specOf(p)); lb != nil {
+			h.trafficPolicy["loadBalancer"] = lb
+		}
+	}
+	if p := kinds["rate-limiting"]; p != nil && h.rateLimit == nil {
+		h.rateLimit = tokenBucket(specOf(p))
+	}
+}
+
+func (h *host) objects(project string) []*unstructured.Unstructured {
+	// the requests not matched by any group should be routed as usual
+	routes := append(h.routes, map[string]interface{}{
+		"route": []interface{}{destination(h.name)},
+	})
+	objs := []*unstructured.Unstructured{
+		newObject(KindVirtualService, project, h.name, map[string]interface{}{
+			"hosts": []interface{}{h.name},
+			"http":  routes,
+		}),
+	}
+	if len(h.trafficPolicy) > 0 {
+		objs = append(objs, newObject(KindDestinationRule, project, h.name, map[string]interface{}{
+			"host":          h.name,
+			"trafficPolicy": h.trafficPolicy,
+		}))
+	}
+	if h.rateLimit != nil {
+		objs = append(objs, newObject(KindEnvoyFilter, project, h.name, envoyFilter(h.name, h.rateLimit)))
+	}
+	return objs
+}
+
+func newObject(kind, project, host string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": spec,
+	}}
+	obj.SetAPIVersion(Group + "/" + Version)
+	obj.SetKind(kind)
+	obj.SetName(ObjectName(project, host))
+	obj.SetLabels(Labels(project))
+	return obj
+}
+
+// ObjectName returns the name of the resources of host, the hash of project
+// avoids the conflicts between the projects in the same namespace
+func ObjectName(project, host string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(project))
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(host), "-"), "-.")
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return fmt.Sprintf("%s-%08x", name, h.Sum32())
+}
+
+// Labels returns the labels of the resources managed by service center
+func Labels(project string) map[string]string {
+	value := strings.Trim(invalidLabelChars.ReplaceAllString(project, "-"), "-._")
+	if len(value) > 63 {
+		value = strings.Trim(value[:63], "-._")
+	}
+	return map[string]string{
+		LabelManagedBy: ManagedBy,
+		LabelProject:   value,
+	}
+}
+
+func httpRoute(group *gov.Policy, match map[string]interface{}, host string, retry *gov.Policy) map[string]interface{} {
+	route := map[string]interface{}{
+		"name":  group.Name,
+		"match": []interface{}{matchRequest(match)},
+		"route": []interface{}{destination(host)},
+	}
+	if name, _ := match["name"].(string); name != "" {
+		route["name"] = group.Name + "." + name
+	}
+	if retry != nil {
+		route["retries"] = retries(specOf(retry))
+	}
+	return route
+}
+
+func matchRequest(match map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{})
+	if apiPath, ok := match["apiPath"].(map[string]interface{}); ok {
+		if uri := stringMatch(apiPath); uri != nil {
+			r["uri"] = uri
+		}
+	}
+	if headers, ok := match["headers"].(map[string]interface{}); ok {
+		h := make(map[string]interface{}, len(headers))
+		for name, val := range headers {
+			operators, ok := val.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if m := stringMatch(operators); m != nil {
+				h[name] = m
+			}
+		}
+		if len(h) > 0 {
+			r["headers"] = h
+		}
+	}
+	if methods, ok := match["method"].([]interface{}); ok && len(methods) > 0 {
+		values := make([]string, 0, len(methods))
+		for _, method := range methods {
+			if s, ok := method.(string); ok {
+				values = append(values, s)
+			}
+		}
+		if len(values) == 1 {
+			r["method"] = map[string]interface{}{"exact": values[0]}
+		} else if len(values) > 1 {
+			r["method"] = map[string]interface{}{"regex": strings.Join(values, "|")}
+		}
+	}
+	return r
+}
+
+// stringMatch converts the operators of servicecomb to the StringMatch of
+// istio, the unsupported operators like compare are ignored
+func stringMatch(operators map[string]interface{}) map[string]interface{} {
+	for _, op := range []string{"exact", "prefix", "regex", "suffix", "contains"} {
+		val, ok := operators[op].(string)
+		if !ok {
+			continue
+		}
+		switch op {
+		case "suffix":
+			return map[string]interface{}{"regex": ".*" + regexp.QuoteMeta(val)}
+		case "contains":
+			return map[string]interface{}{"regex": ".*" + regexp.QuoteMeta(val) + ".*"}
+		default:
+			return map[string]interface{}{op: val}
+		}
+	}
+	return nil
+}
+
+func destination(host string) map[string]interface{} {
+	return map[string]interface{}{
+		"destination": map[string]interface{}{"host": host},
+	}
+}
+
+func retries(spec map[string]interface{}) map[string]interface{} {
+	attempts, ok := intOf(spec, "maxAttempts")
+	if !ok {
+		attempts = 3
+	}
+	retryOn := "5xx,connect-failure,refused-stream"
+	if statuses, ok := spec["retryOnResponseStatus"].([]interface{}); ok && len(statuses) > 0 {
+		codes := []string{"connect-failure", "refused-stream"}
+		for _, status := range statuses {
+			switch v := status.(type) {
+			case float64:
+				codes = append(codes, strconv.Itoa(int(v)))
+			case string:
+				codes = append(codes, v)
+			}
+		}
+		retryOn = strings.Join(codes, ",")
+	}
+	return map[string]interface{}{
+		"attempts": attempts,
+		"retryOn":  retryOn,
+	}
+}
+
+// outlierDetection converts the circuit breaker to the outlierDetection of
+// istio. Istio ejects a host by the consecutive errors instead of the rates
+// of a sliding window, so only waitDurationInOpenState has an equivalent,
+// the ejection is triggered by the default consecutive errors of istio
+func outlierDetection(name string, spec map[string]interface{}) map[string]interface{} {
+	var ignored []string
+	for key := range spec {
+		if key != "waitDurationInOpenState" {
+			ignored = append(ignored, key)
+		}
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		log.Warn(fmt.Sprintf("circuit breaker %s has no istio equivalent of %s, ignore them",
+			name, strings.Join(ignored, ", ")))
+	}
+	d, ok := durationOf(spec, "waitDurationInOpenState")
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{"baseEjectionTime": formatDuration(d)}
+}
+
+func connectionPool(spec map[string]interface{}) map[string]interface{} {
+	calls, ok := intOf(spec, "maxConcurrentCalls")
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		"http": map[string]interface{}{"http2MaxRequests": calls},
+	}
+}
+
+func loadBalancer(spec map[string]interface{}) map[string]interface{} {
+	rule, _ := spec["rule"].(string)
+	simple, ok := lbRules[rule]
+	if !ok {
+		log.Warn(fmt.Sprintf("unsupported load balance rule [%s], skip it", rule))
+		return nil
+	}
+	return map[string]interface{}{"simple": simple}
+}
+
+func tokenBucket(spec map[string]interface{}) map[string]interface{} {
+	rate, ok := intOf(spec, "rate")
+	if !ok {
+		return nil
+	}
+	period, ok := durationOf(spec, "limitRefreshPeriod")
+	if !ok {
+		period = time.Second
+	}
+	return map[string]interface{}{
+		"max_tokens":      rate,
+		"tokens_per_fill": rate,
+		"fill_interval":   formatDuration(period),
+	}
+}
+
+// envoyFilter inserts a local rate limit filter into the inbound http
+// filters of the workloads of host
+func envoyFilter(host string, bucket map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"workloadSelector": map[string]interface{}{
+			"labels": map[string]interface{}{"app": strings.SplitN(host, ".", 2)[0]},
+		},
+		"configPatches": []interface{}{
+			map[string]interface{}{
+				"applyTo": "HTTP_FILTER",
+				"match": map[string]interface{}{
+					"context": "SIDECAR_INBOUND",
+					"listener": map[string]interface{}{
+						"filterChain": map[string]interface{}{
+							"filter": map[string]interface{}{
+								"name":      "envoy.filters.network.http_connection_manager",
+								"subFilter": map[string]interface{}{"name": "envoy.filters.http.router"},
+							},
+						},
+					},
+				},
+				"patch": map[string]interface{}{
+					"operation": "INSERT_BEFORE",
+					"value": map[string]interface{}{
+						"name": "envoy.filters.http.local_ratelimit",
+						"typed_config": map[string]interface{}{
+							"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+							"type_url": "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit",
+							"value": map[string]interface{}{
+								"stat_prefix":     "http_local_rate_limiter",
+								"token_bucket":    bucket,
+								"filter_enabled":  fullPercent("local_rate_limit_enabled"),
+								"filter_enforced": fullPercent("local_rate_limit_enforced"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fullPercent(runtimeKey string) map[string]interface{} {
+	return map[string]interface{}{
+		"default_value": map[string]interface{}{"numerator": int64(100), "denominator": "HUNDRED"},
+		"runtime_key":   runtimeKey,
+	}
+}
+
+func groupKey(policy *gov.Policy) string {
+	if policy.Selector == nil {
+		return "//" + policy.Name
+	}
+	return policy.Selector.App + "/" + policy.Selector.Environment + "/" + policy.Name
+}
+
+func specOf(policy *gov.Policy) map[string]interface{} {
+	spec, _ := policy.Spec.(map[string]interface{})
+	return spec
+}
+
+func matchesOf(group *gov.Policy) []map[string]interface{} {
+	items, _ := specOf(group)["matches"].([]interface{})
+	r := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if match, ok := item.(map[string]interface{}); ok {
+			r = append(r, match)
+		}
+	}
+	return r
+}
+
+func intOf(spec map[string]interface{}, key string) (int64, bool) {
+	switch v := spec[key].(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// durationOf parses the duration of the policies, a number is in
+// milliseconds
+func durationOf(spec map[string]interface{}, key string) (time.Duration, bool) {
+	switch v := spec[key].(type) {
+	case float64:
+		return time.Duration(v) * time.Millisecond, true
+	case string:
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Duration(ms) * time.Millisecond, true
+		}
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+// formatDuration formats d as the json form of protobuf Duration
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -20,7 +20,15 @@
 package kie
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
 )
 
 type Validator struct {
@@ -31,6 +39,9 @@ type ErrIllegalItem struct {
 	val interface{}
 }
 
+// unknownFieldPrefix prefixes the error of the decoder on an unknown field
+const unknownFieldPrefix = "json: unknown field "
+
 var (
 	methodSet map[string]bool
 )
@@ -48,13 +59,14 @@ func (d *Validator) Validate(kind string, spec interface{}) error {
 	case "rate-limiting":
 		return rateLimitingValidate(spec)
 	case "circuit-breaker":
+		return circuitBreakerValidate(spec)
 	case "bulkhead":
+		return bulkheadValidate(spec)
 	case "loadbalancer":
-		return nil
+		return loadBalancerValidate(spec)
 	default:
 		return &ErrIllegalItem{"not support kind yet", kind}
 	}
-	return nil
 }
 
 func matchValidate(val interface{}) error {
@@ -120,6 +132,203 @@ func rateLimitingValidate(val interface{}) error {
 	return nil
 }
 
+func circuitBreakerValidate(val interface{}) error {
+	err := policyValidate(val)
+	if err != nil {
+		return err
+	}
+	spec := &gov.CircuitBreakerSpec{}
+	err = decodeSpec(val, spec)
+	if err != nil {
+		return err
+	}
+	if err = percentValidate("failureRateThreshold", spec.FailureRateThreshold); err != nil {
+		return err
+	}
+	if err = percentValidate("slowCallRateThreshold", spec.SlowCallRateThreshold); err != nil {
+		return err
+	}
+	if err = durationValidate("slowCallDurationThreshold", spec.SlowCallDurationThreshold, false); err != nil {
+		return err
+	}
+	if err = positiveValidate("minimumNumberOfCalls", spec.MinimumNumberOfCalls); err != nil {
+		return err
+	}
+	if spec.SlidingWindowType != "" && spec.SlidingWindowType != gov.SlidingWindowCount &&
+		spec.SlidingWindowType != gov.SlidingWindowTime {
+		return &ErrIllegalItem{"slidingWindowType must be count or time", spec.SlidingWindowType}
+	}
+	if err = positiveValidate("slidingWindowSize", spec.SlidingWindowSize); err != nil {
+		return err
+	}
+	if err = durationValidate("waitDurationInOpenState", spec.WaitDurationInOpenState, false); err != nil {
+		return err
+	}
+	if err = positiveValidate("permittedNumberOfCallsInHalfOpenState", spec.PermittedNumberOfCallsInHalfOpenState); err != nil {
+		return err
+	}
+	for _, status := range spec.RecordFailureStatus {
+		code, err := status.Int64()
+		if err != nil || code < 100 || code > 599 {
+			return &ErrIllegalItem{"recordFailureStatus must be the http status codes", status}
+		}
+	}
+	if spec.ForceOpen && spec.ForceClosed {
+		return &ErrIllegalItem{"forceOpen and forceClosed can not be both true", val}
+	}
+	return nil
+}
+
+func bulkheadValidate(val interface{}) error {
+	err := policyValidate(val)
+	if err != nil {
+		return err
+	}
+	spec := &gov.BulkheadSpec{}
+	err = decodeSpec(val, spec)
+	if err != nil {
+		return err
+	}
+	if err = positiveValidate("maxConcurrentCalls", spec.MaxConcurrentCalls); err != nil {
+		return err
+	}
+	return durationValidate("maxWaitDuration", spec.MaxWaitDuration, true)
+}
+
+func loadBalancerValidate(val interface{}) error {
+	err := policyValidate(val)
+	if err != nil {
+		return err
+	}
+	spec := &gov.LoadBalancerSpec{}
+	err = decodeSpec(val, spec)
+	if err != nil {
+		return err
+	}
+	for _, rule := range gov.LBRules {
+		if spec.Rule == rule {
+			return nil
+		}
+	}
+	return &ErrIllegalItem{"rule must be one of the " + strings.Join(gov.LBRules, "/"), spec.Rule}
+}
+
+// decodeSpec decodes val to the typed spec, the type errors tell the field
+// decodeSpec decodes the policy to the spec, the unknown fields are
+// rejected so that a misspelled field is not ignored silently
+func decodeSpec(val interface{}, spec interface{}) error {
+	if m, ok := val.(map[string]interface{}); ok {
+		if _, ok := m[Rules]; ok {
+			// the rules are checked by policyValidate
+			fields := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				if k != Rules {
+					fields[k] = v
+				}
+			}
+			val = fields
+		}
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return &ErrIllegalItem{"policy can not be marshaled", val}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(spec)
+	if err == nil {
+		return nil
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+		field := strings.Trim(strings.TrimPrefix(msg, unknownFieldPrefix), `"`)
+		return &ErrIllegalItem{"unknown field " + field, val}
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = failedField(val, reflect.TypeOf(spec).Elem())
+		}
+		if field != "" {
+			return &ErrIllegalItem{field + " must be " + describe(typeErr.Type), typeErr.Value}
+		}
+	}
+	return &ErrIllegalItem{"policy can not cast to " + reflect.TypeOf(spec).Elem().Name(), val}
+}
+
+// failedField returns the field failed to decode, the decoder does not
+// tell it if the error is returned by an Unmarshaler
+func failedField(val interface{}, t reflect.Type) string {
+	spec, ok := val.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b, err := json.Marshal(map[string]interface{}{key: spec[key]})
+		if err != nil {
+			return key
+		}
+		if json.Unmarshal(b, reflect.New(t).Interface()) != nil {
+			return key
+		}
+	}
+	return ""
+}
+
+func describe(t reflect.Type) string {
+	if t == reflect.TypeOf(gov.Duration(0)) {
+		return "a duration in milliseconds or like 10s"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice:
+		return "a list"
+	case reflect.String:
+		if t == reflect.TypeOf(json.Number("")) {
+			return "a number"
+		}
+		return "a string"
+	}
+	return t.String()
+}
+
+func percentValidate(field string, val *float64) error {
+	if val != nil && (*val <= 0 || *val > 100) {
+		return &ErrIllegalItem{field + " must be in (0, 100]", *val}
+	}
+	return nil
+}
+
+func positiveValidate(field string, val *int) error {
+	if val != nil && *val <= 0 {
+		return &ErrIllegalItem{field + " must be positive", *val}
+	}
+	return nil
+}
+
+func durationValidate(field string, val *gov.Duration, zero bool) error {
+	if val == nil {
+		return nil
+	}
+	if *val < 0 {
+		return &ErrIllegalItem{field + " must not be negative", *val}
+	}
+	if *val == 0 && !zero {
+		return &ErrIllegalItem{field + " must be positive", *val}
+	}
+	return nil
+}
+
 func policyValidate(val interface{}) error {
 	spec, ok := val.(map[string]interface{})
 	if !ok {
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/service/gov/kie"
+)
+
+var validator = kie.Validator{}
+
+func assertIllegal(t *testing.T, kind string, spec interface{}, msg string) {
+	err := validator.Validate(kind, spec)
+	if assert.Error(t, err) {
+		_, ok := err.(*kie.ErrIllegalItem)
+		assert.True(t, ok)
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestValidator_CircuitBreaker(t *testing.T) {
+	t.Run("valid spec, should pass", func(t *testing.T) {
+		assert.NoError(t, validator.Validate("circuit-breaker", map[string]interface{}{
+			"failureRateThreshold":      50.0,
+			"slowCallRateThreshold":     100,
+			"slowCallDurationThreshold": "1s",
+			"minimumNumberOfCalls":      10,
+			"slidingWindowType":         "time",
+			"slidingWindowSize":         60,
+			"waitDurationInOpenState":   60000,
+			"recordFailureStatus":       []interface{}{502, "503"},
+		}))
+		assert.NoError(t, validator.Validate("circuit-breaker", map[string]interface{}{}))
+	})
+	t.Run("invalid fields, should tell the field", func(t *testing.T) {
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"failureRateThreshold": 0},
+			"failureRateThreshold must be in (0, 100]")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"slowCallRateThreshold": 101},
+			"slowCallRateThreshold must be in (0, 100]")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"failureRateThreshold": "high"},
+			"failureRateThreshold must be a number")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"minimumNumberOfCalls": 1.5},
+			"minimumNumberOfCalls must be an integer")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"waitDurationInOpenState": "soon"},
+			"waitDurationInOpenState must be a duration")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"waitDurationInOpenState": 0},
+			"waitDurationInOpenState must be positive")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"slidingWindowType": "size"},
+			"slidingWindowType must be count or time")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"recordFailureStatus": []interface{}{99}},
+			"recordFailureStatus must be the http status codes")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"forceOpen": true, "forceClosed": true},
+			"forceOpen and forceClosed can not be both true")
+		assertIllegal(t, "circuit-breaker", map[string]interface{}{"failureRateThreshhold": 50},
+			"unknown field failureRateThreshhold")
+	})
+}
+
+func TestValidator_Bulkhead(t *testing.T) {
+	assert.NoError(t, validator.Validate("bulkhead", map[string]interface{}{
+		"maxConcurrentCalls": 100,
+		"maxWaitDuration":    0,
+	}))
+	assertIllegal(t, "bulkhead", map[string]interface{}{"maxConcurrentCalls": 0}, "maxConcurrentCalls must be positive")
+	assertIllegal(t, "bulkhead", map[string]interface{}{"maxWaitDuration": "-1s"}, "maxWaitDuration must not be negative")
+	assertIllegal(t, "bulkhead", "policy", "policy can not cast to map")
+}
+
+func TestValidator_LoadBalancer(t *testing.T) {
+	assert.NoError(t, validator.Validate("loadbalancer", map[string]interface{}{"rule": "RoundRobin"}))
+	assertIllegal(t, "loadbalancer", map[string]interface{}{}, "rule must be one of the RoundRobin")
+	assertIllegal(t, "loadbalancer", map[string]interface{}{"rule": "Fastest"}, "rule must be one of the RoundRobin")
+	assertIllegal(t, "loadbalancer", map[string]interface{}{"rule": 1}, "rule must be a string")
+	assertIllegal(t, "loadbalancer", map[string]interface{}{"rule": "Random", "retry": 1}, "unknown field retry")
+}
@@ -107,7 +107,8 @@ func (d *Distributor) List(kind, project, app, env string) ([]byte, error) {
 }
 
 func checkPolicy(g *gov.Policy, kind, app, env string) bool {
-	return g.Kind == kind && g.Selector != nil && g.Selector.App == app && g.Selector.Environment == env
+	return g.Kind == kind && g.Selector != nil && (app == "" || g.Selector.App == app) &&
+		(env == svc.EnvAll || g.Selector.Environment == env)
 }
 
 func (d *Distributor) Get(kind, id, project string) ([]byte, error) {
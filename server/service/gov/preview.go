@@ -0,0 +1,258 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	pb "github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const KindMatchGroup = "match-group"
+
+// ErrInvalidMatches means the matches of the match group can not be decoded
+var ErrInvalidMatches = errors.New("invalid matches")
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+var pathParam = regexp.MustCompile(`\{[^/}]+\}`)
+
+// previewMatch accepts both the method of the match groups and the methods
+// of the traffic markers
+type previewMatch struct {
+	APIPaths map[string]string `json:"apiPath,omitempty"`
+	Method   []string          `json:"method,omitempty"`
+	Methods  []string          `json:"methods,omitempty"`
+}
+
+// Preview returns the services selected by the selector of the policy, and
+// their schema operations matched by the match group. The policies of other
+// kinds use the match group of the same name and selector, the headers of
+// the matches are not evaluated
+func Preview(ctx context.Context, kind, project string, p *gov.Policy) (*gov.Preview, error) {
+	if p.GovernancePolicy == nil {
+		p.GovernancePolicy = &gov.GovernancePolicy{}
+	}
+	if p.Selector == nil {
+		p.Selector = &gov.Selector{}
+	}
+	spec := p.Spec
+	if kind != KindMatchGroup {
+		group, err := findMatchGroup(project, p)
+		if err != nil {
+			return nil, err
+		}
+		spec = group.Spec
+	}
+	matches, err := decodeMatches(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := datasource.GetMetadataManager().GetServices(ctx, &pb.GetServicesRequest{})
+	if err != nil {
+		log.Error("list services for preview failed", err)
+		return nil, err
+	}
+	result := &gov.Preview{Services: []*gov.AffectedService{}}
+	for _, service := range resp.Services {
+		if (p.Selector.App != "" && p.Selector.App != service.AppId) ||
+			(p.Selector.Environment != "" && p.Selector.Environment != service.Environment) {
+			continue
+		}
+		affected := &gov.AffectedService{
+			ServiceID:   service.ServiceId,
+			ServiceName: service.ServiceName,
+			AppID:       service.AppId,
+			Environment: service.Environment,
+			Version:     service.Version,
+		}
+		if len(matches) > 0 {
+			affected.Operations = matchOperations(ctx, service.ServiceId, matches)
+		}
+		result.Services = append(result.Services, affected)
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		a, b := result.Services[i], result.Services[j]
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		return a.Version < b.Version
+	})
+	return result, nil
+}
+
+func findMatchGroup(project string, p *gov.Policy) (*gov.Policy, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("match group %s: %w", p.Name, datasource.ErrPolicyNotExist)
+	}
+	b, err := primary.List(KindMatchGroup, project, p.Selector.App, p.Selector.Environment)
+	if err != nil {
+		return nil, err
+	}
+	var groups []*gov.Policy
+	if len(b) > 0 {
+		if err = json.Unmarshal(b, &groups); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range groups {
+		if group.GovernancePolicy != nil && policyKey(group) == policyKey(p) {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("match group %s: %w", p.Name, datasource.ErrPolicyNotExist)
+}
+
+func decodeMatches(spec interface{}) ([]*previewMatch, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	s := &struct {
+		Matches []*previewMatch `json:"matches"`
+	}{}
+	err = json.Unmarshal(b, s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMatches, err)
+	}
+	return s.Matches, nil
+}
+
+func matchOperations(ctx context.Context, serviceID string, matches []*previewMatch) []*gov.Operation {
+	resp, err := datasource.GetMetadataManager().GetAllSchemas(ctx, &pb.GetAllSchemaRequest{
+		ServiceId:  serviceID,
+		WithSchema: true,
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("get the schemas of service %s failed", serviceID), err)
+		return nil
+	}
+	var r []*gov.Operation
+	for _, schema := range resp.Schemas {
+		for _, op := range parseOperations(schema) {
+			for _, match := range matches {
+				if matchMethod(match, op.Method) && matchPath(match.APIPaths, op.Path) {
+					r = append(r, op)
+					break
+				}
+			}
+		}
+	}
+	return r
+}
+
+// parseOperations returns the operations of the OpenAPI schema sorted by
+// the path and the method
+func parseOperations(schema *pb.Schema) []*gov.Operation {
+	doc := &struct {
+		BasePath string                                       `json:"basePath"`
+		Paths    map[string]map[string]map[string]interface{} `json:"paths"`
+	}{}
+	b, err := yaml.YAMLToJSON([]byte(schema.Schema))
+	if err == nil {
+		err = json.Unmarshal(b, doc)
+	}
+	if err != nil {
+		log.Warn(fmt.Sprintf("schema %s is not an OpenAPI document: %v", schema.SchemaId, err))
+		return nil
+	}
+	basePath := strings.TrimSuffix(doc.BasePath, "/")
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var r []*gov.Operation
+	for _, path := range paths {
+		for _, method := range httpMethods {
+			op, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			r = append(r, &gov.Operation{
+				SchemaID:    schema.SchemaId,
+				Method:      strings.ToUpper(method),
+				Path:        basePath + path,
+				OperationID: id,
+			})
+		}
+	}
+	return r
+}
+
+func matchMethod(match *previewMatch, method string) bool {
+	methods := append(match.Method, match.Methods...)
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath matches the path template of the operation with the operators,
+// an exact value matches the template if it is an instance of the template
+func matchPath(operators map[string]string, path string) bool {
+	for op, val := range operators {
+		var ok bool
+		switch op {
+		case "exact":
+			ok = path == val || templateRegexp(path).MatchString(val)
+		case "prefix":
+			ok = strings.HasPrefix(path, val)
+		case "suffix":
+			ok = strings.HasSuffix(path, val)
+		case "contains":
+			ok = strings.Contains(path, val)
+		case "regex":
+			re, err := regexp.Compile(val)
+			ok = err == nil && re.MatchString(path)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func templateRegexp(path string) *regexp.Regexp {
+	parts := pathParam.Split(path, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "$")
+}
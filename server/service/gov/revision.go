@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+// RecordRevision saves the policy in the primary distributor as the latest
+// revision, the diff is against the previous revision
+func RecordRevision(ctx context.Context, action, kind, id, project string) error {
+	if primary == nil {
+		return nil
+	}
+	policy, err := getPolicy(primary, kind, id, project)
+	if err != nil {
+		return err
+	}
+	revisions, err := datasource.GetGovRevisionManager().ListRevision(ctx, project, kind, id)
+	if err != nil {
+		return err
+	}
+	var previous *gov.Policy
+	if len(revisions) > 0 {
+		previous = toRevision(revisions[len(revisions)-1]).Policy
+	}
+	policyJSON := marshalPolicy(policy)
+	diffJSON, err := json.Marshal(Diff(previous, policy))
+	if err != nil {
+		return err
+	}
+	r := &datasource.GovRevision{
+		Project:   project,
+		Kind:      kind,
+		PolicyID:  id,
+		Action:    action,
+		Author:    rbacsvc.UserFromContext(ctx),
+		Timestamp: time.Now().Unix(),
+		Policy:    string(policyJSON),
+		Diff:      string(diffJSON),
+	}
+	err = datasource.GetGovRevisionManager().AddRevision(ctx, r)
+	if err != nil {
+		log.Error(fmt.Sprintf("save the revision of %s policy %s failed", kind, id), err)
+		return err
+	}
+	return nil
+}
+
+// ListRevisions returns the revisions of the policy without the policy
+// snapshots, the latest one is the last
+func ListRevisions(ctx context.Context, kind, id, project string) ([]*gov.Revision, error) {
+	revisions, err := datasource.GetGovRevisionManager().ListRevision(ctx, project, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]*gov.Revision, 0, len(revisions))
+	for _, item := range revisions {
+		revision := toRevision(item)
+		revision.Policy = nil
+		r = append(r, revision)
+	}
+	return r, nil
+}
+
+// GetRevision returns the revision with the policy snapshot
+func GetRevision(ctx context.Context, kind, id, project string, revision int64) (*gov.Revision, error) {
+	item, err := datasource.GetGovRevisionManager().GetRevision(ctx, project, kind, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return toRevision(item), nil
+}
+
+// Rollback updates the policy to the snapshot of the revision by the
+// distributors, and saves it as a new revision
+func Rollback(ctx context.Context, kind, id, project string, revision int64) error {
+	r, err := GetRevision(ctx, kind, id, project, revision)
+	if err != nil {
+		return err
+	}
+	if r.Policy == nil {
+		return fmt.Errorf("revision %d of %s policy %s has no snapshot", revision, kind, id)
+	}
+	err = Update(kind, id, project, marshalPolicy(r.Policy))
+	if Applied(err) {
+		// the failures of the other distributors are reported first
+		if rerr := RecordRevision(ctx, gov.ActionRollback, kind, id, project); rerr != nil && err == nil {
+			return rerr
+		}
+	}
+	return err
+}
+
+// Diff returns the changed fields from old to new, the nested fields of the
+// specs are compared one by one
+func Diff(old, new *gov.Policy) []*gov.Change {
+	before, after := flatten(old), flatten(new)
+	paths := make([]string, 0, len(before)+len(after))
+	for path := range before {
+		paths = append(paths, path)
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	changes := make([]*gov.Change, 0)
+	for _, path := range paths {
+		if !reflect.DeepEqual(before[path], after[path]) {
+			changes = append(changes, &gov.Change{Path: path, Old: before[path], New: after[path]})
+		}
+	}
+	return changes
+}
+
+func flatten(p *gov.Policy) map[string]interface{} {
+	r := make(map[string]interface{})
+	if p == nil {
+		return r
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(marshalPolicy(p), &m); err != nil {
+		return r
+	}
+	flattenInto(r, "", m)
+	return r
+}
+
+func flattenInto(r map[string]interface{}, prefix string, m map[string]interface{}) {
+	for key, val := range m {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := val.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(r, path, nested)
+			continue
+		}
+		r[path] = val
+	}
+}
+
+func toRevision(item *datasource.GovRevision) *gov.Revision {
+	r := &gov.Revision{
+		Revision:  item.Revision,
+		Action:    item.Action,
+		Author:    item.Author,
+		Timestamp: item.Timestamp,
+	}
+	policy := &gov.Policy{}
+	if err := json.Unmarshal([]byte(item.Policy), policy); err == nil && policy.GovernancePolicy != nil {
+		r.Policy = policy
+	} else if err != nil {
+		log.Warn(fmt.Sprintf("revision %d of %s policy %s format invalid", item.Revision, item.Kind, item.PolicyID))
+	}
+	if len(item.Diff) > 0 {
+		_ = json.Unmarshal([]byte(item.Diff), &r.Diff)
+	}
+	return r
+}
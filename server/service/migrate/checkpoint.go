@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+// CheckpointTask is the task name of the checkpoint saved in the source
+const CheckpointTask = "migrate"
+
+// Checkpoint records the last migrated key of each stage, the keys of a
+// stage are migrated in order, so the keys migrated before the recorded
+// one can be skipped when resuming. It is saved in the source datasource,
+// so the migration can be resumed by any replica
+type Checkpoint struct {
+	Target string            `json:"target"`
+	Stages map[string]string `json:"stages"`
+
+	sm datasource.SystemManager
+}
+
+// LoadCheckpoint reads the checkpoint from sm, returns an empty checkpoint
+// if it does not exist or it belongs to another target
+func LoadCheckpoint(ctx context.Context, sm datasource.SystemManager, target string) (*Checkpoint, error) {
+	cp := &Checkpoint{Target: target, Stages: make(map[string]string), sm: sm}
+	data, err := sm.GetCheckpoint(ctx, CheckpointTask)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return cp, nil
+	}
+	saved := &Checkpoint{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, err
+	}
+	if saved.Target != target || saved.Stages == nil {
+		return cp, nil
+	}
+	cp.Stages = saved.Stages
+	return cp, nil
+}
+
+func (cp *Checkpoint) Last(stage string) string {
+	return cp.Stages[stage]
+}
+
+func (cp *Checkpoint) Save(ctx context.Context, stage, key string) error {
+	cp.Stages[stage] = key
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return cp.sm.PutCheckpoint(ctx, CheckpointTask, data)
+}
+
+func (cp *Checkpoint) Remove(ctx context.Context) error {
+	cp.Stages = make(map[string]string)
+	return cp.sm.DeleteCheckpoint(ctx, CheckpointTask)
+}
@@ -0,0 +1,296 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package migrate copies the registry data from one datasource to another
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	LockID = "/cse-sr/lock/migrate"
+
+	maxStageErrors = 100
+)
+
+var (
+	ErrEmptyTarget = errors.New("target datasource kind is required")
+	ErrSameStorage = errors.New("target datasource shares the storage client with the source")
+	ErrMigrating   = errors.New("another migration is running")
+
+	targetsMux sync.Mutex
+	targets    = make(map[datasource.Kind]datasource.DataSource)
+)
+
+// Migrator copies the data of Source to Target stage by stage
+type Migrator struct {
+	Source     datasource.DataSource
+	Target     datasource.DataSource
+	Checkpoint *Checkpoint
+	Stages     []Stage
+}
+
+// Run migrates the data, in dry-run mode, it only reports what would be
+// migrated. After migration, the target is verified against the source
+func (m *Migrator) Run(ctx context.Context, dryRun bool) (*migrate.Report, error) {
+	report := &migrate.Report{DryRun: dryRun}
+	for _, s := range m.Stages {
+		sr, err := m.runStage(ctx, s, dryRun)
+		if sr != nil {
+			report.Stages = append(report.Stages, sr)
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	diffs, err := m.Verify(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Diffs = diffs
+	for _, sr := range report.Stages {
+		if sr.Failed > 0 {
+			// keep the checkpoint to retry the failed items
+			return report, nil
+		}
+	}
+	if m.Checkpoint != nil {
+		if err := m.Checkpoint.Remove(ctx); err != nil {
+			log.Error("remove migration checkpoint failed", err)
+		}
+	}
+	return report, nil
+}
+
+func (m *Migrator) runStage(ctx context.Context, s Stage, dryRun bool) (*migrate.StageReport, error) {
+	src, err := s.Load(ctx, m.Source)
+	if err != nil {
+		return nil, fmt.Errorf("load %s from source failed, %s", s.Name(), err.Error())
+	}
+	dst, err := s.Load(ctx, m.Target)
+	if err != nil {
+		return nil, fmt.Errorf("load %s from target failed, %s", s.Name(), err.Error())
+	}
+
+	var last string
+	if m.Checkpoint != nil && !dryRun {
+		last = m.Checkpoint.Last(s.Name())
+	}
+	keys := orderedKeys(s, src)
+	sr := &migrate.StageReport{Stage: s.Name(), Total: len(src)}
+	if len(last) > 0 {
+		// restart the stage if the last key is gone, the migrated items
+		// are skipped as they are equal
+		for i, key := range keys {
+			if key == last {
+				sr.Resumed = i + 1
+				break
+			}
+		}
+	}
+	failed := false
+	for _, key := range keys[sr.Resumed:] {
+		item, exist := dst[key]
+		switch {
+		case exist && s.Equal(src[key], item):
+			sr.Skipped++
+		case exist && !s.Mergeable():
+			sr.Conflicted++
+		case dryRun && exist:
+			sr.Updated++
+		case dryRun:
+			sr.Created++
+		default:
+			// the quota usages are counted in the global datasource, not the target
+			if err := s.Apply(util.WithSkipQuota(ctx), m.Target, key, src[key], item); err != nil {
+				log.Error(fmt.Sprintf("migrate %s %s failed", s.Name(), key), err)
+				sr.Failed++
+				if len(sr.Errors) < maxStageErrors {
+					sr.Errors = append(sr.Errors, fmt.Sprintf("%s: %s", key, err.Error()))
+				}
+				failed = true
+				continue
+			}
+			if exist {
+				sr.Updated++
+			} else {
+				sr.Created++
+			}
+		}
+		if failed || dryRun || m.Checkpoint == nil {
+			continue
+		}
+		if err := m.Checkpoint.Save(ctx, s.Name(), key); err != nil {
+			return sr, err
+		}
+	}
+
+	if !dryRun && s.Name() == migrate.StageDependency && sr.Created+sr.Updated > 0 {
+		// the dependencies are queued, handle them before verification
+		if err := m.Target.DependencyManager().DependencyHandle(ctx); err != nil {
+			log.Error("handle the migrated dependencies failed", err)
+		}
+	}
+	log.Info(fmt.Sprintf("migrate %s: total %d, created %d, updated %d, skipped %d, resumed %d, conflicted %d, failed %d",
+		sr.Stage, sr.Total, sr.Created, sr.Updated, sr.Skipped, sr.Resumed, sr.Conflicted, sr.Failed))
+	return sr, nil
+}
+
+// Verify compares the target with the source, returns the items missing
+// in the target or different from the source
+func (m *Migrator) Verify(ctx context.Context) ([]*migrate.Diff, error) {
+	var diffs []*migrate.Diff
+	for _, s := range m.Stages {
+		src, err := s.Load(ctx, m.Source)
+		if err != nil {
+			return nil, fmt.Errorf("load %s from source failed, %s", s.Name(), err.Error())
+		}
+		dst, err := s.Load(ctx, m.Target)
+		if err != nil {
+			return nil, fmt.Errorf("load %s from target failed, %s", s.Name(), err.Error())
+		}
+		for _, key := range sortedKeys(src) {
+			item, ok := dst[key]
+			switch {
+			case !ok:
+				diffs = append(diffs, &migrate.Diff{Stage: s.Name(), Key: key, Reason: migrate.DiffMissing})
+			case !s.Equal(src[key], item):
+				diffs = append(diffs, &migrate.Diff{Stage: s.Name(), Key: key, Reason: migrate.DiffDifferent})
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// Migrate migrates the data of the running datasource to the target one,
+// only one migration can run in the cluster at the same time
+func Migrate(ctx context.Context, in *migrate.Request) (*migrate.Report, error) {
+	source := config.GetString("registry.kind", "", config.WithStandby("registry_plugin"))
+	if len(in.Target) == 0 {
+		return nil, ErrEmptyTarget
+	}
+	if storageOf(source) == storageOf(in.Target) {
+		return nil, ErrSameStorage
+	}
+
+	err := datasource.GetSystemManager().DLock(ctx, &datasource.DLockRequest{ID: LockID})
+	if err != nil {
+		log.Error("lock migration failed", err)
+		return nil, ErrMigrating
+	}
+	defer func() {
+		if err := datasource.GetSystemManager().DUnlock(ctx, &datasource.DUnlockRequest{ID: LockID}); err != nil {
+			log.Error("unlock migration failed", err)
+		}
+	}()
+
+	target, err := getTarget(datasource.Kind(in.Target))
+	if err != nil {
+		return nil, err
+	}
+	cp, err := LoadCheckpoint(ctx, datasource.GetSystemManager(), in.Target)
+	if err != nil {
+		return nil, err
+	}
+	if !in.Resume && !in.DryRun && !in.VerifyOnly {
+		if err := cp.Remove(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &Migrator{
+		Source:     datasource.GetDataSource(),
+		Target:     target,
+		Checkpoint: cp,
+		Stages:     Stages(),
+	}
+	var report *migrate.Report
+	if in.VerifyOnly {
+		report = &migrate.Report{}
+		report.Diffs, err = m.Verify(ctx)
+	} else {
+		report, err = m.Run(ctx, in.DryRun)
+	}
+	if report != nil {
+		report.Source, report.Target = source, in.Target
+	}
+	return report, err
+}
+
+// getTarget constructs the target datasource once, the datasource plugins
+// hold the storage clients as singletons
+func getTarget(kind datasource.Kind) (datasource.DataSource, error) {
+	targetsMux.Lock()
+	defer targetsMux.Unlock()
+	if ds, ok := targets[kind]; ok {
+		return ds, nil
+	}
+	ds, err := datasource.New(datasource.Options{
+		Kind:        kind,
+		SslEnabled:  config.GetSSL().SslEnabled,
+		InstanceTTL: config.GetRegistry().InstanceTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	targets[kind] = ds
+	return ds, nil
+}
+
+// storageOf returns the storage client family of the datasource kind, the
+// kinds in the same family can not be opened in one process
+func storageOf(kind string) string {
+	switch kind {
+	case "etcd", "embeded_etcd", "embedded_etcd", "local":
+		return "etcd"
+	default:
+		return kind
+	}
+}
+
+// orderedKeys returns the keys in the order of the stage if it is an
+// Orderer, otherwise in the lexical order
+func orderedKeys(s Stage, items map[string]interface{}) []string {
+	if o, ok := s.(Orderer); ok {
+		return o.Order(items)
+	}
+	return sortedKeys(items)
+}
+
+func sortedKeys(items map[string]interface{}) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
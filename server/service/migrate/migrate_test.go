@@ -0,0 +1,370 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate_test
+
+import (
+	_ "github.com/apache/servicecomb-service-center/test"
+
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/service/disco"
+	migratesvc "github.com/apache/servicecomb-service-center/server/service/migrate"
+)
+
+type fakeDataSource struct {
+	datasource.DataSource
+	items map[string]interface{}
+}
+
+// fakeStage stores the items in fakeDataSource
+type fakeStage struct {
+	failKey string
+}
+
+func (s *fakeStage) Name() string    { return "fake" }
+func (s *fakeStage) Mergeable() bool { return false }
+func (s *fakeStage) Load(_ context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	for k, v := range ds.(*fakeDataSource).items {
+		items[k] = v
+	}
+	return items, nil
+}
+func (s *fakeStage) Equal(src, dst interface{}) bool { return src == dst }
+func (s *fakeStage) Apply(_ context.Context, ds datasource.DataSource, key string, src, _ interface{}) error {
+	if key == s.failKey {
+		return errors.New("apply failed")
+	}
+	ds.(*fakeDataSource).items[key] = src
+	return nil
+}
+
+func TestMigrator_Run(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeDataSource{items: map[string]interface{}{"a": 1, "b": 2, "c": 3, "d": 4}}
+	target := &fakeDataSource{items: map[string]interface{}{"b": 2, "c": 30}}
+	sm := datasource.GetSystemManager()
+	defer sm.DeleteCheckpoint(ctx, migratesvc.CheckpointTask)
+
+	t.Run("dry run, should not write the target", func(t *testing.T) {
+		m := &migratesvc.Migrator{Source: source, Target: target, Stages: []migratesvc.Stage{&fakeStage{}}}
+		report, err := m.Run(ctx, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(report.Stages))
+		sr := report.Stages[0]
+		assert.Equal(t, 4, sr.Total)
+		assert.Equal(t, 2, sr.Created)
+		assert.Equal(t, 1, sr.Skipped)
+		assert.Equal(t, 1, sr.Conflicted)
+		assert.Equal(t, 2, len(target.items))
+	})
+
+	t.Run("migrate with a failed item, should keep the checkpoint", func(t *testing.T) {
+		cp, err := migratesvc.LoadCheckpoint(ctx, sm, "fake")
+		assert.NoError(t, err)
+		m := &migratesvc.Migrator{Source: source, Target: target, Checkpoint: cp,
+			Stages: []migratesvc.Stage{&fakeStage{failKey: "d"}}}
+		report, err := m.Run(ctx, false)
+		assert.NoError(t, err)
+		sr := report.Stages[0]
+		assert.Equal(t, 1, sr.Created)
+		assert.Equal(t, 1, sr.Failed)
+		assert.Equal(t, 1, len(sr.Errors))
+		assert.Equal(t, []*migrate.Diff{
+			{Stage: "fake", Key: "c", Reason: migrate.DiffDifferent},
+			{Stage: "fake", Key: "d", Reason: migrate.DiffMissing},
+		}, report.Diffs)
+
+		cp, err = migratesvc.LoadCheckpoint(ctx, sm, "fake")
+		assert.NoError(t, err)
+		assert.Equal(t, "c", cp.Last("fake"))
+	})
+
+	t.Run("resume from the checkpoint, should only migrate the rest", func(t *testing.T) {
+		cp, err := migratesvc.LoadCheckpoint(ctx, sm, "fake")
+		assert.NoError(t, err)
+		m := &migratesvc.Migrator{Source: source, Target: target, Checkpoint: cp,
+			Stages: []migratesvc.Stage{&fakeStage{}}}
+		report, err := m.Run(ctx, false)
+		assert.NoError(t, err)
+		sr := report.Stages[0]
+		assert.Equal(t, 3, sr.Resumed)
+		assert.Equal(t, 1, sr.Created)
+		assert.Equal(t, 1, len(report.Diffs))
+
+		cp, err = migratesvc.LoadCheckpoint(ctx, sm, "fake")
+		assert.NoError(t, err)
+		assert.Empty(t, cp.Last("fake"))
+	})
+
+	t.Run("load the checkpoint of another target, should be empty", func(t *testing.T) {
+		cp, err := migratesvc.LoadCheckpoint(ctx, sm, "fake")
+		assert.NoError(t, err)
+		assert.NoError(t, cp.Save(ctx, "fake", "a"))
+		cp, err = migratesvc.LoadCheckpoint(ctx, sm, "other")
+		assert.NoError(t, err)
+		assert.Empty(t, cp.Last("fake"))
+	})
+}
+
+func TestStages(t *testing.T) {
+	ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "migrate", "migrate"))
+	resp, err := disco.RegisterService(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       "migrate_stages",
+			ServiceName: "migrate_stages",
+			Version:     "1.0.0",
+			Schemas:     []string{"schema1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	serviceID := resp.ServiceId
+	defer disco.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+
+	_, err = datasource.GetMetadataManager().ModifySchema(ctx, &pb.ModifySchemaRequest{
+		ServiceId: serviceID,
+		SchemaId:  "schema1",
+		Schema:    "schema1",
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetMetadataManager().AddTags(ctx, &pb.AddServiceTagsRequest{
+		ServiceId: serviceID,
+		Tags:      map[string]string{"a": "b"},
+	})
+	assert.NoError(t, err)
+	_, err = datasource.GetMetadataManager().AddRule(ctx, &pb.AddServiceRulesRequest{
+		ServiceId: serviceID,
+		Rules: []*pb.AddOrUpdateServiceRule{
+			{RuleType: "BLACK", Attribute: "ServiceName", Pattern: "test"},
+		},
+	})
+	assert.NoError(t, err)
+
+	t.Run("load from the datasource, should contain the service", func(t *testing.T) {
+		key := "migrate/migrate/" + serviceID
+		for _, s := range migratesvc.Stages() {
+			items, err := s.Load(ctx, datasource.GetDataSource())
+			assert.NoError(t, err)
+			switch s.Name() {
+			case migrate.StageService, migrate.StageTag, migrate.StageRule, migrate.StageSchema:
+				assert.Contains(t, items, key, s.Name())
+			}
+		}
+	})
+
+	t.Run("migrate to itself, should skip all", func(t *testing.T) {
+		m := &migratesvc.Migrator{Source: datasource.GetDataSource(), Target: datasource.GetDataSource(),
+			Stages: migratesvc.Stages()}
+		report, err := m.Run(ctx, false)
+		assert.NoError(t, err)
+		for _, sr := range report.Stages {
+			assert.Equal(t, sr.Total, sr.Skipped, sr.Stage)
+		}
+		assert.Empty(t, report.Diffs)
+	})
+}
+
+// fakeSchemaSource returns the service with the schemas out of the quota
+type fakeSchemaSource struct {
+	datasource.DataSource
+	metadata *fakeSchemaManager
+}
+
+func (ds *fakeSchemaSource) MetadataManager() datasource.MetadataManager { return ds.metadata }
+
+type fakeSchemaManager struct {
+	datasource.MetadataManager
+	domainProject string
+	service       *pb.MicroService
+	schemas       []*pb.Schema
+}
+
+func (m *fakeSchemaManager) ListServicesAcrossDomainProject(_ context.Context) (map[string][]*pb.MicroService, error) {
+	return map[string][]*pb.MicroService{m.domainProject: {m.service}}, nil
+}
+
+func (m *fakeSchemaManager) GetAllSchemas(_ context.Context, _ *pb.GetAllSchemaRequest) (*pb.GetAllSchemaResponse, error) {
+	return &pb.GetAllSchemaResponse{Response: pb.CreateResponse(pb.ResponseSuccess, ""), Schemas: m.schemas}, nil
+}
+
+func TestSchemaStage_OutOfQuota(t *testing.T) {
+	ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "migrate", "migrate"))
+	resp, err := disco.RegisterService(ctx, &pb.CreateServiceRequest{
+		Service: &pb.MicroService{
+			AppId:       "migrate_quota",
+			ServiceName: "migrate_quota",
+			Version:     "1.0.0",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+	serviceID := resp.ServiceId
+	defer disco.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: serviceID, Force: true})
+
+	var schemas []*pb.Schema
+	for i := 0; i <= quota.DefaultSchemaQuota; i++ {
+		id := fmt.Sprintf("schema%03d", i)
+		schemas = append(schemas, &pb.Schema{SchemaId: id, Schema: id})
+	}
+	source := &fakeSchemaSource{
+		DataSource: datasource.GetDataSource(),
+		metadata: &fakeSchemaManager{
+			MetadataManager: datasource.GetMetadataManager(),
+			domainProject:   "migrate/migrate",
+			service:         &pb.MicroService{ServiceId: serviceID},
+			schemas:         schemas,
+		},
+	}
+
+	var stages []migratesvc.Stage
+	for _, s := range migratesvc.Stages() {
+		if s.Name() == migrate.StageSchema {
+			stages = append(stages, s)
+		}
+	}
+	m := &migratesvc.Migrator{Source: source, Target: datasource.GetDataSource(), Stages: stages}
+	report, err := m.Run(ctx, false)
+	assert.NoError(t, err)
+	sr := report.Stages[0]
+	assert.Equal(t, 0, sr.Failed, sr.Errors)
+	assert.Equal(t, 1, sr.Created)
+
+	all, err := datasource.GetMetadataManager().GetAllSchemas(ctx, &pb.GetAllSchemaRequest{ServiceId: serviceID})
+	assert.NoError(t, err)
+	assert.Equal(t, len(schemas), len(all.Schemas))
+}
+
+func TestRoleStage_Order(t *testing.T) {
+	ctx := context.Background()
+	rm := datasource.GetRoleManager()
+	for _, name := range []string{"migrate_a", "migrate_b", "migrate_c"} {
+		assert.NoError(t, rm.CreateRole(ctx, &rbac.Role{Name: name}))
+		defer rm.DeleteRole(ctx, name)
+	}
+	// a inherits c, c inherits b
+	assert.NoError(t, rm.SetRoleParents(ctx, "migrate_a", []string{"migrate_c"}))
+	defer rm.SetRoleParents(ctx, "migrate_a", nil)
+	assert.NoError(t, rm.SetRoleParents(ctx, "migrate_c", []string{"migrate_b"}))
+	defer rm.SetRoleParents(ctx, "migrate_c", nil)
+
+	for _, s := range migratesvc.Stages() {
+		if s.Name() != migrate.StageRole {
+			continue
+		}
+		items, err := s.Load(ctx, datasource.GetDataSource())
+		assert.NoError(t, err)
+		index := make(map[string]int)
+		for i, name := range s.(migratesvc.Orderer).Order(items) {
+			index[name] = i
+		}
+		assert.Equal(t, len(items), len(index))
+		assert.True(t, index["migrate_b"] < index["migrate_c"])
+		assert.True(t, index["migrate_c"] < index["migrate_a"])
+	}
+}
+
+// fakeDependencyTarget records the dependencies added to the target
+type fakeDependencyTarget struct {
+	datasource.DataSource
+	dependency *fakeDependencyManager
+}
+
+func (ds *fakeDependencyTarget) DependencyManager() datasource.DependencyManager {
+	return ds.dependency
+}
+
+type fakeDependencyManager struct {
+	datasource.DependencyManager
+	added []*pb.ConsumerDependency
+}
+
+func (m *fakeDependencyManager) GetConsumerDependencyRules(_ context.Context, _ *pb.MicroServiceKey) ([]*pb.MicroServiceKey, error) {
+	return nil, nil
+}
+
+func (m *fakeDependencyManager) AddOrUpdateDependencies(_ context.Context, deps []*pb.ConsumerDependency, _ bool) (*pb.Response, error) {
+	m.added = append(m.added, deps...)
+	return pb.CreateResponse(pb.ResponseSuccess, ""), nil
+}
+
+func (m *fakeDependencyManager) DependencyHandle(_ context.Context) error {
+	return nil
+}
+
+func TestDependencyStage_KeepRules(t *testing.T) {
+	ctx := util.WithNoCache(util.SetDomainProject(context.Background(), "migrate", "migrate"))
+	var serviceIDs []string
+	for _, name := range []string{"migrate_consumer", "migrate_provider"} {
+		resp, err := disco.RegisterService(ctx, &pb.CreateServiceRequest{
+			Service: &pb.MicroService{AppId: "migrate_dependency", ServiceName: name, Version: "1.0.0"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+		serviceIDs = append(serviceIDs, resp.ServiceId)
+	}
+	defer func() {
+		for _, id := range serviceIDs {
+			disco.UnregisterService(ctx, &pb.DeleteServiceRequest{ServiceId: id, Force: true})
+		}
+	}()
+	consumer := &pb.MicroServiceKey{AppId: "migrate_dependency", ServiceName: "migrate_consumer", Version: "1.0.0"}
+	resp, err := datasource.GetDependencyManager().AddOrUpdateDependencies(ctx, []*pb.ConsumerDependency{{
+		Consumer: consumer,
+		Providers: []*pb.MicroServiceKey{
+			{AppId: "migrate_dependency", ServiceName: "migrate_provider", Version: "1.0.0+"},
+		},
+	}}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ResponseSuccess, resp.GetCode())
+	assert.NoError(t, datasource.GetDependencyManager().DependencyHandle(ctx))
+
+	var stages []migratesvc.Stage
+	for _, s := range migratesvc.Stages() {
+		if s.Name() == migrate.StageDependency {
+			stages = append(stages, s)
+		}
+	}
+	target := &fakeDependencyTarget{DataSource: datasource.GetDataSource(), dependency: &fakeDependencyManager{}}
+	m := &migratesvc.Migrator{Source: datasource.GetDataSource(), Target: target, Stages: stages}
+	report, err := m.Run(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Stages[0].Failed, report.Stages[0].Errors)
+
+	var providers []*pb.MicroServiceKey
+	for _, dep := range target.dependency.added {
+		if dep.Consumer.ServiceName == consumer.ServiceName {
+			providers = dep.Providers
+		}
+	}
+	if assert.Len(t, providers, 1) {
+		assert.Equal(t, "migrate_provider", providers[0].ServiceName)
+		assert.Equal(t, "1.0.0+", providers[0].Version)
+	}
+}
@@ -0,0 +1,451 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/migrate"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// Stage migrates one kind of resources, the items are indexed by a key
+// which is unique in the datasource, e.g. {domain}/{project}/{serviceId}
+type Stage interface {
+	Name() string
+	// Mergeable means the existing item in the target can be merged with
+	// the source one, otherwise it is reported as a conflict
+	Mergeable() bool
+	Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error)
+	Equal(src, dst interface{}) bool
+	// Apply writes src to the target, dst is nil if the item does not exist
+	Apply(ctx context.Context, ds datasource.DataSource, key string, src, dst interface{}) error
+}
+
+// Orderer is implemented by the stage whose items depend on each other, it
+// returns the keys in the order to apply
+type Orderer interface {
+	Order(items map[string]interface{}) []string
+}
+
+// Stages returns the stages in dependency order, services must be created
+// before their schemas, tags, rules and dependencies, roles before accounts
+func Stages() []Stage {
+	return []Stage{
+		&serviceStage{},
+		&schemaStage{},
+		&tagStage{},
+		&ruleStage{},
+		&dependencyStage{},
+		&roleStage{},
+		&accountStage{},
+	}
+}
+
+type serviceStage struct{}
+
+func (s *serviceStage) Name() string    { return migrate.StageService }
+func (s *serviceStage) Mergeable() bool { return false }
+
+func (s *serviceStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	err := forEachService(ctx, ds, func(_ context.Context, key string, service *discovery.MicroService) error {
+		items[key] = service
+		return nil
+	})
+	return items, err
+}
+
+func (s *serviceStage) Equal(src, dst interface{}) bool {
+	a, b := src.(*discovery.MicroService), dst.(*discovery.MicroService)
+	return a.Environment == b.Environment && a.AppId == b.AppId && a.ServiceName == b.ServiceName &&
+		a.Version == b.Version && a.Alias == b.Alias
+}
+
+func (s *serviceStage) Apply(ctx context.Context, ds datasource.DataSource, key string, src, _ interface{}) error {
+	service := *src.(*discovery.MicroService)
+	resp, err := ds.MetadataManager().RegisterService(keyContext(ctx, key), &discovery.CreateServiceRequest{
+		Service: &service,
+	})
+	if err != nil {
+		return err
+	}
+	return checkResponse(resp.Response)
+}
+
+type schemaStage struct{}
+
+func (s *schemaStage) Name() string    { return migrate.StageSchema }
+func (s *schemaStage) Mergeable() bool { return true }
+
+func (s *schemaStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	err := forEachService(ctx, ds, func(ctx context.Context, key string, service *discovery.MicroService) error {
+		resp, err := ds.MetadataManager().GetAllSchemas(ctx, &discovery.GetAllSchemaRequest{
+			ServiceId:  service.ServiceId,
+			WithSchema: true,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp.Response); err != nil {
+			return err
+		}
+		var schemas []*discovery.Schema
+		for _, schema := range resp.Schemas {
+			if len(schema.Schema) > 0 {
+				schemas = append(schemas, schema)
+			}
+		}
+		if len(schemas) == 0 {
+			return nil
+		}
+		sort.Slice(schemas, func(i, j int) bool { return schemas[i].SchemaId < schemas[j].SchemaId })
+		items[key] = schemas
+		return nil
+	})
+	return items, err
+}
+
+func (s *schemaStage) Equal(src, dst interface{}) bool {
+	return reflect.DeepEqual(src, dst)
+}
+
+func (s *schemaStage) Apply(ctx context.Context, ds datasource.DataSource, key string, src, _ interface{}) error {
+	resp, err := ds.MetadataManager().ModifySchemas(keyContext(ctx, key), &discovery.ModifySchemasRequest{
+		ServiceId: keyID(key),
+		Schemas:   src.([]*discovery.Schema),
+	})
+	if err != nil {
+		return err
+	}
+	return checkResponse(resp.Response)
+}
+
+type tagStage struct{}
+
+func (s *tagStage) Name() string    { return migrate.StageTag }
+func (s *tagStage) Mergeable() bool { return true }
+
+func (s *tagStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	err := forEachService(ctx, ds, func(ctx context.Context, key string, service *discovery.MicroService) error {
+		resp, err := ds.MetadataManager().GetTags(ctx, &discovery.GetServiceTagsRequest{
+			ServiceId: service.ServiceId,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp.Response); err != nil {
+			return err
+		}
+		if len(resp.Tags) > 0 {
+			items[key] = resp.Tags
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (s *tagStage) Equal(src, dst interface{}) bool {
+	return reflect.DeepEqual(src, dst)
+}
+
+func (s *tagStage) Apply(ctx context.Context, ds datasource.DataSource, key string, src, _ interface{}) error {
+	resp, err := ds.MetadataManager().AddTags(keyContext(ctx, key), &discovery.AddServiceTagsRequest{
+		ServiceId: keyID(key),
+		Tags:      src.(map[string]string),
+	})
+	if err != nil {
+		return err
+	}
+	return checkResponse(resp.Response)
+}
+
+type ruleStage struct{}
+
+func (s *ruleStage) Name() string    { return migrate.StageRule }
+func (s *ruleStage) Mergeable() bool { return true }
+
+func (s *ruleStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	err := forEachService(ctx, ds, func(ctx context.Context, key string, service *discovery.MicroService) error {
+		resp, err := ds.MetadataManager().GetRules(ctx, &discovery.GetServiceRulesRequest{
+			ServiceId: service.ServiceId,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp.Response); err != nil {
+			return err
+		}
+		if len(resp.Rules) == 0 {
+			return nil
+		}
+		rules := make(map[string]*discovery.ServiceRule, len(resp.Rules))
+		for _, rule := range resp.Rules {
+			rules[ruleKey(rule)] = rule
+		}
+		items[key] = rules
+		return nil
+	})
+	return items, err
+}
+
+func (s *ruleStage) Equal(src, dst interface{}) bool {
+	a, b := src.(map[string]*discovery.ServiceRule), dst.(map[string]*discovery.ServiceRule)
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ruleStage) Apply(ctx context.Context, ds datasource.DataSource, key string, src, dst interface{}) error {
+	var exist map[string]*discovery.ServiceRule
+	if dst != nil {
+		exist = dst.(map[string]*discovery.ServiceRule)
+	}
+	var rules []*discovery.AddOrUpdateServiceRule
+	for k, rule := range src.(map[string]*discovery.ServiceRule) {
+		if _, ok := exist[k]; ok {
+			continue
+		}
+		rules = append(rules, &discovery.AddOrUpdateServiceRule{
+			RuleType:    rule.RuleType,
+			Attribute:   rule.Attribute,
+			Pattern:     rule.Pattern,
+			Description: rule.Description,
+		})
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	resp, err := ds.MetadataManager().AddRule(keyContext(ctx, key), &discovery.AddServiceRulesRequest{
+		ServiceId: keyID(key),
+		Rules:     rules,
+	})
+	if err != nil {
+		return err
+	}
+	return checkResponse(resp.Response)
+}
+
+// dependency is the provider rules of a consumer as they are declared, the
+// version rules like 1.0.0+ or latest are not resolved, so that they keep
+// matching the new versions in the target
+type dependency struct {
+	consumer *discovery.MicroServiceKey
+	rules    []*discovery.MicroServiceKey
+	keys     []string
+}
+
+type dependencyStage struct{}
+
+func (s *dependencyStage) Name() string    { return migrate.StageDependency }
+func (s *dependencyStage) Mergeable() bool { return true }
+
+func (s *dependencyStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	items := make(map[string]interface{})
+	err := forEachService(ctx, ds, func(ctx context.Context, key string, service *discovery.MicroService) error {
+		consumer := toServiceKey(service)
+		rules, err := ds.DependencyManager().GetConsumerDependencyRules(ctx, consumer)
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		dep := &dependency{consumer: consumer}
+		for _, rule := range rules {
+			dep.rules = append(dep.rules, rule)
+			dep.keys = append(dep.keys, util.StringJoin([]string{
+				rule.Environment, rule.AppId, rule.ServiceName, rule.Version}, "/"))
+		}
+		sort.Strings(dep.keys)
+		items[key] = dep
+		return nil
+	})
+	return items, err
+}
+
+func (s *dependencyStage) Equal(src, dst interface{}) bool {
+	return reflect.DeepEqual(src.(*dependency).keys, dst.(*dependency).keys)
+}
+
+func (s *dependencyStage) Apply(ctx context.Context, ds datasource.DataSource, key string, src, _ interface{}) error {
+	dep := src.(*dependency)
+	// the keys are changed by the datasource, do not share them with the
+	// source, and the tenant follows the target
+	consumer := *dep.consumer
+	providers := make([]*discovery.MicroServiceKey, 0, len(dep.rules))
+	for _, rule := range dep.rules {
+		provider := *rule
+		provider.Tenant = ""
+		providers = append(providers, &provider)
+	}
+	resp, err := ds.DependencyManager().AddOrUpdateDependencies(keyContext(ctx, key), []*discovery.ConsumerDependency{
+		{Consumer: &consumer, Providers: providers},
+	}, false)
+	if err != nil {
+		return err
+	}
+	return checkResponse(resp)
+}
+
+type roleStage struct{}
+
+// roleItem is the role with the roles it inherits
+type roleItem struct {
+	Role    *rbac.Role
+	Parents []string
+}
+
+func (s *roleStage) Name() string    { return migrate.StageRole }
+func (s *roleStage) Mergeable() bool { return false }
+
+func (s *roleStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	roles, _, err := ds.RoleManager().ListRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := ds.RoleManager().ListRoleParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]interface{}, len(roles))
+	for _, role := range roles {
+		items[role.Name] = &roleItem{Role: role, Parents: parents[role.Name]}
+	}
+	return items, nil
+}
+
+// Order returns the roles after the ones they inherit
+func (s *roleStage) Order(items map[string]interface{}) []string {
+	parents := make(map[string][]string, len(items))
+	for name, item := range items {
+		parents[name] = item.(*roleItem).Parents
+	}
+	return datasource.SortRolesByParents(sortedKeys(items), parents)
+}
+
+func (s *roleStage) Equal(src, dst interface{}) bool {
+	a, b := src.(*roleItem), dst.(*roleItem)
+	return reflect.DeepEqual(a.Role.Perms, b.Role.Perms) && reflect.DeepEqual(a.Parents, b.Parents)
+}
+
+func (s *roleStage) Apply(ctx context.Context, ds datasource.DataSource, _ string, src, _ interface{}) error {
+	item := src.(*roleItem)
+	role := *item.Role
+	return ds.RoleManager().CreateRole(ctx, &role, item.Parents...)
+}
+
+type accountStage struct{}
+
+func (s *accountStage) Name() string    { return migrate.StageAccount }
+func (s *accountStage) Mergeable() bool { return false }
+
+func (s *accountStage) Load(ctx context.Context, ds datasource.DataSource) (map[string]interface{}, error) {
+	accounts, _, err := ds.AccountManager().ListAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]interface{}, len(accounts))
+	for _, a := range accounts {
+		// the list result does not contain the password
+		account, err := ds.AccountManager().GetAccount(ctx, a.Name)
+		if err != nil {
+			return nil, err
+		}
+		items[account.Name] = account
+	}
+	return items, nil
+}
+
+func (s *accountStage) Equal(src, dst interface{}) bool {
+	a, b := src.(*rbac.Account), dst.(*rbac.Account)
+	return a.Password == b.Password && a.Status == b.Status && reflect.DeepEqual(a.Roles, b.Roles)
+}
+
+func (s *accountStage) Apply(ctx context.Context, ds datasource.DataSource, _ string, src, _ interface{}) error {
+	account := *src.(*rbac.Account)
+	// the password of the source is hashed, create it as is in one step
+	return ds.AccountManager().CreateAccount(util.WithHashedPassword(ctx), &account)
+}
+
+func forEachService(ctx context.Context, ds datasource.DataSource,
+	f func(ctx context.Context, key string, service *discovery.MicroService) error) error {
+	services, err := ds.MetadataManager().ListServicesAcrossDomainProject(ctx)
+	if err != nil {
+		return err
+	}
+	for domainProject, list := range services {
+		for _, service := range list {
+			key := domainProject + datasource.SPLIT + service.ServiceId
+			if err := f(keyContext(ctx, key), key, service); err != nil {
+				return fmt.Errorf("%s: %s", key, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// keyContext returns the context with the domain project of the key
+func keyContext(ctx context.Context, key string) context.Context {
+	parts := strings.SplitN(key, datasource.SPLIT, 3)
+	if len(parts) < 3 {
+		return ctx
+	}
+	return util.SetDomainProject(ctx, parts[0], parts[1])
+}
+
+func keyID(key string) string {
+	return key[strings.LastIndex(key, datasource.SPLIT)+1:]
+}
+
+func ruleKey(rule *discovery.ServiceRule) string {
+	return util.StringJoin([]string{rule.RuleType, rule.Attribute, rule.Pattern}, datasource.SPLIT)
+}
+
+func toServiceKey(service *discovery.MicroService) *discovery.MicroServiceKey {
+	return &discovery.MicroServiceKey{
+		Environment: service.Environment,
+		AppId:       service.AppId,
+		ServiceName: service.ServiceName,
+		Version:     service.Version,
+	}
+}
+
+func checkResponse(resp *discovery.Response) error {
+	if resp == nil || resp.GetCode() == discovery.ResponseSuccess {
+		return nil
+	}
+	return fmt.Errorf("%d: %s", resp.GetCode(), resp.GetMessage())
+}
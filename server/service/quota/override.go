@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// OverrideTTL is how long the overrides are cached for applying the quotas,
+// the changes are seen at once by this instance and after it by the others
+const OverrideTTL = 10 * time.Second
+
+var overrides = cache.New(OverrideTTL, 2*OverrideTTL)
+
+var (
+	ErrEmptyDomain     = errors.New("domain is required")
+	ErrUnknownResource = errors.New("unknown quota resource")
+	ErrNegativeLimit   = errors.New("quota limit can not be negative")
+)
+
+// GetQuota returns the override of the domain, or the project if it is
+// not empty
+func GetQuota(ctx context.Context, domain, project string) (*quota.Quota, error) {
+	if len(domain) == 0 {
+		return nil, ErrEmptyDomain
+	}
+	return datasource.GetQuotaManager().GetQuota(ctx, domain, project)
+}
+
+// PutQuota replaces the override of the domain or the project
+func PutQuota(ctx context.Context, q *quota.Quota) error {
+	if err := validate(q); err != nil {
+		return err
+	}
+	err := datasource.GetQuotaManager().PutQuota(ctx, q)
+	overrides.Delete(overrideKey(q.Domain, q.Project))
+	return err
+}
+
+// DeleteQuota resets the domain or the project to the upper level limits
+func DeleteQuota(ctx context.Context, domain, project string) error {
+	if len(domain) == 0 {
+		return ErrEmptyDomain
+	}
+	err := datasource.GetQuotaManager().DeleteQuota(ctx, domain, project)
+	overrides.Delete(overrideKey(domain, project))
+	return err
+}
+
+// GetOverride returns the overridden limit of the resource in the
+// domain/project, it returns nil if neither the project nor the domain
+// overrides the resource
+func GetOverride(ctx context.Context, domain, project, resource string) (*quota.Limit, error) {
+	if len(domain) == 0 {
+		return nil, nil
+	}
+	if len(project) > 0 {
+		limit, err := getOverride(ctx, domain, project, resource, quota.ScopeProject)
+		if err != nil || limit != nil {
+			return limit, err
+		}
+	}
+	return getOverride(ctx, domain, "", resource, quota.ScopeDomain)
+}
+
+func getOverride(ctx context.Context, domain, project, resource, scope string) (*quota.Limit, error) {
+	q, err := getCachedQuota(ctx, domain, project)
+	if err != nil || q == nil {
+		return nil, err
+	}
+	limit, ok := q.Limits[resource]
+	if !ok {
+		return nil, nil
+	}
+	return &quota.Limit{Resource: resource, Limit: limit, Scope: scope}, nil
+}
+
+// getCachedQuota returns the override of the domain or the project, it
+// returns nil if the override does not exist, the absence is cached too
+func getCachedQuota(ctx context.Context, domain, project string) (*quota.Quota, error) {
+	key := overrideKey(domain, project)
+	if !util.NoCache(ctx) {
+		if v, ok := overrides.Get(key); ok {
+			return v.(*quota.Quota), nil
+		}
+	}
+	q, err := datasource.GetQuotaManager().GetQuota(ctx, domain, project)
+	if err != nil {
+		if !errors.Is(err, datasource.ErrQuotaNotExist) {
+			return nil, err
+		}
+		q = nil
+	}
+	overrides.SetDefault(key, q)
+	return q, nil
+}
+
+func overrideKey(domain, project string) string {
+	return util.StringJoin([]string{domain, project}, "/")
+}
+
+func validate(q *quota.Quota) error {
+	if q == nil || len(q.Domain) == 0 {
+		return ErrEmptyDomain
+	}
+	for resource, limit := range q.Limits {
+		if !util.SliceHave(quota.Resources, resource) {
+			return fmt.Errorf("%w '%s'", ErrUnknownResource, resource)
+		}
+		if limit < 0 {
+			return fmt.Errorf("%w, %s: %d", ErrNegativeLimit, resource, limit)
+		}
+	}
+	return nil
+}
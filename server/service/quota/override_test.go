@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quota_test
+
+import (
+	_ "github.com/apache/servicecomb-service-center/test"
+
+	"context"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	qt "github.com/apache/servicecomb-service-center/pkg/quota"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/service/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutQuota(t *testing.T) {
+	ctx := getContext()
+
+	t.Run("put invalid quota, should be failed", func(t *testing.T) {
+		err := quota.PutQuota(ctx, &qt.Quota{Limits: map[string]int64{qt.ResourceService: 1}})
+		assert.ErrorIs(t, err, quota.ErrEmptyDomain)
+		err = quota.PutQuota(ctx, &qt.Quota{Domain: "put_quota", Limits: map[string]int64{"x": 1}})
+		assert.ErrorIs(t, err, quota.ErrUnknownResource)
+		err = quota.PutQuota(ctx, &qt.Quota{Domain: "put_quota", Limits: map[string]int64{qt.ResourceService: -1}})
+		assert.ErrorIs(t, err, quota.ErrNegativeLimit)
+	})
+
+	t.Run("put then reset quota, should be successful", func(t *testing.T) {
+		err := quota.PutQuota(ctx, &qt.Quota{Domain: "put_quota", Project: "put_quota",
+			Limits: map[string]int64{qt.ResourceService: 1}})
+		assert.NoError(t, err)
+		q, err := quota.GetQuota(ctx, "put_quota", "put_quota")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), q.Limits[qt.ResourceService])
+
+		limit, err := quota.GetOverride(ctx, "put_quota", "put_quota", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Equal(t, &qt.Limit{Resource: qt.ResourceService, Limit: 1, Scope: qt.ScopeProject}, limit)
+		limit, err = quota.GetOverride(ctx, "put_quota", "put_quota", qt.ResourceInstance)
+		assert.NoError(t, err)
+		assert.Nil(t, limit)
+
+		err = quota.DeleteQuota(ctx, "put_quota", "put_quota")
+		assert.NoError(t, err)
+		_, err = quota.GetQuota(ctx, "put_quota", "put_quota")
+		assert.ErrorIs(t, err, datasource.ErrQuotaNotExist)
+		limit, err = quota.GetOverride(ctx, "put_quota", "put_quota", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Nil(t, limit)
+	})
+	t.Run("changed in the datasource, should be cached until reset", func(t *testing.T) {
+		// the requests of getContext read the datasource without the cache
+		ctx := util.SetDomainProject(context.Background(), "default", "default")
+		defer quota.DeleteQuota(ctx, "cache_quota", "")
+		err := quota.PutQuota(ctx, &qt.Quota{Domain: "cache_quota", Limits: map[string]int64{qt.ResourceService: 1}})
+		assert.NoError(t, err)
+		limit, err := quota.GetOverride(ctx, "cache_quota", "", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), limit.Limit)
+
+		err = datasource.GetQuotaManager().PutQuota(ctx, &qt.Quota{Domain: "cache_quota",
+			Limits: map[string]int64{qt.ResourceService: 2}})
+		assert.NoError(t, err)
+		limit, err = quota.GetOverride(ctx, "cache_quota", "", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), limit.Limit)
+		limit, err = quota.GetOverride(util.WithNoCache(ctx), "cache_quota", "", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), limit.Limit)
+
+		err = quota.PutQuota(ctx, &qt.Quota{Domain: "cache_quota", Limits: map[string]int64{qt.ResourceService: 3}})
+		assert.NoError(t, err)
+		limit, err = quota.GetOverride(ctx, "cache_quota", "", qt.ResourceService)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), limit.Limit)
+	})
+}
@@ -76,3 +76,13 @@ func AccountUsage(ctx context.Context) (int64, error) {
 	}
 	return used, nil
 }
+
+func TagUsage(ctx context.Context, serviceID string) (int64, error) {
+	resp, err := datasource.GetMetadataManager().GetTags(ctx, &discovery.GetServiceTagsRequest{
+		ServiceId: serviceID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(resp.Tags)), nil
+}
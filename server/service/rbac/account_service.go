@@ -54,14 +54,19 @@ func CreateAccount(ctx context.Context, a *rbac.Account) error {
 		log.Errorf(err, "create account [%s] failed", a.Name)
 		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
 	}
+	if err = checkPasswordPolicy(a.Name, a.Password); err != nil {
+		return err
+	}
 	if err = checkRoleNames(ctx, a.Roles); err != nil {
 		return rbac.NewError(rbac.ErrAccountHasInvalidRole, err.Error())
 	}
 
+	// the datasource replaces the password with the hash
+	pwd := a.Password
 	err = datasource.GetAccountManager().CreateAccount(ctx, a)
 	if err == nil {
 		log.Infof("create account [%s] success", a.Name)
-		return nil
+		return recordPassword(ctx, a.Name, pwd)
 	}
 	log.Errorf(err, "create account [%s] failed", a.Name)
 	if err == datasource.ErrAccountDuplicated {
@@ -92,6 +97,7 @@ func UpdateAccount(ctx context.Context, name string, a *rbac.Account) error {
 	if len(a.Status) != 0 {
 		oldAccount.Status = a.Status
 	}
+	rolesChanged := len(a.Roles) != 0 && !sameRoles(oldAccount.Roles, a.Roles)
 	if len(a.Roles) != 0 {
 		oldAccount.Roles = a.Roles
 	}
@@ -104,6 +110,9 @@ func UpdateAccount(ctx context.Context, name string, a *rbac.Account) error {
 		return err
 	}
 	log.Infof("account [%s] is edit", oldAccount.ID)
+	if rolesChanged {
+		return RevokeAccountTokens(ctx, name)
+	}
 	return nil
 }
 
@@ -118,8 +127,8 @@ func GetAccount(ctx context.Context, name string) (*rbac.Account, error) {
 	}
 	return r, nil
 }
-func ListAccount(ctx context.Context) ([]*rbac.Account, int64, error) {
-	return datasource.GetAccountManager().ListAccount(ctx)
+func ListAccount(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Account, int64, error) {
+	return datasource.GetAccountManager().ListAccount(ctx, opts...)
 }
 func AccountExist(ctx context.Context, name string) (bool, error) {
 	return datasource.GetAccountManager().AccountExist(ctx, name)
@@ -138,7 +147,12 @@ func DeleteAccount(ctx context.Context, name string) error {
 		return rbac.NewError(rbac.ErrAccountNotExist, msg)
 	}
 	_, err = datasource.GetAccountManager().DeleteAccount(ctx, []string{name})
-	return err
+	if err != nil {
+		return err
+	}
+	deleteAccountKeys(ctx, name)
+	deletePasswordHistory(ctx, name)
+	return nil
 }
 
 //CreateAccount save 2 kv
@@ -176,6 +190,18 @@ func checkRoleNames(ctx context.Context, roles []string) error {
 	return nil
 }
 
+func sameRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, r := range a {
+		if !util.SliceHave(b, r) {
+			return false
+		}
+	}
+	return true
+}
+
 func illegalAccountCheck(ctx context.Context, target string) error {
 	if target == RootName {
 		return rbac.NewError(rbac.ErrForbidOperateBuildInAccount, errorsEx.MsgCantOperateRoot)
@@ -0,0 +1,268 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const secretLength = 32
+
+// IsAPIKey returns true if the credential is an API key rather than a token
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apikey.Prefix)
+}
+
+// CreateAPIKey creates a key of the account, the roles of the key must be
+// a subset of the roles of the account. The returned key is the only copy
+// of the secret
+func CreateAPIKey(ctx context.Context, name string, in *apikey.CreateRequest) (*apikey.KeyResponse, error) {
+	expireAfter := in.ExpireAfter
+	if len(expireAfter) == 0 {
+		expireAfter = apikey.DefaultExpireAfter
+	}
+	ttl, err := parseExpireAfter(expireAfter)
+	if err != nil {
+		return nil, err
+	}
+	account, err := GetAccount(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkKeyRoles(account.Roles, in.Roles); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := &apikey.APIKey{
+		ID:         util.GenerateUUID(),
+		Account:    name,
+		Name:       in.Name,
+		Roles:      in.Roles,
+		CreateTime: now.Unix(),
+		UpdateTime: now.Unix(),
+		ExpireAt:   now.Add(ttl).Unix(),
+	}
+	secret, err := newSecret(key)
+	if err != nil {
+		return nil, err
+	}
+	err = datasource.GetAPIKeyManager().CreateKey(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("create api key of account [%s] failed", name), err)
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("create api key [%s] of account [%s] success", key.ID, name))
+	key.Hash = ""
+	return &apikey.KeyResponse{APIKey: key, Key: secret}, nil
+}
+
+func ListAPIKeys(ctx context.Context, name string) ([]*apikey.APIKey, error) {
+	keys, err := datasource.GetAPIKeyManager().ListKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		key.Hash = ""
+	}
+	return keys, nil
+}
+
+// RotateAPIKey replaces the secret of the key, the old secret is invalid
+// immediately
+func RotateAPIKey(ctx context.Context, name, id string, in *apikey.RotateRequest) (*apikey.KeyResponse, error) {
+	key, err := getAccountKey(ctx, name, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ttl := time.Duration(key.ExpireAt-key.UpdateTime) * time.Second
+	if len(in.ExpireAfter) > 0 {
+		ttl, err = parseExpireAfter(in.ExpireAfter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key.UpdateTime = now.Unix()
+	key.ExpireAt = now.Add(ttl).Unix()
+	secret, err := newSecret(key)
+	if err != nil {
+		return nil, err
+	}
+	err = datasource.GetAPIKeyManager().UpdateKey(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("rotate api key [%s] of account [%s] failed", id, name), err)
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("rotate api key [%s] of account [%s] success", id, name))
+	key.Hash = ""
+	return &apikey.KeyResponse{APIKey: key, Key: secret}, nil
+}
+
+func RevokeAPIKey(ctx context.Context, name, id string) error {
+	if _, err := getAccountKey(ctx, name, id); err != nil {
+		return err
+	}
+	err := datasource.GetAPIKeyManager().DeleteKey(ctx, id)
+	if err != nil {
+		log.Error(fmt.Sprintf("revoke api key [%s] of account [%s] failed", id, name), err)
+		return err
+	}
+	log.Info(fmt.Sprintf("revoke api key [%s] of account [%s] success", id, name))
+	return nil
+}
+
+// AuthenticateAPIKey verifies the key and returns the claims like a token,
+// the roles are the roles of the account narrowed by the key
+func AuthenticateAPIKey(ctx context.Context, credential string) (interface{}, error) {
+	id, secret, ok := parseAPIKey(credential)
+	if !ok {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, "invalid api key")
+	}
+	key, err := datasource.GetAPIKeyManager().GetKey(ctx, id)
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			return nil, rbac.NewError(rbac.ErrUnauthorized, "invalid api key")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.Hash)) != 1 {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, "invalid api key")
+	}
+	if time.Now().Unix() >= key.ExpireAt {
+		return nil, rbac.NewError(rbac.ErrTokenExpired, "api key is expired")
+	}
+	account, err := datasource.GetAccountManager().GetAccount(ctx, key.Account)
+	if err != nil {
+		if err == datasource.ErrAccountNotExist {
+			msg := fmt.Sprintf("account [%s] is deleted", key.Account)
+			return nil, rbac.NewError(rbac.ErrTokenOwnedAccountDeleted, msg)
+		}
+		return nil, err
+	}
+	var roles []interface{}
+	for _, r := range narrowRoles(account.Roles, key.Roles) {
+		roles = append(roles, r)
+	}
+	return map[string]interface{}{
+		rbac.ClaimsUser:  key.Account,
+		rbac.ClaimsRoles: roles,
+	}, nil
+}
+
+func deleteAccountKeys(ctx context.Context, name string) {
+	keys, err := datasource.GetAPIKeyManager().ListKey(ctx, name)
+	if err != nil {
+		log.Error(fmt.Sprintf("list api keys of account [%s] failed", name), err)
+		return
+	}
+	for _, key := range keys {
+		if err := datasource.GetAPIKeyManager().DeleteKey(ctx, key.ID); err != nil {
+			log.Error(fmt.Sprintf("remove api key [%s] of account [%s] failed", key.ID, name), err)
+		}
+	}
+}
+
+func getAccountKey(ctx context.Context, name, id string) (*apikey.APIKey, error) {
+	key, err := datasource.GetAPIKeyManager().GetKey(ctx, id)
+	if err != nil && err != datasource.ErrAPIKeyNotExist {
+		return nil, err
+	}
+	if key == nil || key.Account != name {
+		msg := fmt.Sprintf("api key [%s] of account [%s] not exist", id, name)
+		return nil, discovery.NewError(discovery.ErrInvalidParams, msg)
+	}
+	return key, nil
+}
+
+func parseExpireAfter(s string) (time.Duration, error) {
+	ttl, err := time.ParseDuration(s)
+	if err != nil || ttl <= 0 {
+		return 0, discovery.NewError(discovery.ErrInvalidParams, fmt.Sprintf("invalid expireAfter [%s]", s))
+	}
+	return ttl, nil
+}
+
+func checkKeyRoles(accountRoles, keyRoles []string) error {
+	for _, r := range keyRoles {
+		if !util.SliceHave(accountRoles, r) {
+			msg := fmt.Sprintf("role [%s] is not bound to the account", r)
+			return rbac.NewError(rbac.ErrAccountHasInvalidRole, msg)
+		}
+	}
+	return nil
+}
+
+// narrowRoles returns the account roles still allowed by the key, the roles
+// unbound from the account after the key is created are dropped
+func narrowRoles(accountRoles, keyRoles []string) []string {
+	if len(keyRoles) == 0 {
+		return accountRoles
+	}
+	var roles []string
+	for _, r := range keyRoles {
+		if util.SliceHave(accountRoles, r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// newSecret generates a secret for the key, sets the hash of it to the
+// key and returns the full key string
+func newSecret(key *apikey.APIKey) (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		log.Error("generate api key secret failed", err)
+		return "", err
+	}
+	secret := hex.EncodeToString(b)
+	key.Hash = hashSecret(secret)
+	return apikey.Prefix + key.ID + "." + secret, nil
+}
+
+func parseAPIKey(credential string) (id, secret string, ok bool) {
+	if !IsAPIKey(credential) {
+		return "", "", false
+	}
+	s := strings.SplitN(strings.TrimPrefix(credential, apikey.Prefix), ".", 2)
+	if len(s) != 2 || len(s[0]) == 0 || len(s[1]) == 0 {
+		return "", "", false
+	}
+	return s[0], s[1], true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
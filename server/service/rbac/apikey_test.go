@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/apikey"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestAPIKey(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestAPIKey_account"
+	a := newAccount(name)
+	a.Roles = []string{rbac.RoleAdmin, rbac.RoleDeveloper}
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	var key *apikey.KeyResponse
+	t.Run("create key with the role not bound, should fail", func(t *testing.T) {
+		_, err := rbacsvc.CreateAPIKey(ctx, name, &apikey.CreateRequest{Roles: []string{"invalid_role"}})
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrAccountHasInvalidRole, err.(*errsvc.Error).Code)
+	})
+	t.Run("create key with invalid expireAfter, should fail", func(t *testing.T) {
+		_, err := rbacsvc.CreateAPIKey(ctx, name, &apikey.CreateRequest{ExpireAfter: "-1h"})
+		assert.Error(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, err.(*errsvc.Error).Code)
+	})
+	t.Run("create narrowed key, should succeed", func(t *testing.T) {
+		var err error
+		key, err = rbacsvc.CreateAPIKey(ctx, name, &apikey.CreateRequest{Name: "ci", Roles: []string{rbac.RoleDeveloper}})
+		assert.NoError(t, err)
+		assert.True(t, rbacsvc.IsAPIKey(key.Key))
+		assert.Empty(t, key.Hash)
+		assert.True(t, key.ExpireAt > key.CreateTime)
+
+		keys, err := rbacsvc.ListAPIKeys(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(keys))
+		assert.Equal(t, key.ID, keys[0].ID)
+		assert.Empty(t, keys[0].Hash)
+	})
+	t.Run("list the keys, should not contain the keys of another account", func(t *testing.T) {
+		other := newAccount(name + "_other")
+		other.Roles = []string{rbac.RoleDeveloper}
+		assert.NoError(t, rbacsvc.CreateAccount(ctx, other))
+		defer rbacsvc.DeleteAccount(ctx, other.Name)
+		_, err := rbacsvc.CreateAPIKey(ctx, other.Name, &apikey.CreateRequest{})
+		assert.NoError(t, err)
+
+		keys, err := rbacsvc.ListAPIKeys(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(keys))
+		assert.Equal(t, key.ID, keys[0].ID)
+	})
+	t.Run("authenticate the key, should return the narrowed roles", func(t *testing.T) {
+		claims, err := rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, name, account.Name)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, key.Key+"0")
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUnauthorized, err.(*errsvc.Error).Code)
+	})
+	t.Run("rotate the key, the old secret should be invalid", func(t *testing.T) {
+		rotated, err := rbacsvc.RotateAPIKey(ctx, name, key.ID, &apikey.RotateRequest{ExpireAfter: "1h"})
+		assert.NoError(t, err)
+		assert.NotEqual(t, key.Key, rotated.Key)
+
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.Error(t, err)
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, rotated.Key)
+		assert.NoError(t, err)
+		key = rotated
+	})
+	t.Run("rotate the key of another account, should fail", func(t *testing.T) {
+		_, err := rbacsvc.RotateAPIKey(ctx, "root", key.ID, &apikey.RotateRequest{})
+		assert.Error(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, err.(*errsvc.Error).Code)
+	})
+	t.Run("revoke the key, should fail to authenticate", func(t *testing.T) {
+		assert.NoError(t, rbacsvc.RevokeAPIKey(ctx, name, key.ID))
+		_, err := rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.Error(t, err)
+		keys, err := rbacsvc.ListAPIKeys(ctx, name)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+	t.Run("delete the account, should remove the keys", func(t *testing.T) {
+		key, err := rbacsvc.CreateAPIKey(ctx, name, &apikey.CreateRequest{})
+		assert.NoError(t, err)
+		assert.NoError(t, rbacsvc.DeleteAccount(ctx, name))
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.Error(t, err)
+	})
+}
@@ -69,23 +69,42 @@ func (a *EmbeddedAuthenticator) Login(ctx context.Context, user string, password
 		TryLockAccount(MakeBanKey(user, ip))
 		return "", rbac.NewError(rbac.ErrUserOrPwdWrong, "")
 	}
+	expired := checkPasswordExpired(ctx, account)
+	if expired != nil && !errsvc.IsErrEqualCode(expired, ErrPasswordExpired) {
+		return "", expired
+	}
 
+	generation, err := tokenGeneration(ctx, user)
+	if err != nil {
+		return "", err
+	}
 	secret, err := GetPrivateKey()
 	if err != nil {
 		return "", err
 	}
-	tokenStr, err := token.Sign(map[string]interface{}{
+	claims := map[string]interface{}{
 		rbac.ClaimsUser:  user,
 		rbac.ClaimsRoles: account.Roles,
-	},
+		ClaimsGeneration: generation,
+		ClaimsTokenID:    util.GenerateUUID(),
+	}
+	expireAfter := opt.ExpireAfter
+	if expired != nil {
+		// the short-lived token without roles can only change the password,
+		// it is returned along with the error
+		claims[rbac.ClaimsRoles] = []string{}
+		claims[ClaimsPasswordExpired] = true
+		expireAfter = PasswordExpiredTokenTTL
+	}
+	tokenStr, err := token.Sign(claims,
 		secret,
-		token.WithExpTime(opt.ExpireAfter),
+		token.WithExpTime(expireAfter),
 		token.WithSigningMethod(token.RS512)) //TODO config for each user
 	if err != nil {
 		log.Errorf(err, "can not sign a token")
 		return "", err
 	}
-	return tokenStr, nil
+	return tokenStr, expired
 }
 
 //Authenticate parse a token to claims
@@ -115,6 +134,9 @@ func (a *EmbeddedAuthenticator) Authenticate(ctx context.Context, tokenStr strin
 		msg := fmt.Sprintf("account [%s] is deleted", n)
 		return nil, rbac.NewError(rbac.ErrTokenOwnedAccountDeleted, msg)
 	}
+	if err = checkTokenRevoked(ctx, tokenStr, claims); err != nil {
+		return nil, err
+	}
 	return claims, nil
 }
 
@@ -20,10 +20,13 @@ package rbac
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
 	accountsvc "github.com/apache/servicecomb-service-center/server/service/account"
 
 	"golang.org/x/time/rate"
@@ -32,11 +35,28 @@ import (
 const (
 	MaxAttempts = 2
 
-	BlockInterval = 1 * time.Hour
+	// AttemptWindow is the window of MaxAttempts failures, a failure
+	// attempt is recovered every AttemptWindow/MaxAttempts
+	AttemptWindow = 2 * time.Hour
+
+	banKeySeparator = "::"
 )
 
 var BanTime = 1 * time.Hour
 
+// Lock is a banned client, the account is banned from the IP until
+// ReleaseAt
+type Lock struct {
+	Account   string `json:"account"`
+	IP        string `json:"ip"`
+	ReleaseAt int64  `json:"releaseAt"`
+}
+
+type LockList struct {
+	Total int64   `json:"total"`
+	Locks []*Lock `json:"data"`
+}
+
 type LoginFailureLimiter struct {
 	limiter *rate.Limiter
 	Key     string
@@ -54,7 +74,7 @@ func TryLockAccount(key string) {
 	if c, ok = clients.Load(key); !ok {
 		l = &LoginFailureLimiter{
 			Key:     key,
-			limiter: rate.NewLimiter(rate.Every(BlockInterval), MaxAttempts),
+			limiter: newAttemptLimiter(),
 		}
 		clients.Store(key, l)
 	} else {
@@ -81,3 +101,77 @@ func IsBanned(key string) bool {
 	}
 	return IsBanned
 }
+
+// newAttemptLimiter allows rbac.maxAttempts failures in rbac.attemptWindow,
+// the ban duration is rbac.releaseLockAfter
+func newAttemptLimiter() *rate.Limiter {
+	attempts := config.GetInt("rbac.maxAttempts", MaxAttempts)
+	if attempts <= 0 {
+		attempts = MaxAttempts
+	}
+	window := config.GetDuration("rbac.attemptWindow", AttemptWindow)
+	if window <= 0 {
+		window = AttemptWindow
+	}
+	return rate.NewLimiter(rate.Every(window/time.Duration(attempts)), attempts)
+}
+
+// ListLocks returns the banned clients, filtered by the account name if
+// it is not empty
+func ListLocks(ctx context.Context, account string) (*LockList, error) {
+	banned, err := accountsvc.ListBanned(ctx)
+	if err != nil {
+		log.Error("can not list account locks", err)
+		return nil, err
+	}
+	locks := make([]*Lock, 0, len(banned))
+	for _, l := range banned {
+		name, ip := parseBanKey(l.Key)
+		if len(account) > 0 && name != account {
+			continue
+		}
+		locks = append(locks, &Lock{Account: name, IP: ip, ReleaseAt: l.ReleaseAt})
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		if locks[i].Account != locks[j].Account {
+			return locks[i].Account < locks[j].Account
+		}
+		return locks[i].IP < locks[j].IP
+	})
+	return &LockList{Total: int64(len(locks)), Locks: locks}, nil
+}
+
+// Unlock releases the account banned from the IP, or from all IPs if the
+// ip is empty, the failure attempts are reset as well
+func Unlock(ctx context.Context, account, ip string) error {
+	list, err := ListLocks(ctx, account)
+	if err != nil {
+		return err
+	}
+	for _, l := range list.Locks {
+		if len(ip) > 0 && l.IP != ip {
+			continue
+		}
+		key := MakeBanKey(l.Account, l.IP)
+		if err := accountsvc.Unban(ctx, key); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("account %s is unlocked from ip [%s]", l.Account, l.IP))
+	}
+	clients.Range(func(k, _ interface{}) bool {
+		name, addr := parseBanKey(k.(string))
+		if name == account && (len(ip) == 0 || addr == ip) {
+			clients.Delete(k)
+		}
+		return true
+	})
+	return nil
+}
+
+func parseBanKey(key string) (account, ip string) {
+	i := strings.Index(key, banKeySeparator)
+	if i < 0 {
+		return key, ""
+	}
+	return key[:i], key[i+len(banKeySeparator):]
+}
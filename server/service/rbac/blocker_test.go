@@ -18,9 +18,12 @@
 package rbac_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/go-chassis/go-archaius"
+
 	v4 "github.com/apache/servicecomb-service-center/server/resource/v4"
 
 	"github.com/apache/servicecomb-service-center/server/service/rbac"
@@ -56,3 +59,48 @@ func TestCountFailure(t *testing.T) {
 	assert.False(t, rbac.IsBanned(key2))
 
 }
+
+func TestLocks(t *testing.T) {
+	ctx := context.Background()
+	name := "TestLocks_account"
+	key1 := rbac.MakeBanKey(name, "127.0.0.1")
+	key2 := rbac.MakeBanKey(name, "::1")
+
+	t.Run("configure max attempts, should ban after the attempts", func(t *testing.T) {
+		archaius.Set("rbac.maxAttempts", 1)
+		defer archaius.Delete("rbac.maxAttempts")
+
+		rbac.TryLockAccount(key1)
+		assert.False(t, rbac.IsBanned(key1))
+		rbac.TryLockAccount(key1)
+		assert.True(t, rbac.IsBanned(key1))
+
+		rbac.TryLockAccount(key2)
+		rbac.TryLockAccount(key2)
+		assert.True(t, rbac.IsBanned(key2))
+	})
+	t.Run("list locks, should return the banned IPs", func(t *testing.T) {
+		list, err := rbac.ListLocks(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), list.Total)
+		assert.Equal(t, name, list.Locks[0].Account)
+		assert.Equal(t, "127.0.0.1", list.Locks[0].IP)
+		assert.Equal(t, "::1", list.Locks[1].IP)
+		assert.Less(t, time.Now().Unix()-1, list.Locks[0].ReleaseAt)
+	})
+	t.Run("unlock an IP, should not affect the other IP", func(t *testing.T) {
+		assert.NoError(t, rbac.Unlock(ctx, name, "127.0.0.1"))
+		assert.False(t, rbac.IsBanned(key1))
+		assert.True(t, rbac.IsBanned(key2))
+
+		rbac.TryLockAccount(key1)
+		assert.False(t, rbac.IsBanned(key1))
+	})
+	t.Run("unlock the account, should release all IPs", func(t *testing.T) {
+		assert.NoError(t, rbac.Unlock(ctx, name, ""))
+		assert.False(t, rbac.IsBanned(key2))
+		list, err := rbac.ListLocks(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), list.Total)
+	})
+}
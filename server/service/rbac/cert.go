@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"path"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+// CertUserPrefix prefixes the user names taken from the certificate common
+// names, the account names can not contain ':' so they never equal an account
+const CertUserPrefix = "cert:"
+
+// CertAuthEnabled returns whether the verified client certificates are
+// accepted instead of the tokens
+func CertAuthEnabled() bool {
+	c := config.GetCertAuth()
+	return c != nil && c.Enabled
+}
+
+// AuthenticateCert maps the verified client certificate to the claims by
+// the first matched rule
+func AuthenticateCert(ctx context.Context, cert *x509.Certificate) (interface{}, error) {
+	c := config.GetCertAuth()
+	if c == nil || !c.Enabled || cert == nil {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, "certificate authentication is disabled")
+	}
+	for _, rule := range c.Rules {
+		if rule == nil || !matchCert(rule, cert) {
+			continue
+		}
+		if len(rule.Account) == 0 {
+			return certClaims(CertUserPrefix+cert.Subject.CommonName, rule.Roles), nil
+		}
+		account, err := datasource.GetAccountManager().GetAccount(ctx, rule.Account)
+		if err != nil {
+			if err == datasource.ErrAccountNotExist {
+				msg := fmt.Sprintf("account [%s] mapped by certificate does not exist", rule.Account)
+				return nil, rbac.NewError(rbac.ErrUnauthorized, msg)
+			}
+			log.Error(fmt.Sprintf("get account [%s] failed", rule.Account), err)
+			return nil, err
+		}
+		return certClaims(account.Name, account.Roles), nil
+	}
+	msg := fmt.Sprintf("certificate [%s] matches no rule", cert.Subject.CommonName)
+	return nil, rbac.NewError(rbac.ErrUnauthorized, msg)
+}
+
+func certClaims(user string, roleList []string) map[string]interface{} {
+	var roles []interface{}
+	for _, r := range roleList {
+		roles = append(roles, r)
+	}
+	return map[string]interface{}{
+		rbac.ClaimsUser:  user,
+		rbac.ClaimsRoles: roles,
+	}
+}
+
+func matchCert(rule *config.CertRule, cert *x509.Certificate) bool {
+	if len(rule.CommonName) > 0 && !matchPattern(rule.CommonName, cert.Subject.CommonName) {
+		return false
+	}
+	if len(rule.SAN) == 0 {
+		return true
+	}
+	for _, san := range certSANs(cert) {
+		if matchPattern(rule.SAN, san) {
+			return true
+		}
+	}
+	return false
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs))
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func matchPattern(pattern, s string) bool {
+	ok, err := path.Match(pattern, s)
+	if err != nil {
+		log.Warn(fmt.Sprintf("invalid certificate rule pattern [%s]", pattern))
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestAuthenticateCert(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestAuthenticateCert_account"
+	a := newAccount(name)
+	a.Roles = []string{rbac.RoleDeveloper}
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	old := config.App.RBAC
+	defer func() { config.App.RBAC = old }()
+	config.App.RBAC = &config.RBAC{CertAuth: &config.CertAuth{
+		Rules: []*config.CertRule{
+			{SAN: "spiffe://cluster.local/ns/*/sa/sidecar", Account: name},
+			{CommonName: "gateway-*", Roles: []string{rbac.RoleAdmin}},
+			{CommonName: "ghost", Account: "TestAuthenticateCert_none"},
+		},
+	}}
+	spiffe, _ := url.Parse("spiffe://cluster.local/ns/default/sa/sidecar")
+	sidecar := &x509.Certificate{Subject: pkix.Name{CommonName: "sidecar"}, URIs: []*url.URL{spiffe}}
+
+	t.Run("disabled, should fail", func(t *testing.T) {
+		assert.False(t, rbacsvc.CertAuthEnabled())
+		_, err := rbacsvc.AuthenticateCert(ctx, sidecar)
+		assert.Error(t, err)
+	})
+	config.App.RBAC.CertAuth.Enabled = true
+	t.Run("san mapped to account, should use the account roles", func(t *testing.T) {
+		assert.True(t, rbacsvc.CertAuthEnabled())
+		claims, err := rbacsvc.AuthenticateCert(ctx, sidecar)
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, name, account.Name)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+	})
+	t.Run("common name mapped to roles, should use the prefixed common name", func(t *testing.T) {
+		claims, err := rbacsvc.AuthenticateCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "gateway-1"}})
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, rbacsvc.CertUserPrefix+"gateway-1", account.Name)
+		assert.Equal(t, []string{rbac.RoleAdmin}, account.Roles)
+	})
+	t.Run("mapped account not exist, should fail", func(t *testing.T) {
+		_, err := rbacsvc.AuthenticateCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "ghost"}})
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUnauthorized, err.(*errsvc.Error).Code)
+	})
+	t.Run("no rule matched, should fail", func(t *testing.T) {
+		_, err := rbacsvc.AuthenticateCert(ctx, &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}})
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUnauthorized, err.(*errsvc.Error).Code)
+	})
+}
@@ -19,18 +19,20 @@ package rbac
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-chassis/cari/rbac"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 )
 
+// Allow checks the permissions of the roles in the domain of ctx and the project,
 // return: allow, matched labels(empty if no label defined), error
 func Allow(ctx context.Context, project string, roleList []string,
 	targetResouce *auth.ResourceScope) (bool, []map[string]string, error) {
-	//TODO check project
 	allPerms, err := getPermsByRoles(ctx, roleList)
 	if err != nil {
 		log.Error("get role list errors", err)
@@ -40,6 +42,12 @@ func Allow(ctx context.Context, project string, roleList []string,
 		log.Warn("role list has no any permissions")
 		return false, nil, nil
 	}
+	domain := util.ParseDomain(ctx)
+	allPerms = ScopePerms(allPerms, domain, project)
+	if len(allPerms) == 0 {
+		log.Warn(fmt.Sprintf("role list has no permissions in %s/%s", domain, project))
+		return false, nil, nil
+	}
 	allow, labelList := GetLabel(allPerms, targetResouce.Type, targetResouce.Verb)
 	if !allow {
 		return false, nil, nil
@@ -82,20 +90,35 @@ func LabelMatched(targetResourceLabel map[string]string, permLabel map[string]st
 	return true
 }
 
+// getPermsByRoles returns the permissions of the roles and the roles
+// they inherit
 func getPermsByRoles(ctx context.Context, roleList []string) ([]*rbac.Permission, error) {
 	var allPerms = make([]*rbac.Permission, 0)
-	for _, name := range roleList {
-		r, err := datasource.GetRoleManager().GetRole(ctx, name)
-		if err == nil {
-			allPerms = append(allPerms, r.Perms...)
+	visited := make(map[string]struct{}, len(roleList))
+	queue := append([]string{}, roleList...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[name]; ok {
 			continue
 		}
+		visited[name] = struct{}{}
+		r, err := datasource.GetRoleManager().GetRole(ctx, name)
 		if err == datasource.ErrRoleNotExist {
 			log.Warnf("role [%s] not exist", name)
 			continue
 		}
-		log.Errorf(err, "get role [%s] failed", name)
-		return nil, err
+		if err != nil {
+			log.Errorf(err, "get role [%s] failed", name)
+			return nil, err
+		}
+		allPerms = append(allPerms, r.Perms...)
+		parents, err := datasource.GetRoleManager().GetRoleParents(ctx, name)
+		if err != nil {
+			log.Errorf(err, "get the parents of role [%s] failed", name)
+			return nil, err
+		}
+		queue = append(queue, parents...)
 	}
 	return allPerms, nil
 }
@@ -18,11 +18,16 @@
 package rbac_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
 	"github.com/go-chassis/cari/rbac"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 )
 
@@ -189,3 +194,103 @@ func TestFilterLabel(t *testing.T) {
 	l := rbacsvc.FilterLabel(targetResourceLabel, permResourceLabel)
 	assert.Equal(t, 3, len(l))
 }
+
+func TestScopePerms(t *testing.T) {
+	perms := []*rbac.Permission{
+		{
+			Resources: []*rbac.Resource{
+				{
+					Type:   rbacsvc.ResourceService,
+					Labels: map[string]string{rbacsvc.LabelProject: "p1", "appId": "default"},
+				},
+				{
+					Type:   rbacsvc.ResourceSchema,
+					Labels: map[string]string{rbacsvc.LabelDomain: "default", rbacsvc.LabelProject: "prod-*"},
+				},
+			},
+			Verbs: []string{"*"},
+		},
+		{
+			Resources: []*rbac.Resource{
+				{
+					Type: rbacsvc.ResourceGovern,
+				},
+			},
+			Verbs: []string{"get"},
+		},
+	}
+
+	t.Run("project matched, should keep the resource without scope labels", func(t *testing.T) {
+		scoped := rbacsvc.ScopePerms(perms, "default", "p1")
+		assert.Equal(t, 2, len(scoped))
+		assert.Equal(t, 1, len(scoped[0].Resources))
+		assert.Equal(t, map[string]string{"appId": "default"}, scoped[0].Resources[0].Labels)
+		assert.Equal(t, "p1", perms[0].Resources[0].Labels[rbacsvc.LabelProject])
+	})
+
+	t.Run("project matched by wildcard, should keep the resource", func(t *testing.T) {
+		scoped := rbacsvc.ScopePerms(perms, "default", "prod-1")
+		assert.Equal(t, 2, len(scoped))
+		assert.Equal(t, rbacsvc.ResourceSchema, scoped[0].Resources[0].Type)
+		assert.Nil(t, scoped[0].Resources[0].Labels)
+	})
+
+	t.Run("domain or project not matched, should drop the resources", func(t *testing.T) {
+		scoped := rbacsvc.ScopePerms(perms, "other", "prod-1")
+		assert.Equal(t, 1, len(scoped))
+		assert.Equal(t, rbacsvc.ResourceGovern, scoped[0].Resources[0].Type)
+		scoped = rbacsvc.ScopePerms(perms, "default", "")
+		assert.Equal(t, 1, len(scoped))
+	})
+}
+
+func TestAllow(t *testing.T) {
+	role := &rbac.Role{
+		Name: "TestAllow",
+		Perms: []*rbac.Permission{
+			{
+				Resources: []*rbac.Resource{
+					{
+						Type:   rbacsvc.ResourceService,
+						Labels: map[string]string{rbacsvc.LabelProject: "p1"},
+					},
+				},
+				Verbs: []string{"*"},
+			},
+		},
+	}
+	err := rbacsvc.CreateRole(context.TODO(), role)
+	assert.NoError(t, err)
+	defer rbacsvc.DeleteRole(context.TODO(), role.Name)
+	ctx := util.SetDomain(context.TODO(), "default")
+	target := &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "get"}
+
+	t.Run("in the bound project, should allow", func(t *testing.T) {
+		allow, labels, err := rbacsvc.Allow(ctx, "p1", []string{role.Name}, target)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+		assert.Empty(t, labels)
+	})
+
+	t.Run("in another project, should not allow", func(t *testing.T) {
+		allow, _, err := rbacsvc.Allow(ctx, "p2", []string{role.Name}, target)
+		assert.NoError(t, err)
+		assert.False(t, allow)
+	})
+
+	t.Run("create role with invalid project pattern, should be failed", func(t *testing.T) {
+		err := rbacsvc.CreateRole(context.TODO(), &rbac.Role{
+			Name: "TestAllowInvalid",
+			Perms: []*rbac.Permission{
+				{
+					Resources: []*rbac.Resource{
+						{Type: rbacsvc.ResourceService, Labels: map[string]string{rbacsvc.LabelProject: "p["}},
+					},
+					Verbs: []string{"*"},
+				},
+			},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, discovery.ErrInvalidParams, err.(*errsvc.Error).Code)
+	})
+}
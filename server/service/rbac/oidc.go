@@ -0,0 +1,323 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
+	"github.com/apache/servicecomb-service-center/pkg/jwks"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	OIDCPlugin = "oidc"
+	// OIDCAccountStatus is the status of the accounts provisioned by the
+	// identity provider, the roles of other accounts are never synchronized
+	OIDCAccountStatus = "oidc"
+
+	defaultUserClaim           = "sub"
+	defaultJWKSRefreshInterval = 10 * time.Minute
+	// minJWKSRefreshInterval limits the reloading caused by unknown key ids
+	minJWKSRefreshInterval = 10 * time.Second
+)
+
+var (
+	ErrEmptyIssuer = errors.New("oidc issuer is required")
+	ErrEmptyJWKS   = errors.New("oidc jwks is required")
+
+	oidcMethods = []string{
+		jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+		jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+	}
+)
+
+// OIDCAuthenticator validates the tokens issued by the external identity
+// provider, the other tokens and the login are handled by the embedded
+// authenticator, so the local accounts like root still work
+type OIDCAuthenticator struct {
+	*EmbeddedAuthenticator
+	opts            *config.OIDC
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mux      sync.Mutex
+	keys     *jwks.KeySet
+	loadedAt time.Time
+}
+
+func newOIDCAuthenticator(opts *authr.Options) (authr.Authenticator, error) {
+	return NewOIDCAuthenticator(config.GetOIDC())
+}
+
+func NewOIDCAuthenticator(opts *config.OIDC) (*OIDCAuthenticator, error) {
+	if opts == nil || len(opts.Issuer) == 0 {
+		return nil, ErrEmptyIssuer
+	}
+	if len(opts.JWKS) == 0 {
+		return nil, ErrEmptyJWKS
+	}
+	a := &OIDCAuthenticator{
+		EmbeddedAuthenticator: &EmbeddedAuthenticator{},
+		opts:                  opts,
+		refreshInterval:       defaultJWKSRefreshInterval,
+		client:                &http.Client{Timeout: 10 * time.Second},
+	}
+	if len(opts.JWKSRefreshInterval) > 0 {
+		d, err := time.ParseDuration(opts.JWKSRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oidc jwksRefreshInterval, %s", err.Error())
+		}
+		a.refreshInterval = d
+	}
+	// fail fast if the key set is unavailable
+	if _, err := a.keySet(false); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Authenticate validates the token issued by the identity provider and
+// returns the claims with the mapped roles
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, tokenStr string) (interface{}, error) {
+	if !a.issuedBy(tokenStr) {
+		return a.EmbeddedAuthenticator.Authenticate(ctx, tokenStr)
+	}
+	parser := &jwt.Parser{ValidMethods: oidcMethods}
+	token, err := parser.Parse(tokenStr, a.key)
+	if err != nil {
+		if a.isTokenExpiredError(err) {
+			return nil, rbac.NewError(rbac.ErrTokenExpired, "")
+		}
+		return nil, rbac.NewError(rbac.ErrUnauthorized, err.Error())
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, rbac.ErrConvert
+	}
+	if len(a.opts.Audience) > 0 && !claims.VerifyAudience(a.opts.Audience, true) {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, "invalid audience")
+	}
+	userClaim := a.opts.UserClaim
+	if len(userClaim) == 0 {
+		userClaim = defaultUserClaim
+	}
+	name, _ := claimValue(claims, userClaim).(string)
+	if len(name) == 0 {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, fmt.Sprintf("claim [%s] is required", userClaim))
+	}
+	roles := a.mapRoles(claimValue(claims, a.opts.RolesClaim))
+	if len(roles) == 0 {
+		return nil, rbac.NewError(rbac.ErrNoPermission, fmt.Sprintf("no role is mapped for [%s]", name))
+	}
+	if roles, err = a.ensureAccount(ctx, name, roles); err != nil {
+		return nil, err
+	}
+	if err = checkTokenHashRevoked(ctx, tokenStr); err != nil {
+		return nil, err
+	}
+
+	var roleList []interface{}
+	for _, r := range roles {
+		roleList = append(roleList, r)
+	}
+	result := map[string]interface{}{
+		rbac.ClaimsUser:  name,
+		rbac.ClaimsRoles: roleList,
+	}
+	if exp, ok := claims["exp"]; ok {
+		result["exp"] = exp
+	}
+	return result, nil
+}
+
+// issuedBy checks the issuer without verification, the token is verified
+// by the authenticator of the issuer
+func (a *OIDCAuthenticator) issuedBy(tokenStr string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenStr, claims); err != nil {
+		return false
+	}
+	iss, _ := claims["iss"].(string)
+	return iss == a.opts.Issuer
+}
+
+func (a *OIDCAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	ks, err := a.keySet(false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.Key(kid)
+	if err == jwks.ErrKeyNotFound {
+		// the keys may be rotated by the identity provider
+		if ks, err = a.keySet(true); err != nil {
+			return nil, err
+		}
+		key, err = ks.Key(kid)
+	}
+	return key, err
+}
+
+func (a *OIDCAuthenticator) keySet(reload bool) (*jwks.KeySet, error) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	age := time.Since(a.loadedAt)
+	if a.keys != nil && age < a.refreshInterval && (!reload || age < minJWKSRefreshInterval) {
+		return a.keys, nil
+	}
+	ks, err := jwks.Load(context.Background(), a.client, a.opts.JWKS)
+	if err != nil {
+		log.Error(fmt.Sprintf("load oidc jwks from %s failed", a.opts.JWKS), err)
+		if a.keys != nil {
+			// keep using the old keys if the identity provider is unavailable
+			return a.keys, nil
+		}
+		return nil, err
+	}
+	a.keys, a.loadedAt = ks, time.Now()
+	return ks, nil
+}
+
+// mapRoles returns the service center roles of the claim values, the
+// values not in the mapping are ignored
+func (a *OIDCAuthenticator) mapRoles(v interface{}) []string {
+	var values []string
+	switch t := v.(type) {
+	case string:
+		values = strings.Fields(t)
+	case []interface{}:
+		for _, i := range t {
+			if s, ok := i.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	var roles []string
+	for _, value := range values {
+		for _, r := range a.opts.RoleMapping[value] {
+			if !util.SliceHave(roles, r) {
+				roles = append(roles, r)
+			}
+		}
+	}
+	return roles
+}
+
+// ensureAccount checks the account of the token exists, creates it if auto
+// provision is enabled, the roles of the provisioned account follow the
+// identity provider. It returns the roles granted to the token, which are
+// the stored roles if the account is managed locally. The built-in root
+// account can not be used by the identity provider
+func (a *OIDCAuthenticator) ensureAccount(ctx context.Context, name string, roles []string) ([]string, error) {
+	if name == RootName {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, errorsEx.MsgCantOperateRoot)
+	}
+	account, err := datasource.GetAccountManager().GetAccount(ctx, name)
+	if err != nil && err != datasource.ErrAccountNotExist {
+		return nil, err
+	}
+	if !a.opts.AutoProvision {
+		if account == nil {
+			return nil, rbac.NewError(rbac.ErrUnauthorized, fmt.Sprintf("account [%s] not exist", name))
+		}
+		return account.Roles, nil
+	}
+	if account != nil {
+		if account.Status != OIDCAccountStatus {
+			// the local account is not managed by the identity provider
+			return nil, rbac.NewError(rbac.ErrUnauthorized, fmt.Sprintf("account [%s] is not provisioned by oidc", name))
+		}
+		if sameRoles(account.Roles, roles) {
+			return roles, nil
+		}
+		account.Roles = roles
+		err = datasource.GetAccountManager().UpdateAccount(ctx, name, account)
+		if err != nil {
+			log.Error(fmt.Sprintf("sync roles of oidc account [%s] failed", name), err)
+			return nil, err
+		}
+		return roles, nil
+	}
+	pwd, err := randomPassword()
+	if err != nil {
+		return nil, err
+	}
+	err = datasource.GetAccountManager().CreateAccount(ctx, &rbac.Account{
+		Name:     name,
+		Password: pwd,
+		Roles:    roles,
+		Status:   OIDCAccountStatus,
+	})
+	if err != nil && err != datasource.ErrAccountDuplicated {
+		log.Error(fmt.Sprintf("provision oidc account [%s] failed", name), err)
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("oidc account [%s] is provisioned", name))
+	return roles, nil
+}
+
+// claimValue returns the value of the claim, nested claims are separated
+// by dot
+func claimValue(claims map[string]interface{}, name string) interface{} {
+	if len(name) == 0 {
+		return nil
+	}
+	if v, ok := claims[name]; ok {
+		return v
+	}
+	keys := strings.Split(name, ".")
+	var v interface{} = claims
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// randomPassword generates an unknown password for the provisioned account,
+// the account logs in through the identity provider
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func init() {
+	authr.Install(OIDCPlugin, newOIDCAuthenticator)
+}
@@ -0,0 +1,198 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func writeJWKS(t *testing.T, key *rsa.PrivateKey) string {
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	data := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"k1","use":"sig","n":"%s","e":"%s"}]}`, n, e)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(data), 0600))
+	return file
+}
+
+func signIDToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "k1"
+	s, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return s
+}
+
+func idClaims(user string, groups ...interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                testIssuer,
+		"aud":                "service-center",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": user,
+		"realm_access":       map[string]interface{}{"roles": groups},
+	}
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	opts := &config.OIDC{
+		Issuer:     testIssuer,
+		Audience:   "service-center",
+		JWKS:       writeJWKS(t, key),
+		UserClaim:  "preferred_username",
+		RolesClaim: "realm_access.roles",
+		RoleMapping: map[string][]string{
+			"sc-admins": {rbac.RoleAdmin},
+			"devs":      {rbac.RoleDeveloper},
+		},
+	}
+
+	t.Run("invalid options, should fail", func(t *testing.T) {
+		_, err := rbacsvc.NewOIDCAuthenticator(&config.OIDC{JWKS: opts.JWKS})
+		assert.Equal(t, rbacsvc.ErrEmptyIssuer, err)
+		_, err = rbacsvc.NewOIDCAuthenticator(&config.OIDC{Issuer: testIssuer})
+		assert.Equal(t, rbacsvc.ErrEmptyJWKS, err)
+		_, err = rbacsvc.NewOIDCAuthenticator(&config.OIDC{Issuer: testIssuer, JWKS: "not_exist.json"})
+		assert.Error(t, err)
+	})
+
+	a, err := rbacsvc.NewOIDCAuthenticator(opts)
+	assert.NoError(t, err)
+	name := "TestOIDCAuthenticator_account"
+	account := newAccount(name)
+	account.Roles = []string{rbac.RoleDeveloper}
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, account))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	t.Run("valid token without auto provision, should return the account roles", func(t *testing.T) {
+		claims, err := a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "devs", "sc-admins", "others")))
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, name, account.Name)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+	})
+	t.Run("no role is mapped, should fail", func(t *testing.T) {
+		_, err := a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "others")))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNoPermission))
+	})
+	t.Run("invalid token, should fail", func(t *testing.T) {
+		claims := idClaims(name, "devs")
+		claims["aud"] = "others"
+		_, err := a.Authenticate(ctx, signIDToken(t, key, claims))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+
+		claims = idClaims(name, "devs")
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err = a.Authenticate(ctx, signIDToken(t, key, claims))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrTokenExpired))
+
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		_, err = a.Authenticate(ctx, signIDToken(t, other, idClaims(name, "devs")))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+
+		hs := jwt.NewWithClaims(jwt.SigningMethodHS256, idClaims(name, "devs"))
+		s, err := hs.SignedString([]byte("secret"))
+		assert.NoError(t, err)
+		_, err = a.Authenticate(ctx, s)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+	})
+	t.Run("account not exist without auto provision, should fail", func(t *testing.T) {
+		_, err := a.Authenticate(ctx, signIDToken(t, key, idClaims("TestOIDCAuthenticator_none", "devs")))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+	})
+	t.Run("account not exist with auto provision, should create it", func(t *testing.T) {
+		provisioned := *opts
+		provisioned.AutoProvision = true
+		a, err := rbacsvc.NewOIDCAuthenticator(&provisioned)
+		assert.NoError(t, err)
+		name := "TestOIDCAuthenticator_provisioned"
+		defer rbacsvc.DeleteAccount(ctx, name)
+
+		claims, err := a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "devs", "sc-admins")))
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{rbac.RoleDeveloper, rbac.RoleAdmin}, account.Roles)
+
+		_, err = a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "devs")))
+		assert.NoError(t, err)
+		account, err = rbacsvc.GetAccount(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+		assert.Equal(t, rbacsvc.OIDCAccountStatus, account.Status)
+
+		_, err = a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "sc-admins")))
+		assert.NoError(t, err)
+		account, err = rbacsvc.GetAccount(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{rbac.RoleAdmin}, account.Roles)
+	})
+	t.Run("local account with auto provision, should not sync the roles", func(t *testing.T) {
+		provisioned := *opts
+		provisioned.AutoProvision = true
+		a, err := rbacsvc.NewOIDCAuthenticator(&provisioned)
+		assert.NoError(t, err)
+
+		_, err = a.Authenticate(ctx, signIDToken(t, key, idClaims(name, "sc-admins")))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+		account, err := rbacsvc.GetAccount(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+
+		_, err = a.Authenticate(ctx, signIDToken(t, key, idClaims(rbacsvc.RootName, "sc-admins")))
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+	})
+	t.Run("token of other issuer, should use the embedded authenticator", func(t *testing.T) {
+		token, err := a.Login(ctx, name, testPwd0)
+		assert.NoError(t, err)
+		claims, err := a.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, name, claims.(map[string]interface{})[rbac.ClaimsUser])
+	})
+	t.Run("logout, the token should be revoked", func(t *testing.T) {
+		token := signIDToken(t, key, idClaims(name, "devs"))
+		claims, err := a.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.NoError(t, rbacsvc.RevokeToken(ctx, token, claims.(map[string]interface{})))
+		_, err = a.Authenticate(ctx, token)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+	})
+}
@@ -37,6 +37,10 @@ func ChangePassword(ctx context.Context, a *rbac.Account) error {
 		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
 	}
 
+	if err = checkPasswordPolicy(a.Name, a.Password); err != nil {
+		return err
+	}
+
 	changer, err := AccountFromContext(ctx)
 	if err != nil {
 		return discovery.NewError(discovery.ErrInternal, err.Error())
@@ -95,6 +99,10 @@ func changePassword(ctx context.Context, name, currentPassword, pwd string) erro
 }
 
 func doChangePassword(ctx context.Context, old *rbac.Account, pwd string) error {
+	err := checkPasswordReused(ctx, old.Name, pwd)
+	if err != nil {
+		return err
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(pwd), 14)
 	if err != nil {
 		log.Error("pwd hash failed", err)
@@ -106,5 +114,9 @@ func doChangePassword(ctx context.Context, old *rbac.Account, pwd string) error
 		log.Error("can not change pwd", err)
 		return err
 	}
-	return nil
+	if err = recordPassword(ctx, old.Name, pwd); err != nil {
+		log.Error("can not record pwd", err)
+		return err
+	}
+	return RevokeAccountTokens(ctx, old.Name)
 }
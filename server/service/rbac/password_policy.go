@@ -0,0 +1,293 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/privacy"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+// ErrPasswordExpired is returned by login when the password is older than
+// the max age, the account must change the password before login
+const ErrPasswordExpired int32 = 403250
+
+// PasswordExpiredTokenTTL is the lifetime of the token issued to the
+// account with an expired password
+const PasswordExpiredTokenTTL = "5m"
+
+// the common passwords which satisfy the default character classes
+var defaultBannedPasswords = []string{
+	"P@ssw0rd", "P@55w0rd", "Passw0rd!", "Password@123", "Password1!",
+	"Admin@123", "Admin@1234", "Root@123", "Qwerty@123", "Welcome@123",
+	"Abc@1234", "Aa123456!", "Changeme@123", "Test@123", "Test@1234",
+	"1qaz@WSX", "1qaz!QAZ", "Zaq1@wsx",
+}
+
+var (
+	bannedMux   sync.Mutex
+	bannedPath  string
+	bannedWords map[string]struct{}
+)
+
+func init() {
+	rbac.MustRegisterErr(ErrPasswordExpired, "Password is expired")
+}
+
+// PasswordPolicy is the rule of the account passwords, configured by
+// rbac.passwordPolicy
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	// MinCharClasses is the least number of the character classes: upper,
+	// lower, digit and special
+	MinCharClasses int
+	// HistorySize is the number of the recent passwords can not be reused
+	HistorySize int
+	// MaxAge is the max age of the password, 0 means never expire
+	MaxAge time.Duration
+}
+
+func GetPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:      config.GetInt("rbac.passwordPolicy.minLength", 8),
+		MaxLength:      config.GetInt("rbac.passwordPolicy.maxLength", 32),
+		MinCharClasses: config.GetInt("rbac.passwordPolicy.minCharClasses", 4),
+		HistorySize:    config.GetInt("rbac.passwordPolicy.historySize", 0),
+		MaxAge:         config.GetDuration("rbac.passwordPolicy.maxAge", 0),
+	}
+}
+
+// Check returns the first rule the password violates
+func (p *PasswordPolicy) Check(name, pwd string) error {
+	n := len([]rune(pwd))
+	if n < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return fmt.Errorf("password must be at most %d characters", p.MaxLength)
+	}
+	if classes := charClasses(pwd); classes < p.MinCharClasses {
+		return fmt.Errorf("password must contain at least %d of upper case letters, "+
+			"lower case letters, digits and special characters", p.MinCharClasses)
+	}
+	if pwd == name {
+		return rbac.ErrSameAsName
+	}
+	if pwd == reverse(name) {
+		return rbac.ErrSameAsReversedName
+	}
+	if isBannedPassword(pwd) {
+		return fmt.Errorf("password is too common")
+	}
+	return nil
+}
+
+// Expired returns true if the password changed at changeTime is too old
+func (p *PasswordPolicy) Expired(changeTime int64) bool {
+	if p.MaxAge <= 0 || changeTime <= 0 {
+		return false
+	}
+	return time.Since(time.Unix(changeTime, 0)) > p.MaxAge
+}
+
+func charClasses(pwd string) int {
+	var upper, lower, digit, special int
+	for _, c := range pwd {
+		switch {
+		case unicode.IsUpper(c):
+			upper = 1
+		case unicode.IsLower(c):
+			lower = 1
+		case unicode.IsNumber(c):
+			digit = 1
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			special = 1
+		}
+	}
+	return upper + lower + digit + special
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func isBannedPassword(pwd string) bool {
+	_, ok := bannedPasswords()[strings.ToLower(pwd)]
+	return ok
+}
+
+// bannedPasswords returns the built-in banned passwords and the ones in
+// rbac.passwordPolicy.bannedPasswordsFile, one password per line
+func bannedPasswords() map[string]struct{} {
+	file := config.GetString("rbac.passwordPolicy.bannedPasswordsFile", "")
+	bannedMux.Lock()
+	defer bannedMux.Unlock()
+	if bannedWords != nil && bannedPath == file {
+		return bannedWords
+	}
+	words := make(map[string]struct{}, len(defaultBannedPasswords))
+	for _, w := range defaultBannedPasswords {
+		words[strings.ToLower(w)] = struct{}{}
+	}
+	if len(file) > 0 {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Error(fmt.Sprintf("can not read banned passwords file %s", file), err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			w := strings.TrimSpace(scanner.Text())
+			if len(w) > 0 && !strings.HasPrefix(w, "#") {
+				words[strings.ToLower(w)] = struct{}{}
+			}
+		}
+	}
+	bannedPath, bannedWords = file, words
+	return words
+}
+
+func checkPasswordPolicy(name, pwd string) error {
+	if err := GetPasswordPolicy().Check(name, pwd); err != nil {
+		return rbac.NewError(rbac.ErrNewPwdBad, err.Error())
+	}
+	return nil
+}
+
+// checkPasswordReused returns an error if pwd is one of the recent
+// passwords of the account
+func checkPasswordReused(ctx context.Context, name, pwd string) error {
+	size := GetPasswordPolicy().HistorySize
+	if size <= 0 {
+		return nil
+	}
+	h, err := getPasswordHistory(ctx, name)
+	if err != nil {
+		return err
+	}
+	for i, hash := range h.Hashes {
+		if i >= size {
+			break
+		}
+		if privacy.SamePassword(hash, pwd) {
+			return rbac.NewError(rbac.ErrNewPwdBad,
+				fmt.Sprintf("password can not be the same as the last %d passwords", size))
+		}
+	}
+	return nil
+}
+
+// recordPassword saves the hash of the new password at the head of the
+// history, only the recent passwords are kept
+func recordPassword(ctx context.Context, name, pwd string) error {
+	h, err := getPasswordHistory(ctx, name)
+	if err != nil {
+		return err
+	}
+	hashes := h.Hashes
+	size := GetPasswordPolicy().HistorySize
+	if size <= 0 {
+		hashes = nil
+	} else {
+		hash, err := privacy.ScryptPassword(pwd)
+		if err != nil {
+			log.Error("pwd hash failed", err)
+			return err
+		}
+		hashes = append([]string{hash}, hashes...)
+		if len(hashes) > size {
+			hashes = hashes[:size]
+		}
+	}
+	return datasource.GetPasswordHistoryManager().PutPasswordHistory(ctx, &datasource.PasswordHistory{
+		Account:    name,
+		Hashes:     hashes,
+		ChangeTime: time.Now().Unix(),
+	})
+}
+
+func getPasswordHistory(ctx context.Context, name string) (*datasource.PasswordHistory, error) {
+	h, err := datasource.GetPasswordHistoryManager().GetPasswordHistory(ctx, name)
+	if err == datasource.ErrPasswordHistoryNotExist {
+		return &datasource.PasswordHistory{Account: name}, nil
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("can not get password history of account %s", name), err)
+		return nil, err
+	}
+	return h, nil
+}
+
+// checkPasswordExpired returns ErrPasswordExpired if the password of the
+// account is older than the max age. The accounts without history use
+// the last update time of the account
+func checkPasswordExpired(ctx context.Context, a *rbac.Account) error {
+	p := GetPasswordPolicy()
+	if p.MaxAge <= 0 {
+		return nil
+	}
+	h, err := getPasswordHistory(ctx, a.Name)
+	if err != nil {
+		return err
+	}
+	changeTime := h.ChangeTime
+	if changeTime == 0 {
+		changeTime = parseUnixTime(a.UpdateTime)
+	}
+	if changeTime == 0 {
+		changeTime = parseUnixTime(a.CreateTime)
+	}
+	if p.Expired(changeTime) {
+		return rbac.NewError(ErrPasswordExpired,
+			fmt.Sprintf("password is older than %s, change it before login", p.MaxAge))
+	}
+	return nil
+}
+
+func parseUnixTime(s string) int64 {
+	t, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return t
+}
+
+func deletePasswordHistory(ctx context.Context, name string) {
+	err := datasource.GetPasswordHistoryManager().DeletePasswordHistory(ctx, name)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete password history of account %s", name), err)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-archaius"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	p := rbacsvc.GetPasswordPolicy()
+	assert.Equal(t, 8, p.MinLength)
+	assert.Equal(t, 4, p.MinCharClasses)
+
+	t.Run("check the policy", func(t *testing.T) {
+		assert.NoError(t, p.Check("tester", testPwd0))
+		assert.Error(t, p.Check("tester", "Ab@0"))
+		assert.Error(t, p.Check("tester", "Ab@000000000000000000000000000000"))
+		assert.Error(t, p.Check("tester", "Ab000000"))
+		assert.Error(t, p.Check("Ab@00000", "Ab@00000"))
+		assert.Error(t, p.Check("00000@bA", "Ab@00000"))
+		assert.Error(t, p.Check("tester", "p@ssW0RD"))
+
+		policy := &rbacsvc.PasswordPolicy{MinLength: 6, MinCharClasses: 2}
+		assert.NoError(t, policy.Check("tester", "abc123"))
+	})
+	t.Run("create account with a bad password, should fail", func(t *testing.T) {
+		a := newAccount("TestPasswordPolicy_bad_password")
+		a.Password = "Admin@123"
+		err := rbacsvc.CreateAccount(ctx, a)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNewPwdBad))
+		assert.Contains(t, err.Error(), "too common")
+
+		a.Password = "abcdefgh"
+		err = rbacsvc.CreateAccount(ctx, a)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNewPwdBad))
+		assert.Contains(t, err.Error(), "at least 4 of")
+	})
+	t.Run("banned passwords file", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "banned")
+		assert.NoError(t, err)
+		defer os.Remove(f.Name())
+		_, err = f.WriteString("# banned\nab@22222\n")
+		assert.NoError(t, err)
+		f.Close()
+
+		assert.NoError(t, p.Check("tester", "Ab@22222"))
+		archaius.Set("rbac.passwordPolicy.bannedPasswordsFile", f.Name())
+		defer archaius.Delete("rbac.passwordPolicy.bannedPasswordsFile")
+		assert.Error(t, p.Check("tester", "Ab@22222"))
+		assert.Error(t, p.Check("tester", "P@ssw0rd"))
+	})
+	t.Run("change to a bad password, should fail", func(t *testing.T) {
+		name := "TestPasswordPolicy_change_password"
+		assert.NoError(t, rbacsvc.CreateAccount(ctx, newAccount(name)))
+		defer rbacsvc.DeleteAccount(ctx, name)
+
+		err := rbacsvc.ChangePassword(ctx, &rbac.Account{Name: name, CurrentPassword: testPwd0, Password: "Ab@0"})
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNewPwdBad))
+		assert.Contains(t, err.Error(), "at least 8 characters")
+	})
+}
+
+func TestPasswordHistory(t *testing.T) {
+	ctx := context.Background()
+	archaius.Set("rbac.passwordPolicy.historySize", 2)
+	defer archaius.Delete("rbac.passwordPolicy.historySize")
+
+	name := "TestPasswordHistory_account"
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, newAccount(name)))
+	defer rbacsvc.DeleteAccount(ctx, name)
+	adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, map[string]interface{}{
+		rbac.ClaimsUser:  "root",
+		rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+	})
+	change := func(pwd string) error {
+		return rbacsvc.ChangePassword(adminCtx, &rbac.Account{Name: name, Password: pwd})
+	}
+
+	t.Run("reuse the recent passwords, should fail", func(t *testing.T) {
+		err := change(testPwd0)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNewPwdBad))
+		assert.NoError(t, change(testPwd1))
+		err = change(testPwd0)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrNewPwdBad))
+		assert.Contains(t, err.Error(), "last 2 passwords")
+	})
+	t.Run("reuse the password out of history, should succeed", func(t *testing.T) {
+		assert.NoError(t, change("Ab@22222"))
+		assert.NoError(t, change(testPwd0))
+	})
+	t.Run("delete account, the history should be removed", func(t *testing.T) {
+		assert.NoError(t, rbacsvc.DeleteAccount(ctx, name))
+		_, err := datasource.GetPasswordHistoryManager().GetPasswordHistory(ctx, name)
+		assert.Equal(t, datasource.ErrPasswordHistoryNotExist, err)
+	})
+}
+
+func TestPasswordExpired(t *testing.T) {
+	ctx := context.Background()
+	archaius.Set("rbac.passwordPolicy.maxAge", "1h")
+	defer archaius.Delete("rbac.passwordPolicy.maxAge")
+
+	name := "TestPasswordExpired_account"
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, newAccount(name)))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	_, err := authr.Login(ctx, name, testPwd0)
+	assert.NoError(t, err)
+
+	var expiredToken string
+	t.Run("login with an expired password, should fail", func(t *testing.T) {
+		err := datasource.GetPasswordHistoryManager().PutPasswordHistory(ctx, &datasource.PasswordHistory{
+			Account:    name,
+			ChangeTime: time.Now().Add(-2 * time.Hour).Unix(),
+		})
+		assert.NoError(t, err)
+		expiredToken, err = authr.Login(ctx, name, testPwd0)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbacsvc.ErrPasswordExpired))
+		assert.NotEmpty(t, expiredToken)
+	})
+	t.Run("change the expired password with the token, should succeed", func(t *testing.T) {
+		claims, err := authr.Authenticate(ctx, expiredToken)
+		assert.NoError(t, err)
+		m := claims.(map[string]interface{})
+		assert.Equal(t, true, m[rbacsvc.ClaimsPasswordExpired])
+		assert.Empty(t, m[rbac.ClaimsRoles])
+		tokenCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, m)
+
+		err = rbacsvc.ChangePassword(tokenCtx, &rbac.Account{Name: name, CurrentPassword: testPwd1, Password: "Ab@22222"})
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrOldPwdWrong))
+		err = rbacsvc.ChangePassword(tokenCtx, &rbac.Account{Name: name, CurrentPassword: testPwd0, Password: testPwd1})
+		assert.NoError(t, err)
+		_, err = authr.Login(ctx, name, testPwd1)
+		assert.NoError(t, err)
+		_, err = authr.Authenticate(ctx, expiredToken)
+		assert.Error(t, err)
+	})
+}
@@ -55,7 +55,7 @@ func Init() {
 		return
 	}
 	InitResourceMap()
-	err := authr.Init()
+	err := authr.Init(authr.WithPlugin(config.GetString("rbac.authenticator", "default")))
 	if err != nil {
 		log.Fatal("can not enable auth module", err)
 	}
@@ -177,5 +177,5 @@ func GetPrivateKey() (*rsa.PrivateKey, error) {
 
 //MakeBanKey return ban key
 func MakeBanKey(name, ip string) string {
-	return name + "::" + ip
+	return name + banKeySeparator + ip
 }
@@ -39,6 +39,10 @@ var (
 
 	APIAccountPassword = "/v4/accounts/:name/password"
 
+	APIAccountLocks = "/v4/account-locks"
+
+	APISelf = "/v4/self/"
+
 	APIOps = "/v4/:project/admin"
 
 	APIGov = "/v1/:project/gov/"
@@ -68,6 +72,7 @@ var (
 
 func InitResourceMap() {
 	rbac.PartialMapResource(APIAccountList, ResourceAccount)
+	rbac.PartialMapResource(APIAccountLocks, ResourceAccount)
 
 	rbac.PartialMapResource(APIRoleList, ResourceRole)
 
@@ -20,6 +20,8 @@ package rbac
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/rbac"
@@ -32,18 +34,43 @@ import (
 	"github.com/apache/servicecomb-service-center/server/service/validator"
 )
 
-func CreateRole(ctx context.Context, r *rbac.Role) error {
+var ErrRoleCycle = errors.New("role inheritance has a cycle")
+
+// Role is the role with the parent roles, the permissions of the parents
+// are inherited
+type Role struct {
+	rbac.Role
+	Parents []string `json:"parents,omitempty"`
+}
+
+type RoleResponse struct {
+	Total int64   `json:"total,omitempty"`
+	Roles []*Role `json:"data,omitempty"`
+}
+
+// CreateRole creates the role inheriting the parents
+func CreateRole(ctx context.Context, r *rbac.Role, parents ...string) error {
 	err := validator.ValidateCreateRole(r)
 	if err != nil {
 		log.Errorf(err, "create role [%s] failed", r.Name)
 		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
 	}
+	err = checkScope(r.Perms)
+	if err != nil {
+		log.Errorf(err, "create role [%s] failed", r.Name)
+		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	parents = uniqueRoles(parents)
+	if err = checkRoleParents(ctx, r.Name, parents); err != nil {
+		log.Errorf(err, "create role [%s] failed", r.Name)
+		return err
+	}
 	quotaErr := quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeRole,
 		util.ParseDomainProject(ctx), "", 1))
 	if quotaErr != nil {
 		return rbac.NewError(rbac.ErrRoleNoQuota, quotaErr.Error())
 	}
-	err = datasource.GetRoleManager().CreateRole(ctx, r)
+	err = datasource.GetRoleManager().CreateRole(ctx, r, parents...)
 	if err == nil {
 		log.Infof("create role [%s] success", r.Name)
 		return nil
@@ -53,6 +80,10 @@ func CreateRole(ctx context.Context, r *rbac.Role) error {
 	if err == datasource.ErrRoleDuplicated {
 		return rbac.NewError(rbac.ErrRoleConflict, err.Error())
 	}
+	if err == datasource.ErrRoleNotExist {
+		// a parent is deleted since the check
+		return rbac.NewError(rbac.ErrRoleNotExist, err.Error())
+	}
 
 	return err
 }
@@ -68,8 +99,18 @@ func GetRole(ctx context.Context, name string) (*rbac.Role, error) {
 	return nil, err
 }
 
-func ListRole(ctx context.Context) ([]*rbac.Role, int64, error) {
-	return datasource.GetRoleManager().ListRole(ctx)
+// GetRoleParents returns the roles inherited by the role directly
+func GetRoleParents(ctx context.Context, name string) ([]string, error) {
+	return datasource.GetRoleManager().GetRoleParents(ctx, name)
+}
+
+// ListRoleParents returns the parents of all the roles inheriting others
+func ListRoleParents(ctx context.Context) (map[string][]string, error) {
+	return datasource.GetRoleManager().ListRoleParents(ctx)
+}
+
+func ListRole(ctx context.Context, opts ...datasource.ListOption) ([]*rbac.Role, int64, error) {
+	return datasource.GetRoleManager().ListRole(ctx, opts...)
 }
 
 func RoleExist(ctx context.Context, name string) (bool, error) {
@@ -94,6 +135,9 @@ func DeleteRole(ctx context.Context, name string) error {
 		if errors.Is(err, datasource.ErrRoleBindingExist) {
 			return rbac.NewError(rbac.ErrRoleIsBound, "")
 		}
+		if errors.Is(err, datasource.ErrRoleInherited) {
+			return rbac.NewError(rbac.ErrRoleIsBound, err.Error())
+		}
 		return err
 	}
 	if !succeed {
@@ -102,10 +146,23 @@ func DeleteRole(ctx context.Context, name string) error {
 	return nil
 }
 
-func EditRole(ctx context.Context, name string, a *rbac.Role) error {
+// EditRole replaces the permissions and the parents of the role, the
+// parents are unchanged if nil, and removed if empty
+func EditRole(ctx context.Context, name string, a *rbac.Role, parents []string) error {
 	if err := illegalRoleCheck(name); err != nil {
 		return err
 	}
+	if err := checkScope(a.Perms); err != nil {
+		log.Errorf(err, "edit role [%s] failed", name)
+		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
+	}
+	if parents != nil {
+		parents = uniqueRoles(parents)
+		if err := checkRoleParents(ctx, name, parents); err != nil {
+			log.Errorf(err, "edit role [%s] failed", name)
+			return err
+		}
+	}
 	exist, err := RoleExist(ctx, name)
 	if err != nil {
 		log.Errorf(err, "check role [%s] exist failed", name)
@@ -128,6 +185,13 @@ func EditRole(ctx context.Context, name string, a *rbac.Role) error {
 		log.Errorf(err, "can not edit role info")
 		return err
 	}
+	if parents != nil {
+		err = datasource.GetRoleManager().SetRoleParents(ctx, name, parents)
+		if err != nil {
+			log.Errorf(err, "can not edit role parents")
+			return err
+		}
+	}
 	log.Infof("role [%s] is edit", oldRole.ID)
 	return nil
 }
@@ -138,3 +202,59 @@ func illegalRoleCheck(role string) error {
 	}
 	return nil
 }
+
+// checkRoleParents checks the parents exist and inheriting them does
+// not make a cycle
+func checkRoleParents(ctx context.Context, name string, parents []string) error {
+	if len(parents) == 0 {
+		return nil
+	}
+	for _, p := range parents {
+		exist, err := RoleExist(ctx, p)
+		if err != nil {
+			log.Errorf(err, "check role [%s] exist failed", p)
+			return err
+		}
+		if !exist {
+			return rbac.NewError(rbac.ErrRoleNotExist, fmt.Sprintf("parent role [%s] not exist", p))
+		}
+	}
+	all, err := ListRoleParents(ctx)
+	if err != nil {
+		log.Error("list role parents failed", err)
+		return err
+	}
+	all[name] = parents
+	if cycle := findRoleCycle(all, name, []string{name}); len(cycle) > 0 {
+		return discovery.NewError(discovery.ErrInvalidParams,
+			fmt.Sprintf("%s: %s", ErrRoleCycle.Error(), strings.Join(cycle, " -> ")))
+	}
+	return nil
+}
+
+// findRoleCycle returns the inheritance path from the role back to the
+// first role in the path, empty if no cycle
+func findRoleCycle(all map[string][]string, role string, path []string) []string {
+	for _, p := range all[role] {
+		if p == path[0] {
+			return append(path, p)
+		}
+		if util.SliceHave(path, p) {
+			continue
+		}
+		if cycle := findRoleCycle(all, p, append(path, p)); len(cycle) > 0 {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func uniqueRoles(roles []string) []string {
+	unique := make([]string, 0, len(roles))
+	for _, r := range roles {
+		if !util.SliceHave(unique, r) {
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
@@ -23,7 +23,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 	"github.com/go-chassis/cari/rbac"
 	"github.com/stretchr/testify/assert"
@@ -80,7 +82,7 @@ func TestGetRole(t *testing.T) {
 func TestEditRole(t *testing.T) {
 	t.Run("edit no exist role, should return: "+rbac.NewError(rbac.ErrRoleNotExist, "").Error(), func(t *testing.T) {
 		r := newRole("TestEditRole_editNoExistRole")
-		err := rbacsvc.EditRole(context.TODO(), r.Name, r)
+		err := rbacsvc.EditRole(context.TODO(), r.Name, r, nil)
 		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrRoleNotExist))
 	})
 	t.Run("edit role, should success", func(t *testing.T) {
@@ -108,7 +110,7 @@ func TestEditRole(t *testing.T) {
 				Verbs: []string{"*"},
 			},
 		}
-		err = rbacsvc.EditRole(context.TODO(), r.Name, r)
+		err = rbacsvc.EditRole(context.TODO(), r.Name, r, nil)
 		assert.Nil(t, err)
 
 		resp, err := rbacsvc.GetRole(context.TODO(), r.Name)
@@ -117,7 +119,7 @@ func TestEditRole(t *testing.T) {
 	})
 	t.Run("edit build in role, should return: "+rbac.NewError(rbac.ErrForbidOperateBuildInRole, "").Error(), func(t *testing.T) {
 		for _, name := range []string{rbac.RoleDeveloper, rbac.RoleDeveloper} {
-			err := rbacsvc.EditRole(context.TODO(), name, newRole(""))
+			err := rbacsvc.EditRole(context.TODO(), name, newRole(""), nil)
 			assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrForbidOperateBuildInRole))
 		}
 	})
@@ -164,3 +166,88 @@ func TestRoleExistt(t *testing.T) {
 		assert.False(t, exist)
 	})
 }
+
+func TestRoleInheritance(t *testing.T) {
+	ctx := context.TODO()
+	base := &rbac.Role{
+		Name: "TestRoleInheritance_base",
+		Perms: []*rbac.Permission{
+			{Resources: []*rbac.Resource{{Type: rbacsvc.ResourceSchema}}, Verbs: []string{"get"}},
+		},
+	}
+	team := &rbac.Role{
+		Name: "TestRoleInheritance_team",
+		Perms: []*rbac.Permission{
+			{Resources: []*rbac.Resource{{Type: rbacsvc.ResourceService}}, Verbs: []string{"create"}},
+		},
+	}
+	member := newRole("TestRoleInheritance_member")
+	member.Perms = nil
+	assert.NoError(t, rbacsvc.CreateRole(ctx, base))
+	defer rbacsvc.DeleteRole(ctx, base.Name)
+
+	t.Run("create role inheriting a not exist role, should fail", func(t *testing.T) {
+		err := rbacsvc.CreateRole(ctx, team, "TestRoleInheritance_not_exist")
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrRoleNotExist))
+	})
+	t.Run("create roles inheriting others, should resolve the merged permissions", func(t *testing.T) {
+		assert.NoError(t, rbacsvc.CreateRole(ctx, team, base.Name, base.Name))
+		defer rbacsvc.DeleteRole(ctx, team.Name)
+		assert.NoError(t, rbacsvc.CreateRole(ctx, member, team.Name))
+		defer rbacsvc.DeleteRole(ctx, member.Name)
+
+		parents, err := rbacsvc.GetRoleParents(ctx, team.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{base.Name}, parents)
+
+		for _, target := range []*auth.ResourceScope{
+			{Type: rbacsvc.ResourceSchema, Verb: "get"},
+			{Type: rbacsvc.ResourceService, Verb: "create"},
+		} {
+			allow, _, err := rbacsvc.Allow(ctx, "", []string{member.Name}, target)
+			assert.NoError(t, err)
+			assert.True(t, allow)
+		}
+		allow, _, err := rbacsvc.Allow(ctx, "", []string{team.Name}, &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "delete"})
+		assert.NoError(t, err)
+		assert.False(t, allow)
+
+		t.Run("make a cycle, should fail", func(t *testing.T) {
+			err := rbacsvc.EditRole(ctx, base.Name, base, []string{member.Name})
+			assert.Error(t, err)
+			assert.Equal(t, discovery.ErrInvalidParams, err.(*errsvc.Error).Code)
+			assert.Contains(t, err.Error(), base.Name+" -> "+member.Name+" -> "+team.Name+" -> "+base.Name)
+
+			err = rbacsvc.EditRole(ctx, team.Name, team, []string{team.Name})
+			assert.Error(t, err)
+		})
+		t.Run("delete an inherited role, should fail", func(t *testing.T) {
+			err := rbacsvc.DeleteRole(ctx, team.Name)
+			assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrRoleIsBound))
+		})
+		t.Run("edit without the parents, should keep them", func(t *testing.T) {
+			assert.NoError(t, rbacsvc.EditRole(ctx, member.Name, member, nil))
+			parents, err := rbacsvc.GetRoleParents(ctx, member.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{team.Name}, parents)
+		})
+		t.Run("remove the parents, should not inherit the permissions", func(t *testing.T) {
+			assert.NoError(t, rbacsvc.EditRole(ctx, member.Name, member, []string{}))
+			allow, _, err := rbacsvc.Allow(ctx, "", []string{member.Name}, &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "create"})
+			assert.NoError(t, err)
+			assert.False(t, allow)
+
+			all, err := rbacsvc.ListRoleParents(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{base.Name}, all[team.Name])
+			_, ok := all[member.Name]
+			assert.False(t, ok)
+		})
+	})
+	t.Run("delete the roles inheriting the role, should be able to delete it", func(t *testing.T) {
+		assert.NoError(t, rbacsvc.DeleteRole(ctx, base.Name))
+		parents, err := rbacsvc.GetRoleParents(ctx, team.Name)
+		assert.NoError(t, err)
+		assert.Empty(t, parents)
+	})
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/go-chassis/cari/rbac"
+)
+
+const (
+	// LabelDomain and LabelProject are the reserved resource labels to bind
+	// the resource permission to domains and projects, the values are the
+	// patterns of path.Match, e.g. "*" or "prod-*". A resource without them
+	// is permitted in all the domains and projects
+	LabelDomain  = "domain"
+	LabelProject = "project"
+)
+
+var ErrInvalidScope = errors.New("invalid domain or project pattern")
+
+// ScopePerms returns the permissions in the scope of the domain/project, the
+// scope labels are removed from the resources of the result, so the rest
+// labels can be filtered as usual. The project is empty for the APIs not
+// belonging to a project, only the resources unbound or bound to "*"
+// projects are permitted
+func ScopePerms(perms []*rbac.Permission, domain, project string) []*rbac.Permission {
+	scoped := make([]*rbac.Permission, 0, len(perms))
+	for _, perm := range perms {
+		var resources []*rbac.Resource
+		for _, resource := range perm.Resources {
+			if !matchScope(resource.Labels[LabelDomain], domain) ||
+				!matchScope(resource.Labels[LabelProject], project) {
+				continue
+			}
+			resources = append(resources, unscoped(resource))
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		scoped = append(scoped, &rbac.Permission{Resources: resources, Verbs: perm.Verbs})
+	}
+	return scoped
+}
+
+func matchScope(pattern, value string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func unscoped(resource *rbac.Resource) *rbac.Resource {
+	_, hasDomain := resource.Labels[LabelDomain]
+	_, hasProject := resource.Labels[LabelProject]
+	if !hasDomain && !hasProject {
+		return resource
+	}
+	labels := make(map[string]string, len(resource.Labels))
+	for k, v := range resource.Labels {
+		if k != LabelDomain && k != LabelProject {
+			labels[k] = v
+		}
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+	return &rbac.Resource{Type: resource.Type, Labels: labels}
+}
+
+func checkScope(perms []*rbac.Permission) error {
+	for _, perm := range perms {
+		for _, resource := range perm.Resources {
+			for _, k := range []string{LabelDomain, LabelProject} {
+				pattern, ok := resource.Labels[k]
+				if !ok {
+					continue
+				}
+				if _, err := path.Match(pattern, ""); len(pattern) == 0 || err != nil {
+					return fmt.Errorf("%w, %s: '%s'", ErrInvalidScope, k, pattern)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+)
+
+var ErrInvalidLabels = errors.New("labels must be in the format of key:value,key:value")
+
+// Permissions are the effective permissions of the caller in the domain
+// of the request and the project
+type Permissions struct {
+	Account string             `json:"account"`
+	Roles   []string           `json:"roles"`
+	Domain  string             `json:"domain"`
+	Project string             `json:"project,omitempty"`
+	Perms   []*rbac.Permission `json:"perms"`
+}
+
+// Decision is the result of checking one resource verb of the caller
+type Decision struct {
+	Allowed  bool   `json:"allowed"`
+	Resource string `json:"resource"`
+	Verb     string `json:"verb"`
+	// MatchedLabels are the labels of the permissions matching the
+	// resource, empty means the resource is permitted regardless of labels
+	MatchedLabels []map[string]string `json:"matchedLabels,omitempty"`
+	Reason        string              `json:"reason,omitempty"`
+}
+
+// SelfPermissions returns the permissions of the roles in the request token
+func SelfPermissions(ctx context.Context, project string) (*Permissions, error) {
+	a, err := AccountFromContext(ctx)
+	if err != nil {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, err.Error())
+	}
+	perms, err := getPermsByRoles(ctx, a.Roles)
+	if err != nil {
+		return nil, err
+	}
+	domain := util.ParseDomain(ctx)
+	return &Permissions{
+		Account: a.Name,
+		Roles:   a.Roles,
+		Domain:  domain,
+		Project: project,
+		Perms:   ScopePerms(perms, domain, project),
+	}, nil
+}
+
+// CanI checks whether the roles in the request token can operate the
+// resource, it evaluates the roles as the API authentication does
+func CanI(ctx context.Context, project string, target *auth.ResourceScope) (*Decision, error) {
+	a, err := AccountFromContext(ctx)
+	if err != nil {
+		return nil, rbac.NewError(rbac.ErrUnauthorized, err.Error())
+	}
+	d := &Decision{Resource: target.Type, Verb: target.Verb}
+	for _, r := range a.Roles {
+		if r == rbac.RoleAdmin {
+			d.Allowed, d.Reason = true, "admin role owns all permissions"
+			return d, nil
+		}
+	}
+	d.Allowed, d.MatchedLabels, err = Allow(ctx, project, a.Roles, target)
+	if err != nil {
+		return nil, err
+	}
+	if !d.Allowed {
+		d.Reason, err = denyReason(ctx, project, a.Roles, target)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func denyReason(ctx context.Context, project string, roles []string, target *auth.ResourceScope) (string, error) {
+	perms, err := getPermsByRoles(ctx, roles)
+	if err != nil {
+		return "", err
+	}
+	domain := util.ParseDomain(ctx)
+	perms = ScopePerms(perms, domain, project)
+	if len(perms) == 0 {
+		return fmt.Sprintf("roles %v have no permissions in %s/%s", roles, domain, project), nil
+	}
+	allow, labels := GetLabel(perms, target.Type, target.Verb)
+	if !allow {
+		return fmt.Sprintf("roles %v can not %s %s", roles, target.Verb, target.Type), nil
+	}
+	return fmt.Sprintf("labels %v match none of the permitted labels %v", target.Labels, labels), nil
+}
+
+// ParseLabels parses the labels of the query, e.g. appId:a,serviceName:b,
+// each value is one label set of the resource
+func ParseLabels(values []string) ([]map[string]string, error) {
+	var labels []map[string]string
+	for _, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		m := make(map[string]string)
+		for _, kv := range strings.Split(v, ",") {
+			i := strings.Index(kv, ":")
+			if i <= 0 {
+				return nil, ErrInvalidLabels
+			}
+			m[strings.TrimSpace(kv[:i])] = strings.TrimSpace(kv[i+1:])
+		}
+		labels = append(labels, m)
+	}
+	return labels, nil
+}
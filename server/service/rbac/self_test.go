@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestCanI(t *testing.T) {
+	ctx := util.SetDomain(context.Background(), "default")
+	role := &rbac.Role{
+		Name: "TestCanI_role",
+		Perms: []*rbac.Permission{
+			{
+				Resources: []*rbac.Resource{
+					{Type: rbacsvc.ResourceService, Labels: map[string]string{"appId": "a", rbacsvc.LabelProject: "p1"}},
+				},
+				Verbs: []string{"get", "create"},
+			},
+		},
+	}
+	assert.NoError(t, rbacsvc.CreateRole(ctx, role))
+	defer rbacsvc.DeleteRole(ctx, role.Name)
+	ctx = context.WithValue(ctx, rbacsvc.CtxRequestClaims, map[string]interface{}{
+		rbac.ClaimsUser:  "TestCanI_account",
+		rbac.ClaimsRoles: []interface{}{role.Name},
+	})
+
+	t.Run("get self permissions, should return the scoped permissions", func(t *testing.T) {
+		perms, err := rbacsvc.SelfPermissions(ctx, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, "TestCanI_account", perms.Account)
+		assert.Equal(t, []string{role.Name}, perms.Roles)
+		assert.Equal(t, 1, len(perms.Perms))
+		assert.Equal(t, map[string]string{"appId": "a"}, perms.Perms[0].Resources[0].Labels)
+
+		perms, err = rbacsvc.SelfPermissions(ctx, "p2")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(perms.Perms))
+	})
+	t.Run("can create the service with the permitted labels, should allow", func(t *testing.T) {
+		d, err := rbacsvc.CanI(ctx, "p1", &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "create",
+			Labels: []map[string]string{{"appId": "a"}}})
+		assert.NoError(t, err)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, []map[string]string{{"appId": "a"}}, d.MatchedLabels)
+	})
+	t.Run("can do something not permitted, should deny with the reason", func(t *testing.T) {
+		d, err := rbacsvc.CanI(ctx, "p1", &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "delete"})
+		assert.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.Contains(t, d.Reason, "can not delete service")
+
+		d, err = rbacsvc.CanI(ctx, "p1", &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "get",
+			Labels: []map[string]string{{"appId": "b"}}})
+		assert.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.Contains(t, d.Reason, "match none of the permitted labels")
+
+		d, err = rbacsvc.CanI(ctx, "p2", &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "get"})
+		assert.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.Contains(t, d.Reason, "no permissions in default/p2")
+	})
+	t.Run("admin, should allow", func(t *testing.T) {
+		adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, map[string]interface{}{
+			rbac.ClaimsUser:  "root",
+			rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+		})
+		d, err := rbacsvc.CanI(adminCtx, "", &auth.ResourceScope{Type: rbacsvc.ResourceAccount, Verb: "delete"})
+		assert.NoError(t, err)
+		assert.True(t, d.Allowed)
+	})
+	t.Run("without token, should fail", func(t *testing.T) {
+		_, err := rbacsvc.CanI(context.Background(), "", &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "get"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := rbacsvc.ParseLabels([]string{"appId:a, serviceName:b", "", "version:1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"appId": "a", "serviceName": "b"}, {"version": "1.0.0"}}, labels)
+
+	_, err = rbacsvc.ParseLabels([]string{"appId"})
+	assert.Equal(t, rbacsvc.ErrInvalidLabels, err)
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/go-chassis/go-chassis/v2/security/token"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const (
+	// ClaimsGeneration is the token generation of the account when the token
+	// is issued, the tokens of the older generations are revoked
+	ClaimsGeneration = "generation"
+	// ClaimsTokenID makes the tokens unique, a token issued after logout
+	// must not be the same as the revoked one
+	ClaimsTokenID = "jti"
+	// ClaimsPasswordExpired marks the token issued to the account with an
+	// expired password, it can only be used to change the password
+	ClaimsPasswordExpired = "pwdExpired"
+)
+
+// RevokeToken revokes the token until it expires, it is used to log out
+func RevokeToken(ctx context.Context, tokenStr string, claims map[string]interface{}) error {
+	// the token signed without expiry is revoked forever
+	exp, _ := claims[token.JWTClaimsExp].(float64)
+	user, _ := claims[rbac.ClaimsUser].(string)
+	err := datasource.GetTokenManager().RevokeToken(ctx, &datasource.RevokedToken{
+		Hash:     hashSecret(tokenStr),
+		Account:  user,
+		ExpireAt: int64(exp),
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("revoke token of account [%s] failed", user), err)
+		return err
+	}
+	log.Info(fmt.Sprintf("token of account [%s] is revoked", user))
+	return nil
+}
+
+// Logout verifies the token and revokes it, the API keys can not log out,
+// they are revoked through the key API
+func Logout(ctx context.Context, tokenStr string) error {
+	if IsAPIKey(tokenStr) {
+		return discovery.NewError(discovery.ErrInvalidParams, "api key can not log out")
+	}
+	claims, err := authr.Authenticate(ctx, tokenStr)
+	if err != nil {
+		return err
+	}
+	m, ok := claims.(map[string]interface{})
+	if !ok {
+		return rbac.ErrConvert
+	}
+	return RevokeToken(ctx, tokenStr, m)
+}
+
+// RevokeAccountTokens revokes all the tokens issued to the account, it is
+// called when the password or the roles of the account change
+func RevokeAccountTokens(ctx context.Context, name string) error {
+	_, err := datasource.GetTokenManager().IncGeneration(ctx, name)
+	if err != nil {
+		log.Error(fmt.Sprintf("revoke tokens of account [%s] failed", name), err)
+		return err
+	}
+	log.Info(fmt.Sprintf("tokens of account [%s] are revoked", name))
+	return nil
+}
+
+func tokenGeneration(ctx context.Context, name string) (int64, error) {
+	return datasource.GetTokenManager().GetGeneration(ctx, name)
+}
+
+func checkTokenRevoked(ctx context.Context, tokenStr string, claims map[string]interface{}) error {
+	user, _ := claims[rbac.ClaimsUser].(string)
+	generation, err := tokenGeneration(ctx, user)
+	if err != nil {
+		return err
+	}
+	// the tokens issued before revocation was supported have no generation
+	issued, _ := claims[ClaimsGeneration].(float64)
+	if int64(issued) < generation {
+		return rbac.NewError(rbac.ErrUnauthorized, "token is revoked")
+	}
+	return checkTokenHashRevoked(ctx, tokenStr)
+}
+
+// checkTokenHashRevoked checks the token is not logged out, it is used for
+// the external tokens which have no generation
+func checkTokenHashRevoked(ctx context.Context, tokenStr string) error {
+	revoked, err := datasource.GetTokenManager().TokenRevoked(ctx, hashSecret(tokenStr))
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return rbac.NewError(rbac.ErrUnauthorized, "token is revoked")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/stretchr/testify/assert"
+
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	name := "TestRevokeToken_account"
+	a := newAccount(name)
+	a.Roles = []string{rbac.RoleDeveloper}
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, map[string]interface{}{
+		rbac.ClaimsUser:  "root",
+		rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+	})
+	login := func(pwd string) string {
+		token, err := authr.Login(ctx, name, pwd)
+		assert.NoError(t, err)
+		_, err = authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		return token
+	}
+	assertRevoked := func(token string) {
+		_, err := authr.Authenticate(ctx, token)
+		assert.Error(t, err)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrUnauthorized))
+	}
+
+	t.Run("logout, the token should be revoked", func(t *testing.T) {
+		token := login(testPwd0)
+		assert.NoError(t, rbacsvc.Logout(ctx, token))
+		assertRevoked(token)
+		login(testPwd0)
+	})
+	t.Run("logout with invalid token, should fail", func(t *testing.T) {
+		assert.Error(t, rbacsvc.Logout(ctx, "invalid_token"))
+	})
+	t.Run("change password, the tokens should be revoked", func(t *testing.T) {
+		token := login(testPwd0)
+		err := rbacsvc.ChangePassword(adminCtx, &rbac.Account{Name: name, Password: testPwd1})
+		assert.NoError(t, err)
+		assertRevoked(token)
+		login(testPwd1)
+	})
+	t.Run("change roles, the tokens should be revoked", func(t *testing.T) {
+		token := login(testPwd1)
+		err := rbacsvc.UpdateAccount(adminCtx, name, &rbac.Account{Status: "active", Roles: []string{rbac.RoleDeveloper}})
+		assert.NoError(t, err)
+		_, err = authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+
+		err = rbacsvc.UpdateAccount(adminCtx, name, &rbac.Account{Roles: []string{rbac.RoleAdmin}})
+		assert.NoError(t, err)
+		assertRevoked(token)
+		login(testPwd1)
+	})
+	t.Run("revoke account tokens, the tokens should be revoked", func(t *testing.T) {
+		token := login(testPwd1)
+		assert.NoError(t, rbacsvc.RevokeAccountTokens(ctx, name))
+		assertRevoked(token)
+	})
+}
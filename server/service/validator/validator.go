@@ -37,7 +37,6 @@ var accountLoginValidator = &validate.Validator{}
 func init() {
 	createAccountValidator.AddRule("Name", &validate.Rule{Max: 64, Regexp: nameRegex})
 	createAccountValidator.AddRule("Roles", &validate.Rule{Min: 1, Max: 5, Regexp: nameRegex})
-	createAccountValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
 	createAccountValidator.AddRule("Status", &validate.Rule{Regexp: accountStatusRegex})
 
 	updateAccountValidator.AddRule("Roles", createAccountValidator.GetRule("Roles"))
@@ -45,7 +44,6 @@ func init() {
 
 	createRoleValidator.AddRule("Name", &validate.Rule{Max: 64, Regexp: nameRegex})
 
-	changePWDValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
 	changePWDValidator.AddRule("Name", &validate.Rule{Regexp: nameRegex})
 
 	accountLoginValidator.AddRule("TokenExpirationTime", &validate.Rule{Regexp: &validate.TokenExpirationTimeChecker{}})
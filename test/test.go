@@ -19,6 +19,9 @@
 package test
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	_ "github.com/apache/servicecomb-service-center/server/init"
@@ -27,10 +30,13 @@ import (
 	_ "github.com/apache/servicecomb-service-center/server/bootstrap"
 
 	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/go-chassis/go-archaius"
 )
 
+var localPath = filepath.Join(os.TempDir(), fmt.Sprintf("sc-test-%d.db", os.Getpid()))
+
 func init() {
 	t := archaius.Get("TEST_MODE")
 	if t == nil {
@@ -41,6 +47,11 @@ func init() {
 		archaius.Set("registry.cache.mode", 0)
 		archaius.Set("discovery.kind", "etcd")
 		archaius.Set("registry.kind", "etcd")
+	} else if t == "local" {
+		archaius.Set("registry.cache.mode", 0)
+		archaius.Set("discovery.kind", "etcd")
+		archaius.Set("registry.kind", "local")
+		archaius.Set("registry.local.path", localPath)
 	} else {
 		archaius.Set("registry.heartbeat.kind", "checker")
 	}
@@ -48,5 +59,12 @@ func init() {
 		Kind:                datasource.Kind(t.(string)),
 		ReleaseAccountAfter: 3 * time.Second,
 	})
+	if t == "local" {
+		// the registry keeps the file opened, remove it now so that nothing
+		// is left after the test process exits
+		if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+			log.Error(fmt.Sprintf("remove test registry file %s failed", localPath), err)
+		}
+	}
 	core.ServiceAPI = disco.AssembleResources()
 }